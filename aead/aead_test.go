@@ -0,0 +1,73 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+// aeads lists every AEAD of the package.
+var aeads = []struct {
+	name string
+	new  func(key []byte) godium.AEAD
+}{
+	{"aes256gcm", NewAes256Gcm},
+	{"aes256gcmsiv", NewAes256GcmSiv},
+	{"chacha20poly1305", NewChacha20Poly1305},
+	{"chacha20poly1305_ietf", NewChacha20Poly1305Ietf},
+	{"xchacha20poly1305_ietf", NewXChacha20Poly1305Ietf},
+	{"xchacha20blake2bsiv", NewXChacha20Blake2bSiv},
+}
+
+func TestAEADConcurrent(t *testing.T) {
+	for _, v := range aeads {
+		testAEADConcurrent(t, v.name, v.new)
+	}
+}
+
+// testAEADConcurrent shares one instance of the AEAD between goroutines.
+func testAEADConcurrent(t *testing.T, name string, new func(key []byte) godium.AEAD) {
+	const goroutines, messages = 8, 50
+
+	// every goroutine compares with a private instance under the same key
+	skipNonceReuse(t)
+
+	a := new(testBytes(0, 32))
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			// every goroutine seals different messages under its own
+			// nonces, and compares with a private instance.
+			own := new(testBytes(0, 32))
+			nonce := testBytes(byte(g), a.NonceSize())
+			ad := testPattern(g)
+			for i := 0; i < messages; i++ {
+				nonce[0] = byte(i)
+				plain := testPattern(g*97 + i)
+
+				cipher := a.Seal(nil, nonce, plain, ad)
+				if expect := own.Seal(nil, nonce, plain, ad); !bytes.Equal(expect, cipher) {
+					t.Error("expected result did not match computed", name, g, i)
+					return
+				}
+				if got, err := a.Open(nil, nonce, cipher, ad); err != nil || !bytes.Equal(plain, got) {
+					t.Error("expected result did not match computed", name, g, i, err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
@@ -11,6 +11,8 @@ import (
 	"crypto/cipher"
 
 	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/noncecheck"
 )
 
 const (
@@ -20,35 +22,85 @@ const (
 	Aes256Gcm_ABytes    = 16
 )
 
+// aes256gcm wraps the AES-GCM of the standard library, which has no detached
+// API. The detached functions copy through a temporary buffer.
 type aes256gcm struct {
 	cipher.AEAD
-	key []byte
+	key godium.Key
 }
 
 // NewAes256Gcm
 func NewAes256Gcm(key []byte) (aesImpl godium.AEAD) {
-	block, _ := aes.NewCipher(key)
-	impl, _ := cipher.NewGCMWithNonceSize(block, Aes256Gcm_NPubBytes)
-	aesImpl = &aes256gcm{
-		AEAD: impl,
-		key:  key,
+	a := &aes256gcm{
+		key: internal.Copy(key, Aes256Gcm_KeyBytes),
 	}
+	a.init()
+	aesImpl = a
 	return
 }
 
+// init creates the AES-GCM instance for the key.
+func (a *aes256gcm) init() {
+	block, _ := aes.NewCipher(a.key)
+	a.AEAD, _ = cipher.NewGCMWithNonceSize(block, Aes256Gcm_NPubBytes)
+}
+
 // SealDetached
 func (a *aes256gcm) SealDetached(dst, dstMac, nonce, plain, ad []byte) (cipher, mac []byte) {
-	panic("aes256gcm: SealDetached not supported")
+	mlen := len(plain)
+	cipher = internal.AllocDst(dst, uint64(mlen))
+	mac = internal.AllocDst(dstMac, Aes256Gcm_ABytes)
+
+	noncecheck.Record("aead aes256gcm", a.key, nonce)
+	sealed := a.AEAD.Seal(nil, nonce, plain, ad)
+	copy(cipher, sealed[:mlen])
+	copy(mac, sealed[mlen:])
+	godium.Wipe(sealed)
+	return
 }
 
 // OpenDetached
 func (a *aes256gcm) OpenDetached(dst, nonce, cipher, mac, ad []byte) (plain []byte, err error) {
-	panic("aes256gcm: SealDetached not supported")
+	plain = internal.AllocDst(dst, uint64(len(cipher)))
+
+	sealed := make([]byte, 0, len(cipher)+len(mac))
+	sealed = append(append(sealed, cipher...), mac...)
+
+	_, err = a.AEAD.Open(plain[:0], nonce, sealed, ad)
+	if err != nil {
+		err = godium.ErrForgedOrCorrupted
+	}
+	return
+}
+
+// Seal
+func (a *aes256gcm) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
+	cipher = internal.AllocDst(dst, uint64(len(plain))+Aes256Gcm_ABytes)
+
+	noncecheck.Record("aead aes256gcm", a.key, nonce)
+	_ = a.AEAD.Seal(cipher[:0], nonce, plain, ad)
+	return
+}
+
+// Open
+func (a *aes256gcm) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
+	if len(cipher) < Aes256Gcm_ABytes {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	plain = internal.AllocDst(dst, uint64(len(cipher)-Aes256Gcm_ABytes))
+	if _, err = a.AEAD.Open(plain[:0], nonce, cipher, ad); err != nil {
+		err = godium.ErrForgedOrCorrupted
+	}
+	return
 }
 
-// Wipe
+// Wipe erases the key, and replaces the key schedule with that of the erased
+// key.
 func (a *aes256gcm) Wipe() {
 	godium.Wipe(a.key)
+	a.init()
 }
 
 func (a *aes256gcm) Overhead() int  { return Aes256Gcm_ABytes }
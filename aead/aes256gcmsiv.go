@@ -0,0 +1,220 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+)
+
+const (
+	Aes256GcmSiv_KeyBytes  = 32
+	Aes256GcmSiv_NSecBytes = 0
+	Aes256GcmSiv_NPubBytes = 12
+	Aes256GcmSiv_ABytes    = 16
+
+	// Aes256GcmSiv_MessageBytesMax is the longest message, and the longest
+	// additional data, RFC 8452 allows.
+	Aes256GcmSiv_MessageBytesMax = 1 << 36
+)
+
+// aes256gcmsiv implements the nonce misuse-resistant AES-256-GCM-SIV of
+// RFC 8452. The tag is computed over the message first, and used as the
+// initial counter to encrypt it, so reusing a nonce only reveals whether the
+// same message was sealed twice. Keys for POLYVAL and the encryption are
+// derived for every nonce.
+type aes256gcmsiv struct {
+	godium.Key
+	block cipher.Block
+}
+
+// NewAes256GcmSiv
+func NewAes256GcmSiv(key []byte) (impl godium.AEAD) {
+	k := internal.Copy(key, Aes256GcmSiv_KeyBytes)
+	block, _ := aes.NewCipher(k)
+
+	impl = &aes256gcmsiv{
+		Key:   k,
+		block: block,
+	}
+	return
+}
+
+// Wipe erases the key, and replaces the key schedule with that of the erased
+// key.
+func (a *aes256gcmsiv) Wipe() {
+	godium.Wipe(a.Key)
+	a.block, _ = aes.NewCipher(a.Key)
+}
+
+// deriveKeys derives the POLYVAL key and the AES-256 encryption key for the
+// nonce, see RFC 8452, section 4.
+func (a *aes256gcmsiv) deriveKeys(authKey *[16]byte, encKey *[32]byte, nonce []byte) {
+	var in, out [aes.BlockSize]byte
+
+	copy(in[4:], nonce[:Aes256GcmSiv_NPubBytes])
+	for i := uint32(0); i < 6; i++ {
+		binary.LittleEndian.PutUint32(in[:4], i)
+		a.block.Encrypt(out[:], in[:])
+
+		if i < 2 {
+			copy(authKey[8*i:], out[:8])
+		} else {
+			copy(encKey[8*(i-2):], out[:8])
+		}
+	}
+
+	godium.Wipe(out[:])
+}
+
+// tag computes the tag of plain and ad under the derived keys.
+func (a *aes256gcmsiv) tag(tag *[16]byte, authKey []byte, enc cipher.Block, nonce, plain, ad []byte) {
+	var p polyval
+	var lengths [16]byte
+
+	binary.LittleEndian.PutUint64(lengths[0:], uint64(len(ad))*8)
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(plain))*8)
+
+	p.init(authKey)
+	p.update(ad)
+	p.update(plain)
+	p.update(lengths[:])
+	p.sum(tag)
+	p.wipe()
+
+	for i, v := range nonce[:Aes256GcmSiv_NPubBytes] {
+		tag[i] ^= v
+	}
+	tag[15] &= 0x7f
+	enc.Encrypt(tag[:], tag[:])
+}
+
+// xorCtr xors src with the keystream of AES in counter mode into dst. The
+// initial counter is the tag with its highest bit set, and only the first 32
+// bits are incremented, as a little endian integer.
+func (a *aes256gcmsiv) xorCtr(dst, src []byte, enc cipher.Block, tag *[16]byte) {
+	var ctr, ks [aes.BlockSize]byte
+
+	ctr = *tag
+	ctr[15] |= 0x80
+	n := binary.LittleEndian.Uint32(ctr[:4])
+
+	for len(src) > 0 {
+		enc.Encrypt(ks[:], ctr[:])
+		m := len(src)
+		if m > len(ks) {
+			m = len(ks)
+		}
+		for i, v := range src[:m] {
+			dst[i] = v ^ ks[i]
+		}
+		dst, src = dst[m:], src[m:]
+
+		n++
+		binary.LittleEndian.PutUint32(ctr[:4], n)
+	}
+
+	godium.Wipe(ks[:])
+}
+
+// checkLengths panics if plain or ad is too long.
+func (a *aes256gcmsiv) checkLengths(plain, ad []byte) {
+	if uint64(len(plain)) > Aes256GcmSiv_MessageBytesMax || uint64(len(ad)) > Aes256GcmSiv_MessageBytesMax {
+		panic("aead: message too long for AES-256-GCM-SIV")
+	}
+}
+
+// SealDetached
+func (a *aes256gcmsiv) SealDetached(dst, dstMac, nonce, plain, ad []byte) (cipher, mac []byte) {
+	var authKey [16]byte
+	var encKey [32]byte
+	var tag [16]byte
+
+	a.checkLengths(plain, ad)
+
+	cipher = internal.AllocDst(dst, uint64(len(plain)))
+	mac = internal.AllocDst(dstMac, Aes256GcmSiv_ABytes)
+
+	a.deriveKeys(&authKey, &encKey, nonce)
+	enc, _ := aes.NewCipher(encKey[:])
+
+	a.tag(&tag, authKey[:], enc, nonce, plain, ad)
+	a.xorCtr(cipher, plain, enc, &tag)
+	copy(mac, tag[:])
+
+	godium.Wipe(authKey[:])
+	godium.Wipe(encKey[:])
+	return
+}
+
+// Seal
+func (a *aes256gcmsiv) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
+	mlen := uint64(len(plain))
+	cipher = internal.AllocDst(dst, mlen+Aes256GcmSiv_ABytes)
+
+	// call with slices of len == 0, pointing to the right parts of cipher.
+	_, _ = a.SealDetached(cipher[0:0], cipher[mlen:mlen], nonce, plain, ad)
+	return
+}
+
+// OpenDetached decrypts cipher before checking the tag of the plaintext. The
+// output is erased if the tag is invalid.
+func (a *aes256gcmsiv) OpenDetached(dst, nonce, cipher, mac, ad []byte) (plain []byte, err error) {
+	var authKey [16]byte
+	var encKey [32]byte
+	var tag, expect [16]byte
+
+	a.checkLengths(cipher, ad)
+	plain = internal.AllocDst(dst, uint64(len(cipher)))
+
+	if len(mac) != Aes256GcmSiv_ABytes {
+		err = godium.ErrForgedOrCorrupted
+		return
+	}
+	copy(tag[:], mac)
+
+	a.deriveKeys(&authKey, &encKey, nonce)
+	enc, _ := aes.NewCipher(encKey[:])
+
+	a.xorCtr(plain, cipher, enc, &tag)
+	a.tag(&expect, authKey[:], enc, nonce, plain, ad)
+
+	if subtle.ConstantTimeCompare(expect[:], tag[:]) != 1 {
+		godium.Wipe(plain)
+		err = godium.ErrForgedOrCorrupted
+	}
+
+	godium.Wipe(authKey[:])
+	godium.Wipe(encKey[:])
+	return
+}
+
+// Open
+func (a *aes256gcmsiv) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
+	if len(cipher) < Aes256GcmSiv_ABytes {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	mlen := uint64(len(cipher) - Aes256GcmSiv_ABytes)
+	plain = internal.AllocDst(dst, mlen)
+
+	_, err = a.OpenDetached(plain[:0], nonce, cipher[:mlen], cipher[mlen:], ad)
+	return
+}
+
+func (a *aes256gcmsiv) Overhead() int  { return Aes256GcmSiv_ABytes }
+func (a *aes256gcmsiv) NonceSize() int { return Aes256GcmSiv_NPubBytes }
+func (a *aes256gcmsiv) KeyBytes() int  { return Aes256GcmSiv_KeyBytes }
+func (a *aes256gcmsiv) NSecBytes() int { return Aes256GcmSiv_NSecBytes }
+func (a *aes256gcmsiv) NPubBytes() int { return Aes256GcmSiv_NPubBytes }
+func (a *aes256gcmsiv) ABytes() int    { return Aes256GcmSiv_ABytes }
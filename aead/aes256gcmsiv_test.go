@@ -0,0 +1,304 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+func TestAes256GcmSivRFC8452(t *testing.T) {
+	// RFC 8452, appendix C.2 and the counter wrap tests of appendix C.3
+	vectors := []struct {
+		key, nonce, ad, plain, cipher string
+	}{
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "", "",
+			"07f5f4169bbf55a8400cd47ea6fd400f"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "",
+			"0100000000000000",
+			"c2ef328e5c71c83b843122130f7364b761e0b97427e3df28"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "",
+			"010000000000000000000000",
+			"9aab2aeb3faa0a34aea8e2b18ca50da9ae6559e48fd10f6e5c9ca17e"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "",
+			"01000000000000000000000000000000",
+			"85a01b63025ba19b7fd3ddfc033b3e76c9eac6fa700942702e90862383c6c366"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "",
+			"0100000000000000000000000000000002000000000000000000000000000000",
+			"4a6a9db4c8c6549201b9edb53006cba821ec9cf850948a7c86c68ac7539d027f" +
+				"e819e63abcd020b006a976397632eb5d"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "",
+			"0100000000000000000000000000000002000000000000000000000000000000" +
+				"03000000000000000000000000000000",
+			"c00d121893a9fa603f48ccc1ca3c57ce7499245ea0046db16c53c7c66fe717e3" +
+				"9cf6c748837b61f6ee3adcee17534ed5790bc96880a99ba804bd12c0e6a22cc4"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "",
+			"0100000000000000000000000000000002000000000000000000000000000000" +
+				"0300000000000000000000000000000004000000000000000000000000000000",
+			"c2d5160a1f8683834910acdafc41fbb1632d4a353e8b905ec9a5499ac34f96c7" +
+				"e1049eb080883891a4db8caaa1f99dd004d80487540735234e3744512c6f90ce" +
+				"112864c269fc0d9d88c61fa47e39aa08"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "01",
+			"0200000000000000",
+			"1de22967237a813291213f267e3b452f02d01ae33e4ec854"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "01",
+			"020000000000000000000000",
+			"163d6f9cc1b346cd453a2e4cc1a4a19ae800941ccdc57cc8413c277f"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "01",
+			"02000000000000000000000000000000",
+			"c91545823cc24f17dbb0e9e807d5ec17b292d28ff61189e8e49f3875ef91aff7"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "01",
+			"0200000000000000000000000000000003000000000000000000000000000000",
+			"07dad364bfc2b9da89116d7bef6daaaf6f255510aa654f920ac81b94e8bad365" +
+				"aea1bad12702e1965604374aab96dbbc"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "01",
+			"0200000000000000000000000000000003000000000000000000000000000000" +
+				"04000000000000000000000000000000",
+			"c67a1f0f567a5198aa1fcc8e3f21314336f7f51ca8b1af61feac35a86416fa47" +
+				"fbca3b5f749cdf564527f2314f42fe2503332742b228c647173616cfd44c54eb"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "01",
+			"0200000000000000000000000000000003000000000000000000000000000000" +
+				"0400000000000000000000000000000005000000000000000000000000000000",
+			"67fd45e126bfb9a79930c43aad2d36967d3f0e4d217c1e551f59727870beefc9" +
+				"8cb933a8fce9de887b1e40799988db1fc3f91880ed405b2dd298318858467c89" +
+				"5bde0285037c5de81e5b570a049b62a0"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "010000000000000000000000",
+			"02000000",
+			"22b3f4cd1835e517741dfddccfa07fa4661b74cf"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "010000000000000000000000000000000200",
+			"0300000000000000000000000000000004000000",
+			"43dd0163cdb48f9fe3212bf61b201976067f342bb879ad976d8242acc188ab59" +
+				"cabfe307"},
+		{"0100000000000000000000000000000000000000000000000000000000000000",
+			"030000000000000000000000", "0100000000000000000000000000000002000000",
+			"030000000000000000000000000000000400",
+			"462401724b5ce6588d5a54aae5375513a075cfcdf5042112aa29685c912fc205" +
+				"6543"},
+		{"0000000000000000000000000000000000000000000000000000000000000000",
+			"000000000000000000000000", "",
+			"000000000000000000000000000000004db923dc793ee6497c76dcc03a98e108",
+			"f3f80f2cf0cb2dd9c5984fcda908456cc537703b5ba70324a6793a7bf218d3ea" +
+				"ffffffff000000000000000000000000"},
+		{"0000000000000000000000000000000000000000000000000000000000000000",
+			"000000000000000000000000", "",
+			"eb3640277c7ffd1303c7a542d02d3e4c0000000000000000",
+			"18ce4f0b8cb4d0cac65fea8f79257b20888e53e72299e56d" +
+				"ffffffff000000000000000000000000"},
+	}
+
+	for i, v := range vectors {
+		key, _ := hex.DecodeString(v.key)
+		nonce, _ := hex.DecodeString(v.nonce)
+		ad, _ := hex.DecodeString(v.ad)
+		plain, _ := hex.DecodeString(v.plain)
+		expect, _ := hex.DecodeString(v.cipher)
+
+		a := NewAes256GcmSiv(key)
+		got := a.Seal(nil, nonce, plain, ad)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", i, expect, got)
+		}
+
+		got, err := a.Open(nil, nonce, expect, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", i, plain, got, err)
+		}
+	}
+}
+
+func TestAes256GcmSiv(t *testing.T) {
+	// computed with the AES-GCM-SIV of Tink, under the key 00..1f and the nonce
+	// 40..4b. The hash covers the ciphertexts of testPattern(n) with additional
+	// data testPattern(n % 50), for n up to 600.
+	expect, _ := hex.DecodeString("df5f6997d45a4cf5c5029385bc40e24bed75cf11cf5699b221df805b6be19b9f")
+
+	a := NewAes256GcmSiv(testBytes(0, 32))
+	nonce := testBytes(0x40, Aes256GcmSiv_NPubBytes)
+
+	h := sha256.New()
+	for n := 0; n < 600; n++ {
+		plain, ad := testPattern(n), testPattern(n%50)
+		cipher := a.Seal(nil, nonce, plain, ad)
+		h.Write(cipher)
+
+		got, err := a.Open(nil, nonce, cipher, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", n, err)
+		}
+	}
+	if got := h.Sum(nil); !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestAes256GcmSivForged(t *testing.T) {
+	a := NewAes256GcmSiv(testBytes(0, 32))
+	nonce := testBytes(0x40, Aes256GcmSiv_NPubBytes)
+	plain, ad := testPattern(100), []byte("additional data")
+	cipher := a.Seal(nil, nonce, plain, ad)
+
+	for _, i := range []int{0, 50, len(cipher) - 1} {
+		forged := append([]byte(nil), cipher...)
+		forged[i] ^= 1
+
+		got, err := a.Open(nil, nonce, forged, ad)
+		if err != godium.ErrForgedOrCorrupted {
+			t.Error("forged message was accepted", i, err)
+		}
+		if !bytes.Equal(got, make([]byte, len(plain))) {
+			t.Error("output of a forged message was not erased", i)
+		}
+	}
+
+	if _, err := a.Open(nil, nonce, cipher[:Aes256GcmSiv_ABytes-1], ad); err != godium.ErrCipherTooShort {
+		t.Error("short ciphertext was accepted", err)
+	}
+}
+
+func TestAes256GcmSivModifiedTag(t *testing.T) {
+	// The invalid tag cases of Wycheproof's aes_gcm_siv_test.json modify the
+	// tag of a valid message in these ways, applied here to the RFC 8452 C.2
+	// vector with 1 byte of additional data and a 3 block plaintext.
+	key, _ := hex.DecodeString("0100000000000000000000000000000000000000000000000000000000000000")
+	nonce, _ := hex.DecodeString("030000000000000000000000")
+	ad, _ := hex.DecodeString("01")
+	cipher, _ := hex.DecodeString("c67a1f0f567a5198aa1fcc8e3f21314336f7f51ca8b1af61feac35a86416fa47" +
+		"fbca3b5f749cdf564527f2314f42fe2503332742b228c647173616cfd44c54eb")
+
+	flip := func(bits ...int) func(tag []byte) {
+		return func(tag []byte) {
+			for _, b := range bits {
+				tag[b/8] ^= 1 << uint(b%8)
+			}
+		}
+	}
+	each := func(mask byte) func(tag []byte) {
+		return func(tag []byte) {
+			for i := range tag {
+				tag[i] ^= mask
+			}
+		}
+	}
+	set := func(c byte) func(tag []byte) {
+		return func(tag []byte) {
+			for i := range tag {
+				tag[i] = c
+			}
+		}
+	}
+
+	modifications := []struct {
+		name   string
+		modify func(tag []byte)
+	}{
+		{"Flipped bit 0 in tag", flip(0)},
+		{"Flipped bit 1 in tag", flip(1)},
+		{"Flipped bit 7 in tag", flip(7)},
+		{"Flipped bit 8 in tag", flip(8)},
+		{"Flipped bit 31 in tag", flip(31)},
+		{"Flipped bit 32 in tag", flip(32)},
+		{"Flipped bit 33 in tag", flip(33)},
+		{"Flipped bit 63 in tag", flip(63)},
+		{"Flipped bit 64 in tag", flip(64)},
+		{"Flipped bit 71 in tag", flip(71)},
+		{"Flipped bit 77 in tag", flip(77)},
+		{"Flipped bit 80 in tag", flip(80)},
+		{"Flipped bit 96 in tag", flip(96)},
+		{"Flipped bit 97 in tag", flip(97)},
+		{"Flipped bit 120 in tag", flip(120)},
+		{"Flipped bit 121 in tag", flip(121)},
+		{"Flipped bit 126 in tag", flip(126)},
+		{"Flipped bit 127 in tag", flip(127)},
+		{"Flipped bits 0 and 64 in tag", flip(0, 64)},
+		{"Flipped bits 31 and 63 in tag", flip(31, 63)},
+		{"Flipped bits 63 and 127 in tag", flip(63, 127)},
+		{"all bits of tag flipped", each(0xff)},
+		{"Tag changed to all zero", set(0x00)},
+		{"tag changed to all 1", set(0xff)},
+		{"msbs changed in tag", each(0x80)},
+		{"lsbs changed in tag", each(0x01)},
+	}
+
+	a := NewAes256GcmSiv(key)
+	if _, err := a.Open(nil, nonce, cipher, ad); err != nil {
+		t.Fatal("valid message was rejected", err)
+	}
+
+	for _, m := range modifications {
+		forged := append([]byte(nil), cipher...)
+		m.modify(forged[len(forged)-Aes256GcmSiv_ABytes:])
+
+		if _, err := a.Open(nil, nonce, forged, ad); err != godium.ErrForgedOrCorrupted {
+			t.Error("forged message was accepted", m.name, err)
+		}
+	}
+}
+
+func TestAes256GcmSivInPlace(t *testing.T) {
+	a := NewAes256GcmSiv(testBytes(0, 32))
+	nonce := testBytes(0x40, Aes256GcmSiv_NPubBytes)
+	plain := testPattern(100)
+	expect := a.Seal(nil, nonce, plain, nil)
+
+	buf := append([]byte(nil), plain...)
+	got := a.Seal(buf[:0], nonce, buf, nil)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	got, err := a.Open(got[:0], nonce, got, nil)
+	if err != nil || !bytes.Equal(plain, got) {
+		t.Error("expected result did not match computed", plain, got, err)
+	}
+}
+
+func TestAes256GcmSivKeyWrap(t *testing.T) {
+	kek, key := testBytes(0, 32), testBytes(0x80, 32)
+	ad := []byte("key id")
+
+	wrapped := WrapKeyAes256GcmSiv(nil, kek, key, ad)
+	if len(wrapped) != len(key)+Aes256GcmSiv_ABytes {
+		t.Error("unexpected wrapped key length", len(wrapped))
+	}
+	if again := WrapKeyAes256GcmSiv(nil, kek, key, ad); !bytes.Equal(wrapped, again) {
+		t.Error("key wrap is not deterministic", wrapped, again)
+	}
+
+	got, err := UnwrapKeyAes256GcmSiv(nil, kek, wrapped, ad)
+	if err != nil || !bytes.Equal(key, got) {
+		t.Error("expected result did not match computed", key, got, err)
+	}
+	if _, err = UnwrapKeyAes256GcmSiv(nil, kek, wrapped, nil); err != godium.ErrForgedOrCorrupted {
+		t.Error("key was unwrapped with different additional data", err)
+	}
+}
+
+func BenchmarkAes256GcmSiv_64(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewAes256GcmSiv, 64)
+}
+func BenchmarkAes256GcmSiv_8K(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewAes256GcmSiv, 8192)
+}
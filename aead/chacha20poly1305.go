@@ -11,6 +11,7 @@ import (
 
 	"go.artemisc.eu/godium"
 	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/noncecheck"
 	"go.artemisc.eu/godium/onetimeauth"
 	"go.artemisc.eu/godium/stream"
 )
@@ -22,10 +23,11 @@ const (
 	Chacha20Poly1305_ABytes    = 16
 )
 
+// chacha20poly1305 implements the original ChaCha20-Poly1305 construction,
+// with a 64 bit nonce. Only the key is kept between calls, the stream and
+// Poly1305 states live on the stack of Seal and Open, which do not allocate.
 type chacha20poly1305 struct {
 	godium.Key
-	godium.Stream
-	godium.OneTimeAuth
 }
 
 // NewChacha20Poly1305
@@ -36,21 +38,12 @@ func NewChacha20Poly1305(key []byte) (impl godium.AEAD) {
 	return
 }
 
-// initAead
-func (a *chacha20poly1305) initAead(key, nonce []byte) {
+// initAead keys p with the first 32 bytes of keystream block 0.
+func (a *chacha20poly1305) initAead(p *onetimeauth.Poly1305, nonce []byte) {
 	var block0 [stream.Chacha20_BlockBytes]byte
 
-	if a.Stream == nil {
-		a.Stream = stream.NewChacha20(key, nonce)
-	} else {
-		a.Stream.ReKey(key, nonce)
-	}
-
-	if a.OneTimeAuth == nil {
-		a.OneTimeAuth = onetimeauth.NewPoly1305(block0[:onetimeauth.Poly1305_KeyBytes])
-	} else {
-		a.OneTimeAuth.ReKey(block0[:onetimeauth.Poly1305_KeyBytes])
-	}
+	stream.Chacha20XORIc(block0[:], block0[:], nonce, 0, a.Key)
+	p.ReKey(block0[:onetimeauth.Poly1305_KeyBytes])
 
 	godium.Wipe(block0[:])
 }
@@ -58,15 +51,16 @@ func (a *chacha20poly1305) initAead(key, nonce []byte) {
 // Wipe
 func (a *chacha20poly1305) Wipe() {
 	godium.Wipe(a.Key)
-	if a.Stream != nil {
-		a.Stream.Wipe()
-	}
-	if a.OneTimeAuth != nil {
-		a.OneTimeAuth.Wipe()
-	}
 }
 
+// recordNonce records the nonce of a seal when nonce reuse is checked.
+func (a *chacha20poly1305) recordNonce(nonce []byte) {
+	noncecheck.Record("aead chacha20poly1305", a.Key, nonce[:Chacha20Poly1305_NPubBytes])
+}
+
+// SealDetached
 func (a *chacha20poly1305) SealDetached(dst, dstMac, nonce, plain, ad []byte) (cipher, mac []byte) {
+	var p onetimeauth.Poly1305
 	var slen [8]byte
 
 	mlen := uint64(len(plain))
@@ -75,23 +69,24 @@ func (a *chacha20poly1305) SealDetached(dst, dstMac, nonce, plain, ad []byte) (c
 	cipher = internal.AllocDst(dst, mlen)
 	mac = internal.AllocDst(dstMac, Chacha20Poly1305_ABytes)
 
-	a.initAead(a.Key, nonce)
+	a.recordNonce(nonce)
+	a.initAead(&p, nonce)
 
 	// update tag
-	a.OneTimeAuth.Write(ad)
+	p.Write(ad)
 	binary.LittleEndian.PutUint64(slen[:], adlen)
-	a.OneTimeAuth.Write(slen[:])
+	p.Write(slen[:])
 
 	// encrypt with xor
-	a.Stream.XORKeyStream(cipher, plain)
+	stream.Chacha20XORIc(cipher, plain, nonce, 1, a.Key)
 
 	// update tag
-	a.OneTimeAuth.Write(cipher)
+	p.Write(cipher)
 	binary.LittleEndian.PutUint64(slen[:], mlen)
-	a.OneTimeAuth.Write(slen[:])
+	p.Write(slen[:])
 
 	// add tag
-	a.OneTimeAuth.Sum(mac[:0])
+	p.Sum(mac[:0])
 
 	return
 }
@@ -108,6 +103,7 @@ func (a *chacha20poly1305) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
 
 // OpenDetached
 func (a *chacha20poly1305) OpenDetached(dst, nonce, cipher, mac, ad []byte) (plain []byte, err error) {
+	var p onetimeauth.Poly1305
 	var slen [8]byte
 
 	mlen := uint64(len(cipher))
@@ -115,36 +111,41 @@ func (a *chacha20poly1305) OpenDetached(dst, nonce, cipher, mac, ad []byte) (pla
 
 	plain = internal.AllocDst(dst, mlen)
 
-	a.initAead(a.Key, nonce)
+	a.initAead(&p, nonce)
 
 	// update tag
-	a.OneTimeAuth.Write(ad)
+	p.Write(ad)
 	binary.LittleEndian.PutUint64(slen[:], adlen)
-	a.OneTimeAuth.Write(slen[:])
+	p.Write(slen[:])
 
-	a.OneTimeAuth.Write(cipher)
+	p.Write(cipher)
 	binary.LittleEndian.PutUint64(slen[:], mlen)
-	a.OneTimeAuth.Write(slen[:])
+	p.Write(slen[:])
 
 	// verify tag
-	if !a.OneTimeAuth.Verify(mac) {
+	if !p.Verify(mac) {
 		err = godium.ErrForgedOrCorrupted
 		return
 	}
 
-	// encrypt with xor
-	a.Stream.XORKeyStream(plain, cipher)
+	// decrypt with xor
+	stream.Chacha20XORIc(plain, cipher, nonce, 1, a.Key)
 
 	return
 }
 
 // Open
 func (a *chacha20poly1305) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
+	if len(cipher) < Chacha20Poly1305_ABytes {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
 	mlen := uint64(len(cipher) - Chacha20Poly1305_ABytes)
 	plain = internal.AllocDst(dst, mlen)
 
 	// call with slices of len == 0, pointing to the right parts of cipher.
-	_, err = a.OpenDetached(plain, nonce, cipher[:mlen], cipher[mlen:], ad)
+	_, err = a.OpenDetached(plain[:0], nonce, cipher[:mlen], cipher[mlen:], ad)
 	return
 }
 
@@ -0,0 +1,277 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/noncecheck"
+)
+
+// testPattern returns size bytes of input, byte i set to i % 251.
+func testPattern(size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = byte(i % 251)
+	}
+	return
+}
+
+// testBytes returns size bytes counting up from start.
+func testBytes(start byte, size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = start + byte(i)
+	}
+	return
+}
+
+// skipNonceReuse skips a test that seals under fixed keys and nonces, which
+// the godium_noncecheck build tag reports as nonce reuse. Tests that seal a
+// fixed key and nonce only once call noncecheck.Reset instead.
+func skipNonceReuse(t *testing.T) {
+	if noncecheck.Enabled {
+		t.Skip("seals under fixed keys and nonces")
+	}
+}
+
+// chacha20poly1305Vectors were computed with libsodium's crypto_aead_*_encrypt
+// under the key 00..1f and the nonce 40... The hash covers the ciphertexts of
+// testPattern(n) with additional data testPattern(n % 50), for n up to 1100.
+var chacha20poly1305Vectors = []struct {
+	name  string
+	new   func(key []byte) godium.AEAD
+	empty string
+	short string
+	hash  string
+}{
+	{"chacha20poly1305", NewChacha20Poly1305,
+		"2f5622ae5e48e2346d20bba0f6d33f62",
+		"6411e79baca1021008fb8742a9b74764e035c469d0b5b73378b0b51b1a16cfc2" +
+			"e704be2730197ffd994bcb33c3937bf68d7fba3331b05f6dbf1e8a8843213010" +
+			"3d705f446a37690fa813ee9e2d951df3dd980c2b62d075804721a2b41f8ffd3e" +
+			"f3348f245f519e1c8c38a9b466dccb39132e2743",
+		"6872965de23581a29b23c243fcd48424ed59ebb615dda464cd2f75627a6d64bd"},
+	{"chacha20poly1305_ietf", NewChacha20Poly1305Ietf,
+		"2feeb456ec810ae069850c4c40c195c9",
+		"f8557e82764fe80650c6fc0bb7e263c2a4dd710b50e95778e615c840f63a2475" +
+			"d092bb53026e44d12246056c4679c0b1d1625efaca43bb18e3fb350034560d5e" +
+			"8b716b1e1756a16c0a4727c28f636b258d0ac27da8e8b639e528f6765001dc2b" +
+			"fe59ec39850b1fc8953d2b62d869c1984121cec9",
+		"4354ca12239654cbf84beef557cb85e287007ed745af9ec089521ea16cfaaac8"},
+	{"xchacha20poly1305_ietf", NewXChacha20Poly1305Ietf,
+		"61d8489bd58cfbe3ec6d5913671c3779",
+		"d4380773d4e57f1187fd8db5a3916b9d82abbfd7074c458d7228e75e15193d8f" +
+			"36d8204d71b7a237d79bde6726f7f4deab5a4fdb5bb082ab8ec532cf2f8f726b" +
+			"1a6aba3150f6038fcd4afab49034d2a0a69654787c68322008fa5dcf99386d7a" +
+			"4614d5aabcbabb6ad2a411f678e02ba949dc91b0",
+		"7a0dcce2a0fa2e6064393f79abb857589c63db306b24a19cce28d2da25580396"},
+}
+
+func TestChacha20Poly1305(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range chacha20poly1305Vectors {
+		a := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, a.NPubBytes())
+
+		expect, _ := hex.DecodeString(v.empty)
+		got := a.Seal(nil, nonce, nil, nil)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.name, expect, got)
+		}
+
+		expect, _ = hex.DecodeString(v.short)
+		got = a.Seal(nil, nonce, testPattern(100), []byte("additional data"))
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.name, expect, got)
+		}
+
+		expect, _ = hex.DecodeString(v.hash)
+		h := sha256.New()
+		for n := 0; n < 1100; n++ {
+			plain, ad := testPattern(n), testPattern(n%50)
+			cipher := a.Seal(nil, nonce, plain, ad)
+			h.Write(cipher)
+
+			c, mac := a.SealDetached(nil, nil, nonce, plain, ad)
+			if !bytes.Equal(cipher, append(c, mac...)) {
+				t.Error("expected result did not match computed", v.name, n)
+			}
+
+			got, err := a.Open(nil, nonce, cipher, ad)
+			if err != nil || !bytes.Equal(plain, got) {
+				t.Error("expected result did not match computed", v.name, n, err)
+			}
+			got, err = a.OpenDetached(nil, nonce, c, mac, ad)
+			if err != nil || !bytes.Equal(plain, got) {
+				t.Error("expected result did not match computed", v.name, n, err)
+			}
+		}
+		if sum := h.Sum(nil); !bytes.Equal(expect, sum) {
+			t.Error("expected result did not match computed", v.name, expect, sum)
+		}
+	}
+}
+
+func TestChacha20Poly1305IetfRFC8439(t *testing.T) {
+	// RFC 8439, section 2.8.2
+	key := testBytes(0x80, Chacha20Poly1305Ietf_KeyBytes)
+	nonce, _ := hex.DecodeString("070000004041424344454647")
+	ad, _ := hex.DecodeString("50515253c0c1c2c3c4c5c6c7")
+	plain := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	expect, _ := hex.DecodeString("d31a8d34648e60db7b86afbc53ef7ec2a4aded51296e08fea9e2b5a736ee62d6" +
+		"3dbea45e8ca9671282fafb69da92728b1a71de0a9e060b2905d6a5b67ecd3b36" +
+		"92ddbd7f2d778b8c9803aee328091b58fab324e4fad675945585808b4831d7bc" +
+		"3ff4def08e4b7a9de576d26586cec64b6116" +
+		"1ae10b594f09e26a7e902ecbd0600691")
+
+	got := NewChacha20Poly1305Ietf(key).Seal(nil, nonce, plain, ad)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestXChacha20Poly1305IetfDraft(t *testing.T) {
+	// draft-irtf-cfrg-xchacha-03, section A.3.1
+	key := testBytes(0x80, XChacha20Poly1305Ietf_KeyBytes)
+	nonce := testBytes(0x40, XChacha20Poly1305Ietf_NPubBytes)
+	ad, _ := hex.DecodeString("50515253c0c1c2c3c4c5c6c7")
+	plain := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	expect, _ := hex.DecodeString("bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb" +
+		"731c7f1b0b4aa6440bf3a82f4eda7e39ae64c6708c54c216cb96b72e1213b452" +
+		"2f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff9" +
+		"21f9664c97637da9768812f615c68b13b52ec0875924c1c7987947deafd8780a" +
+		"cf49")
+
+	got := NewXChacha20Poly1305Ietf(key).Seal(nil, nonce, plain, ad)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestChacha20Poly1305Forged(t *testing.T) {
+	noncecheck.Reset()
+
+	for _, v := range chacha20poly1305Vectors {
+		a := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, a.NPubBytes())
+		ad := []byte("additional data")
+		cipher := a.Seal(nil, nonce, testPattern(300), ad)
+
+		for _, i := range []int{0, 150, len(cipher) - 1} {
+			cipher[i] ^= 1
+			plain, err := a.Open(nil, nonce, cipher, ad)
+			if err != godium.ErrForgedOrCorrupted {
+				t.Error("forged message was accepted", v.name, i, err)
+			}
+			if !bytes.Equal(plain, make([]byte, len(plain))) {
+				t.Error("forged message was decrypted", v.name, i)
+			}
+			cipher[i] ^= 1
+		}
+
+		if _, err := a.Open(nil, nonce, cipher, ad[1:]); err != godium.ErrForgedOrCorrupted {
+			t.Error("forged additional data was accepted", v.name, err)
+		}
+		if _, err := a.Open(nil, nonce, cipher[:15], ad); err != godium.ErrCipherTooShort {
+			t.Error("short ciphertext was accepted", v.name, err)
+		}
+	}
+}
+
+func TestChacha20Poly1305InPlace(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range chacha20poly1305Vectors {
+		a := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, a.NPubBytes())
+		plain := testPattern(1000)
+		expect := a.Seal(nil, nonce, plain, nil)
+
+		buf := make([]byte, len(plain), len(plain)+a.ABytes())
+		copy(buf, plain)
+		got := a.Seal(buf[:0], nonce, buf, nil)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.name)
+		}
+
+		got, err := a.Open(got[:0], nonce, got, nil)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, err)
+		}
+	}
+}
+
+func TestChacha20Poly1305Allocs(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range chacha20poly1305Vectors {
+		a := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, a.NPubBytes())
+		plain := testPattern(1000)
+		ad := testPattern(20)
+		cipher := make([]byte, 0, len(plain)+a.ABytes())
+		mac := make([]byte, 0, a.ABytes())
+		out := make([]byte, 0, len(plain))
+
+		allocs := testing.AllocsPerRun(10, func() {
+			c := a.Seal(cipher, nonce, plain, ad)
+			if _, err := a.Open(out, nonce, c, ad); err != nil {
+				t.Error(v.name, err)
+			}
+			c, m := a.SealDetached(cipher, mac, nonce, plain, ad)
+			if _, err := a.OpenDetached(out, nonce, c, m, ad); err != nil {
+				t.Error(v.name, err)
+			}
+		})
+		if allocs != 0 {
+			t.Error("Seal and Open allocated", v.name, allocs)
+		}
+	}
+}
+
+func benchmarkChacha20Poly1305(b *testing.B, new func([]byte) godium.AEAD, size int) {
+	a := new(testBytes(0, 32))
+	nonce := testBytes(0x40, a.NPubBytes())
+	plain := testPattern(size)
+	ad := testPattern(13)
+	cipher := make([]byte, 0, size+a.ABytes())
+
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Seal(cipher, nonce, plain, ad)
+	}
+}
+
+func BenchmarkChacha20Poly1305_64(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewChacha20Poly1305, 64)
+}
+func BenchmarkChacha20Poly1305_8K(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewChacha20Poly1305, 8192)
+}
+func BenchmarkChacha20Poly1305Ietf_64(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewChacha20Poly1305Ietf, 64)
+}
+func BenchmarkChacha20Poly1305Ietf_1K(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewChacha20Poly1305Ietf, 1024)
+}
+func BenchmarkChacha20Poly1305Ietf_8K(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewChacha20Poly1305Ietf, 8192)
+}
+func BenchmarkXChacha20Poly1305Ietf_64(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewXChacha20Poly1305Ietf, 64)
+}
+func BenchmarkXChacha20Poly1305Ietf_8K(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewXChacha20Poly1305Ietf, 8192)
+}
@@ -7,12 +7,9 @@
 package aead
 
 import (
-	"encoding/binary"
-
 	"go.artemisc.eu/godium"
 	"go.artemisc.eu/godium/internal"
-	"go.artemisc.eu/godium/onetimeauth"
-	"go.artemisc.eu/godium/stream"
+	"go.artemisc.eu/godium/noncecheck"
 )
 
 const (
@@ -20,16 +17,18 @@ const (
 	Chacha20Poly1305Ietf_NSecBytes = 0
 	Chacha20Poly1305Ietf_NPubBytes = 12
 	Chacha20Poly1305Ietf_ABytes    = 16
-)
 
-var (
-	pad0 [16]byte
+	// Chacha20Poly1305Ietf_MessageBytesMax is the longest message that can be
+	// encrypted with the 32 bit block counter, block 0 is used for the
+	// Poly1305 key.
+	Chacha20Poly1305Ietf_MessageBytesMax = 64 * (1<<32 - 1)
 )
 
+// chacha20poly1305ietf implements the ChaCha20-Poly1305 construction of
+// RFC 8439. Only the key is kept between calls, Seal and Open do not
+// allocate, and run in a single pass over the message on amd64.
 type chacha20poly1305ietf struct {
 	godium.Key
-	godium.Stream
-	godium.OneTimeAuth
 }
 
 // NewChacha20Poly1305Ietf
@@ -40,121 +39,64 @@ func NewChacha20Poly1305Ietf(key []byte) (impl godium.AEAD) {
 	return
 }
 
-// initAead
-func (a *chacha20poly1305ietf) initAead(key, nonce []byte) {
-	var block0 [stream.Chacha20Ietf_BlockBytes]byte
-
-	if a.Stream == nil {
-		a.Stream = stream.NewChacha20Ietf(key, nonce)
-	} else {
-		a.Stream.ReKey(key, nonce)
-	}
-
-	if a.OneTimeAuth == nil {
-		a.OneTimeAuth = onetimeauth.NewPoly1305(block0[:onetimeauth.Poly1305_KeyBytes])
-	} else {
-		a.OneTimeAuth.ReKey(block0[:onetimeauth.Poly1305_KeyBytes])
-	}
-
-	godium.Wipe(block0[:])
-}
-
 // Wipe
 func (a *chacha20poly1305ietf) Wipe() {
 	godium.Wipe(a.Key)
+}
 
-	if a.Stream != nil {
-		a.Stream.Wipe()
-	}
-	if a.OneTimeAuth != nil {
-		a.OneTimeAuth.Wipe()
-	}
+// recordNonce records the nonce of a seal when nonce reuse is checked.
+func (a *chacha20poly1305ietf) recordNonce(nonce []byte) {
+	noncecheck.Record("aead chacha20poly1305ietf", a.Key, nonce[:Chacha20Poly1305Ietf_NPubBytes])
 }
 
+// SealDetached
 func (a *chacha20poly1305ietf) SealDetached(dst, dstMac, nonce, plain, ad []byte) (cipher, mac []byte) {
-	slen := make([]byte, 8)
-
 	mlen := uint64(len(plain))
-	adlen := uint64(len(ad))
+	if mlen > Chacha20Poly1305Ietf_MessageBytesMax {
+		panic("aead: message too long for ChaCha20-Poly1305")
+	}
 
 	cipher = internal.AllocDst(dst, mlen)
 	mac = internal.AllocDst(dstMac, Chacha20Poly1305Ietf_ABytes)
 
-	a.initAead(a.Key, nonce)
-
-	// update tag
-	a.OneTimeAuth.Write(ad)
-	a.OneTimeAuth.Write(pad0[:(0x10-adlen)&0xf])
-
-	// encrypt with xor
-	a.Stream.XORKeyStream(cipher[:mlen], plain)
-
-	// update tag
-	a.OneTimeAuth.Write(cipher)
-	a.OneTimeAuth.Write(pad0[:(0x10-mlen)&0xf])
-
-	binary.LittleEndian.PutUint64(slen, adlen)
-	a.OneTimeAuth.Write(slen)
-	binary.LittleEndian.PutUint64(slen, mlen)
-	a.OneTimeAuth.Write(slen)
-
-	// add tag
-	a.OneTimeAuth.Sum(mac[:0])
-
+	a.recordNonce(nonce)
+	sealIetfGeneric(cipher, mac, a.Key, nonce, plain, ad)
 	return
 }
 
 // Seal
 func (a *chacha20poly1305ietf) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
 	mlen := uint64(len(plain))
-	cipher = internal.AllocDst(dst, mlen+Chacha20Poly1305_ABytes)
+	if mlen > Chacha20Poly1305Ietf_MessageBytesMax {
+		panic("aead: message too long for ChaCha20-Poly1305")
+	}
 
-	// call with slices of len == 0, pointing to the right parts of cipher.
-	_, _ = a.SealDetached(cipher[0:0], cipher[mlen:mlen], nonce, plain, ad)
+	cipher = internal.AllocDst(dst, mlen+Chacha20Poly1305Ietf_ABytes)
+
+	a.recordNonce(nonce)
+	sealIetf(cipher, a.Key, nonce, plain, ad)
 	return
 }
 
 // OpenDetached
 func (a *chacha20poly1305ietf) OpenDetached(dst, nonce, cipher, mac, ad []byte) (plain []byte, err error) {
-	slen := make([]byte, 8)
-
-	mlen := uint64(len(cipher))
-	adlen := uint64(len(ad))
-
-	plain = internal.AllocDst(dst, mlen)
-
-	a.initAead(a.Key, nonce)
-
-	// update tag
-	a.OneTimeAuth.Write(ad)
-	a.OneTimeAuth.Write(pad0[:(0x10-adlen)&0xf])
-
-	a.OneTimeAuth.Write(cipher)
-	a.OneTimeAuth.Write(pad0[:(0x10-mlen)&0xf])
-
-	binary.LittleEndian.PutUint64(slen, adlen)
-	a.OneTimeAuth.Write(slen)
-	binary.LittleEndian.PutUint64(slen, mlen)
-	a.OneTimeAuth.Write(slen)
-
-	// verify tag
-	if !a.OneTimeAuth.Verify(mac) {
-		err = godium.ErrForgedOrCorrupted
-		return
-	}
-
-	// encrypt with xor
-	a.Stream.XORKeyStream(plain, cipher)
+	plain = internal.AllocDst(dst, uint64(len(cipher)))
 
+	err = openIetfGeneric(plain, a.Key, nonce, cipher, mac, ad)
 	return
 }
 
 // Open
 func (a *chacha20poly1305ietf) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
+	if len(cipher) < Chacha20Poly1305Ietf_ABytes {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
 	mlen := uint64(len(cipher) - Chacha20Poly1305Ietf_ABytes)
 	plain = internal.AllocDst(dst, mlen)
 
-	_, err = a.OpenDetached(plain[:0], nonce, cipher[:mlen], cipher[mlen:], ad)
+	err = openIetf(plain, a.Key, nonce, cipher, ad)
 	return
 }
 
@@ -0,0 +1,87 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+package aead
+
+import (
+	"encoding/binary"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal/cpu"
+)
+
+var (
+	useAVX2  = cpu.X86.HasAVX2 && cpu.X86.HasBMI2
+	useSSSE3 = cpu.X86.HasSSSE3
+)
+
+// chacha20Poly1305Seal encrypts src into dst and appends the tag, computing
+// the keystream and the tag in a single pass. The key holds the ChaCha20
+// state, with the counter set to 0.
+//
+//go:noescape
+func chacha20Poly1305Seal(dst []byte, key []uint32, src, ad []byte)
+
+// chacha20Poly1305Open decrypts src into dst, and reports whether the tag
+// that directly follows src in memory is valid.
+//
+//go:noescape
+func chacha20Poly1305Open(dst []byte, key []uint32, src, ad []byte) bool
+
+// ietfState sets up the ChaCha20 state for the key and nonce at counter 0.
+func ietfState(state *[16]uint32, key, nonce []byte) {
+	nonce = nonce[:Chacha20Poly1305Ietf_NPubBytes]
+
+	state[0] = 0x61707865
+	state[1] = 0x3320646e
+	state[2] = 0x79622d32
+	state[3] = 0x6b206574
+	for i := 0; i < 8; i++ {
+		state[4+i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+	state[12] = 0
+	for i := 0; i < 3; i++ {
+		state[13+i] = binary.LittleEndian.Uint32(nonce[4*i:])
+	}
+}
+
+// sealIetf encrypts plain into out, followed by the tag. The fused kernel is
+// used if the processor supports SSSE3.
+func sealIetf(out, key, nonce, plain, ad []byte) {
+	var state [16]uint32
+
+	mlen := len(plain)
+	if !useSSSE3 {
+		sealIetfGeneric(out[:mlen], out[mlen:], key, nonce, plain, ad)
+		return
+	}
+
+	ietfState(&state, key, nonce)
+	chacha20Poly1305Seal(out, state[:], plain, ad)
+	state = [16]uint32{}
+}
+
+// openIetf decrypts cipher, which ends with the tag, into plain. The output
+// is erased if the tag is invalid.
+func openIetf(plain, key, nonce, cipher, ad []byte) (err error) {
+	var state [16]uint32
+
+	mlen := len(cipher) - Chacha20Poly1305Ietf_ABytes
+	if !useSSSE3 {
+		err = openIetfGeneric(plain, key, nonce, cipher[:mlen], cipher[mlen:], ad)
+		return
+	}
+
+	ietfState(&state, key, nonce)
+	if !chacha20Poly1305Open(plain, state[:], cipher[:mlen], ad) {
+		godium.Wipe(plain)
+		err = godium.ErrForgedOrCorrupted
+	}
+	state = [16]uint32{}
+	return
+}
@@ -0,0 +1,61 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+package aead
+
+import (
+	"testing"
+)
+
+// chacha20poly1305Kernels lists the implementations that are supported by the
+// processor, with a function that enables only that implementation.
+func chacha20poly1305Kernels() (names []string, enable []func()) {
+	avx2, ssse3 := useAVX2, useSSSE3
+
+	set := func(a, b bool) func() {
+		return func() { useAVX2, useSSSE3 = a, b }
+	}
+
+	names = append(names, "generic")
+	enable = append(enable, set(false, false))
+	if ssse3 {
+		names = append(names, "SSSE3")
+		enable = append(enable, set(false, true))
+	}
+	if avx2 {
+		names = append(names, "AVX2")
+		enable = append(enable, set(true, true))
+	}
+	names = append(names, "default")
+	enable = append(enable, set(avx2, ssse3))
+	return
+}
+
+func TestChacha20Poly1305Kernels(t *testing.T) {
+	skipNonceReuse(t)
+
+	names, enable := chacha20poly1305Kernels()
+	defer enable[len(enable)-1]()
+
+	for i, name := range names {
+		enable[i]()
+		t.Run(name, TestChacha20Poly1305)
+		t.Run(name, TestChacha20Poly1305Forged)
+		t.Run(name, TestChacha20Poly1305InPlace)
+	}
+}
+
+func BenchmarkChacha20Poly1305Kernels(b *testing.B) {
+	names, enable := chacha20poly1305Kernels()
+	defer enable[len(enable)-1]()
+
+	for i, name := range names {
+		enable[i]()
+		b.Run(name, BenchmarkChacha20Poly1305Ietf_8K)
+	}
+}
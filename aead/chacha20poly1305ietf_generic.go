@@ -0,0 +1,72 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"encoding/binary"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/onetimeauth"
+	"go.artemisc.eu/godium/stream"
+)
+
+var (
+	pad0 [16]byte
+)
+
+// initIetf keys p with the first 32 bytes of keystream block 0, and absorbs
+// the padded additional data.
+func initIetf(p *onetimeauth.Poly1305, key, nonce, ad []byte) {
+	var block0 [stream.Chacha20Ietf_BlockBytes]byte
+
+	stream.Chacha20IetfXORIc(block0[:], block0[:], nonce, 0, key)
+	p.ReKey(block0[:onetimeauth.Poly1305_KeyBytes])
+	godium.Wipe(block0[:])
+
+	p.Write(ad)
+	p.Write(pad0[:(0x10-len(ad))&0xf])
+}
+
+// finishIetf absorbs the padded ciphertext and the lengths into p.
+func finishIetf(p *onetimeauth.Poly1305, cipher, ad []byte) {
+	var slen [16]byte
+
+	p.Write(cipher)
+	p.Write(pad0[:(0x10-len(cipher))&0xf])
+
+	binary.LittleEndian.PutUint64(slen[:8], uint64(len(ad)))
+	binary.LittleEndian.PutUint64(slen[8:], uint64(len(cipher)))
+	p.Write(slen[:])
+}
+
+// sealIetfGeneric encrypts plain into cipher and writes the tag to mac, with
+// a separate pass of ChaCha20 and Poly1305 over the message.
+func sealIetfGeneric(cipher, mac, key, nonce, plain, ad []byte) {
+	var p onetimeauth.Poly1305
+
+	initIetf(&p, key, nonce, ad)
+	stream.Chacha20IetfXORIc(cipher, plain, nonce, 1, key)
+	finishIetf(&p, cipher, ad)
+
+	p.Sum(mac[:0])
+}
+
+// openIetfGeneric checks the tag of cipher before decrypting it into plain.
+func openIetfGeneric(plain, key, nonce, cipher, mac, ad []byte) (err error) {
+	var p onetimeauth.Poly1305
+
+	initIetf(&p, key, nonce, ad)
+	finishIetf(&p, cipher, ad)
+
+	if !p.Verify(mac) {
+		err = godium.ErrForgedOrCorrupted
+		return
+	}
+
+	stream.Chacha20IetfXORIc(plain, cipher, nonce, 1, key)
+	return
+}
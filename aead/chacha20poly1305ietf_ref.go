@@ -0,0 +1,22 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build !amd64 !gc purego
+
+package aead
+
+// sealIetf encrypts plain into out, followed by the tag.
+func sealIetf(out, key, nonce, plain, ad []byte) {
+	mlen := len(plain)
+	sealIetfGeneric(out[:mlen], out[mlen:], key, nonce, plain, ad)
+}
+
+// openIetf decrypts cipher, which ends with the tag, into plain.
+func openIetf(plain, key, nonce, cipher, ad []byte) (err error) {
+	mlen := len(cipher) - Chacha20Poly1305Ietf_ABytes
+	err = openIetfGeneric(plain, key, nonce, cipher[:mlen], cipher[mlen:], ad)
+	return
+}
@@ -0,0 +1,166 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"crypto/subtle"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/generichash"
+	"go.artemisc.eu/godium/internal"
+)
+
+const (
+	// Committing_CommitBytes is the size of the key commitment appended to
+	// every message by a committing AEAD.
+	Committing_CommitBytes = 32
+
+	// Committing_KeyBytesMax is the longest key a committing AEAD accepts.
+	Committing_KeyBytesMax = generichash.Blake2b_KeyBytesMax
+)
+
+// labels of the BLAKE2b tuple hashes used by committing AEADs.
+const (
+	committingEncLabel    = "godium committing enc"
+	committingCommitLabel = "godium committing commit"
+	committingLabel       = "godium committing"
+)
+
+// committing turns any AEAD into a key-committing AEAD. AEADs based on
+// Poly1305 or GHASH are not committing: a ciphertext can be crafted that
+// opens under two different keys, like the "invisible salamanders" attack on
+// multi-recipient messages. The inner AEAD is keyed with a key derived from
+// the key, and every message carries a BLAKE2b tuple hash of a second derived
+// key, the nonce and the additional data. Finding a message that opens under
+// two keys requires a collision of BLAKE2b.
+type committing struct {
+	inner     godium.AEAD
+	commitKey [32]byte
+}
+
+// NewCommitting returns the committing variant of the AEAD created by
+// newAEAD, under the key. The inner AEAD is created with a key of the same
+// length, derived from the key. The key commitment is appended to the tag, so
+// Seal returns the ciphertext, the tag of the inner AEAD and the commitment.
+// It panics if the key is empty or longer than Committing_KeyBytesMax.
+func NewCommitting(key []byte, newAEAD func(key []byte) godium.AEAD) (impl godium.AEAD) {
+	var encKey [Committing_KeyBytesMax]byte
+
+	if len(key) == 0 || len(key) > Committing_KeyBytesMax {
+		panic("aead: invalid committing AEAD key length")
+	}
+
+	a := new(committing)
+	_, _ = generichash.Blake2bTupleSum(a.commitKey[:0], 32, key, committingCommitLabel)
+	_, _ = generichash.Blake2bTupleSum(encKey[:0], uint32(len(key)), key, committingEncLabel)
+
+	a.inner = newAEAD(encKey[:len(key)])
+	godium.Wipe(encKey[:])
+
+	impl = a
+	return
+}
+
+// commit computes the key commitment for the nonce and ad.
+func (a *committing) commit(c *[Committing_CommitBytes]byte, nonce, ad []byte) {
+	_, _ = generichash.Blake2bTupleSum(c[:0], Committing_CommitBytes, a.commitKey[:],
+		committingLabel, nonce[:a.inner.NPubBytes()], ad)
+}
+
+// verify checks the key commitment for the nonce and ad.
+func (a *committing) verify(commitment, nonce, ad []byte) (ok bool) {
+	var c [Committing_CommitBytes]byte
+
+	a.commit(&c, nonce, ad)
+	ok = subtle.ConstantTimeCompare(c[:], commitment) == 1
+	return
+}
+
+// Wipe
+func (a *committing) Wipe() {
+	a.inner.Wipe()
+	godium.Wipe(a.commitKey[:])
+}
+
+// SealDetached returns the tag of the inner AEAD, followed by the key
+// commitment, as the mac.
+func (a *committing) SealDetached(dst, dstMac, nonce, plain, ad []byte) (cipher, mac []byte) {
+	var c [Committing_CommitBytes]byte
+
+	cipher = internal.AllocDst(dst, uint64(len(plain)))
+	mac = internal.AllocDst(dstMac, uint64(a.ABytes()))
+
+	innerCipher, innerMac := a.inner.SealDetached(cipher[:0], mac[:0], nonce, plain, ad)
+	internal.CopyOut(cipher, innerCipher)
+	internal.CopyOut(mac, innerMac)
+
+	a.commit(&c, nonce, ad)
+	copy(mac[a.inner.ABytes():], c[:])
+	return
+}
+
+// Seal
+func (a *committing) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
+	var c [Committing_CommitBytes]byte
+
+	mlen := uint64(len(plain))
+	cipher = internal.AllocDst(dst, mlen+uint64(a.ABytes()))
+
+	internal.CopyOut(cipher, a.inner.Seal(cipher[:0], nonce, plain, ad))
+
+	a.commit(&c, nonce, ad)
+	copy(cipher[len(cipher)-Committing_CommitBytes:], c[:])
+	return
+}
+
+// OpenDetached checks the key commitment before opening the message with the
+// inner AEAD.
+func (a *committing) OpenDetached(dst, nonce, cipher, mac, ad []byte) (plain []byte, err error) {
+	plain = internal.AllocDst(dst, uint64(len(cipher)))
+
+	tagBytes := len(mac) - Committing_CommitBytes
+	if tagBytes != a.inner.ABytes() || !a.verify(mac[tagBytes:], nonce, ad) {
+		err = godium.ErrForgedOrCorrupted
+		return
+	}
+
+	innerPlain, err := a.inner.OpenDetached(plain[:0], nonce, cipher, mac[:tagBytes], ad)
+	if err == nil {
+		internal.CopyOut(plain, innerPlain)
+	}
+	return
+}
+
+// Open checks the key commitment before opening the message with the inner
+// AEAD.
+func (a *committing) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
+	if len(cipher) < a.ABytes() {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	clen := len(cipher) - Committing_CommitBytes
+	plain = internal.AllocDst(dst, uint64(clen-a.inner.ABytes()))
+
+	if !a.verify(cipher[clen:], nonce, ad) {
+		err = godium.ErrForgedOrCorrupted
+		return
+	}
+
+	innerPlain, err := a.inner.Open(plain[:0], nonce, cipher[:clen], ad)
+	if err == nil {
+		internal.CopyOut(plain, innerPlain)
+	}
+	return
+}
+
+func (a *committing) Overhead() int  { return a.ABytes() }
+func (a *committing) NonceSize() int { return a.inner.NonceSize() }
+func (a *committing) KeyBytes() int  { return a.inner.KeyBytes() }
+func (a *committing) NSecBytes() int { return a.inner.NSecBytes() }
+func (a *committing) NPubBytes() int { return a.inner.NPubBytes() }
+func (a *committing) ABytes() int    { return a.inner.ABytes() + Committing_CommitBytes }
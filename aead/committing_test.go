@@ -0,0 +1,221 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/generichash"
+)
+
+func TestCommitting(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range aeads {
+		a := NewCommitting(testBytes(0, 32), v.new)
+		other := NewCommitting(testBytes(1, 32), v.new)
+		nonce := testBytes(0x40, a.NPubBytes())
+		plain, ad := testPattern(100), []byte("additional data")
+
+		cipher := a.Seal(nil, nonce, plain, ad)
+		if len(cipher) != len(plain)+a.Overhead() || a.Overhead() != v.new(testBytes(0, 32)).Overhead()+Committing_CommitBytes {
+			t.Error("unexpected ciphertext length", v.name, len(cipher))
+		}
+
+		c, mac := a.SealDetached(nil, nil, nonce, plain, ad)
+		if !bytes.Equal(cipher, append(c, mac...)) {
+			t.Error("expected result did not match computed", v.name, cipher, c, mac)
+		}
+
+		got, err := a.Open(nil, nonce, cipher, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, err)
+		}
+		got, err = a.OpenDetached(nil, nonce, c, mac, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, err)
+		}
+
+		if _, err = other.Open(nil, nonce, cipher, ad); err != godium.ErrForgedOrCorrupted {
+			t.Error("message was accepted under a different key", v.name, err)
+		}
+		if _, err = a.Open(nil, nonce, cipher, nil); err != godium.ErrForgedOrCorrupted {
+			t.Error("message was accepted with different additional data", v.name, err)
+		}
+		cipher[len(cipher)-1] ^= 1
+		if _, err = a.Open(nil, nonce, cipher, ad); err != godium.ErrForgedOrCorrupted {
+			t.Error("forged commitment was accepted", v.name, err)
+		}
+		if _, err = a.Open(nil, nonce, cipher[:a.Overhead()-1], ad); err != godium.ErrCipherTooShort {
+			t.Error("short ciphertext was accepted", v.name, err)
+		}
+	}
+}
+
+// TestCommittingAllocating checks that the output of an inner AEAD that does
+// not write to the buffers it is given is still returned.
+func TestCommittingAllocating(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range aeads {
+		expect := NewCommitting(testBytes(0, 32), v.new)
+		a := NewCommitting(testBytes(0, 32), func(key []byte) godium.AEAD {
+			return allocAEAD{v.new(key)}
+		})
+		nonce := testBytes(0x40, a.NPubBytes())
+		plain, ad := testPattern(100), []byte("additional data")
+		sealed := expect.Seal(nil, nonce, plain, ad)
+
+		cipher := a.Seal(make([]byte, 0, len(sealed)), nonce, plain, ad)
+		if !bytes.Equal(sealed, cipher) {
+			t.Error("expected result did not match computed", v.name, sealed, cipher)
+		}
+
+		c, mac := a.SealDetached(make([]byte, 0, len(plain)), make([]byte, 0, a.ABytes()), nonce, plain, ad)
+		if !bytes.Equal(sealed, append(c, mac...)) {
+			t.Error("expected result did not match computed", v.name, sealed, c, mac)
+		}
+
+		got, err := a.Open(make([]byte, 0, len(plain)), nonce, cipher, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, plain, got, err)
+		}
+
+		got, err = a.OpenDetached(make([]byte, 0, len(plain)), nonce, c, mac, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, plain, got, err)
+		}
+	}
+}
+
+// gf128Mul multiplies x and y in the GHASH field, in the bit order of GCM.
+func gf128Mul(x, y [2]uint64) (z [2]uint64) {
+	v := y
+	for i := 0; i < 128; i++ {
+		if x[i/64]>>(63-uint(i%64))&1 == 1 {
+			z[0] ^= v[0]
+			z[1] ^= v[1]
+		}
+
+		lsb := v[1] & 1
+		v[1] = v[1]>>1 | v[0]<<63
+		v[0] >>= 1
+		if lsb == 1 {
+			v[0] ^= 0xe1 << 56
+		}
+	}
+	return
+}
+
+// gf128Inv computes x^(2^128 - 2), the inverse of x in the GHASH field.
+func gf128Inv(x [2]uint64) (r [2]uint64) {
+	r = [2]uint64{1 << 63, 0}
+	for i := 0; i < 127; i++ {
+		r = gf128Mul(gf128Mul(r, r), x)
+	}
+	r = gf128Mul(r, r)
+	return
+}
+
+func gf128Add(x ...[2]uint64) (z [2]uint64) {
+	for _, v := range x {
+		z[0] ^= v[0]
+		z[1] ^= v[1]
+	}
+	return
+}
+
+func gf128Load(b []byte) [2]uint64 {
+	return [2]uint64{binary.BigEndian.Uint64(b), binary.BigEndian.Uint64(b[8:])}
+}
+
+func gf128Store(b []byte, x [2]uint64) {
+	binary.BigEndian.PutUint64(b, x[0])
+	binary.BigEndian.PutUint64(b[8:], x[1])
+}
+
+// salamander crafts a two block AES-GCM message without additional data that
+// opens under both key1 and key2, by solving the tag equations for the first
+// ciphertext block.
+func salamander(key1, key2, nonce []byte) (sealed []byte) {
+	var h, e [2][2]uint64
+	var block [16]byte
+
+	for i, key := range [][]byte{key1, key2} {
+		b, _ := aes.NewCipher(key)
+		b.Encrypt(block[:], make([]byte, 16))
+		h[i] = gf128Load(block[:])
+
+		copy(block[:], nonce)
+		binary.BigEndian.PutUint32(block[12:], 1)
+		b.Encrypt(block[:], block[:])
+		e[i] = gf128Load(block[:])
+	}
+
+	c2 := [2]uint64{0x0123456789abcdef, 0xfedcba9876543210}
+	lengths := [2]uint64{0, 2 * 128}
+
+	h1sq, h2sq := gf128Mul(h[0], h[0]), gf128Mul(h[1], h[1])
+	h1cu, h2cu := gf128Mul(h1sq, h[0]), gf128Mul(h2sq, h[1])
+
+	// C1 H1^3 + C2 H1^2 + L H1 + E1 = C1 H2^3 + C2 H2^2 + L H2 + E2
+	c1 := gf128Mul(gf128Add(
+		gf128Mul(c2, gf128Add(h1sq, h2sq)),
+		gf128Mul(lengths, gf128Add(h[0], h[1])),
+		e[0], e[1]), gf128Inv(gf128Add(h1cu, h2cu)))
+	tag := gf128Add(gf128Mul(c1, h1cu), gf128Mul(c2, h1sq), gf128Mul(lengths, h[0]), e[0])
+
+	sealed = make([]byte, 48)
+	gf128Store(sealed[0:], c1)
+	gf128Store(sealed[16:], c2)
+	gf128Store(sealed[32:], tag)
+	return
+}
+
+func TestCommittingSalamander(t *testing.T) {
+	key1, key2 := testBytes(0, 32), testBytes(0x80, 32)
+	nonce := testBytes(0x40, Aes256Gcm_NPubBytes)
+
+	// the attack works on AES-GCM itself.
+	sealed := salamander(key1, key2, nonce)
+	plain1, err1 := NewAes256Gcm(key1).Open(nil, nonce, sealed, nil)
+	plain2, err2 := NewAes256Gcm(key2).Open(nil, nonce, sealed, nil)
+	if err1 != nil || err2 != nil || bytes.Equal(plain1, plain2) {
+		t.Fatal("crafted message did not open under both keys", err1, err2)
+	}
+
+	// craft the message for the inner keys, and add the commitment of the
+	// first key.
+	inner1, _ := generichash.Blake2bTupleSum(nil, 32, key1, committingEncLabel)
+	inner2, _ := generichash.Blake2bTupleSum(nil, 32, key2, committingEncLabel)
+	sealed = salamander(inner1, inner2, nonce)
+
+	a1 := NewCommitting(key1, NewAes256Gcm)
+	a2 := NewCommitting(key2, NewAes256Gcm)
+	commitment := a1.Seal(nil, nonce, nil, nil)[Aes256Gcm_ABytes:]
+	sealed = append(sealed, commitment...)
+
+	if _, err := a1.Open(nil, nonce, sealed, nil); err != nil {
+		t.Error("crafted message did not open under the first key", err)
+	}
+	if _, err := a2.Open(nil, nonce, sealed, nil); err != godium.ErrForgedOrCorrupted {
+		t.Error("crafted message was accepted under the second key", err)
+	}
+}
+
+func TestCommittingConcurrent(t *testing.T) {
+	for _, v := range aeads {
+		newAEAD := v.new
+		testAEADConcurrent(t, "committing "+v.name, func(key []byte) godium.AEAD {
+			return NewCommitting(key, newAEAD)
+		})
+	}
+}
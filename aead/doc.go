@@ -6,5 +6,28 @@
 
 /*
 
- */
+Package AEAD implements authenticated encryption with additional data. The
+AEADs only keep their key between calls, so a single instance can be shared
+by many goroutines, like any cipher.AEAD.
+
+None of the AEADs based on Poly1305 or GHASH commit to their key: a
+ciphertext can be crafted that opens under two keys. NewCommitting wraps any
+AEAD in a key-committing variant, for designs where a message is decrypted by
+several recipients, or with keys chosen by an attacker.
+
+*/
 package aead // import "go.artemisc.eu/godium/aead"
+
+import (
+	"errors"
+)
+
+var (
+	// ErrADAfterMessage is returned when additional data is written to an
+	// incremental Sealer or Opener after the message has started.
+	ErrADAfterMessage = errors.New("aead: additional data written after the message")
+
+	// ErrFinished is returned when an incremental Sealer or Opener is used
+	// after Final.
+	ErrFinished = errors.New("aead: incremental operation already finished")
+)
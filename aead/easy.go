@@ -0,0 +1,55 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+)
+
+const (
+	// Easy_Version is the format version written by Encrypt.
+	Easy_Version = internal.EasyVersion
+
+	// Easy_HeaderBytes is the size of the version header, which is followed by
+	// the nonce.
+	Easy_HeaderBytes = internal.EasyHeaderBytes
+)
+
+// Encrypt seals plain with a random nonce read from rnd, and appends the
+// version header, the nonce and the sealed message to dst. The nonce size of
+// the AEAD is used, so the caller never handles nonces. Random nonces are
+// only safe for the extended nonce AEADs, or for a limited number of
+// messages under the same key. The ad is not part of the output. The output
+// must not overlap plain.
+//
+// The message is sealed with the version header and the nonce in front of
+// the ad, so the whole header is authenticated.
+func Encrypt(a godium.AEAD, rnd godium.Random, dst, plain, ad []byte) (cipher []byte, err error) {
+	out, nonce, err := internal.EasyHeader(dst, rnd, a.NPubBytes())
+	if err != nil {
+		return
+	}
+
+	bound := internal.EasyAD(out, ad)
+	cipher = internal.EasyAppend(out, a.Seal(out[len(out):], nonce, plain, bound))
+	return
+}
+
+// Decrypt opens a message created by Encrypt. It returns
+// godium.ErrUnknownVersion if the message was written with an unsupported
+// format version.
+func Decrypt(a godium.AEAD, dst, cipher, ad []byte) (plain []byte, err error) {
+	nonce, sealed, err := internal.EasyParse(cipher, a.NPubBytes(), a.ABytes())
+	if err != nil {
+		return
+	}
+
+	bound := internal.EasyAD(cipher[:len(cipher)-len(sealed)], ad)
+	plain, err = a.Open(dst, nonce, sealed, bound)
+	return
+}
@@ -0,0 +1,129 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/random"
+)
+
+func TestEasy(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range aeads {
+		a := v.new(testBytes(0, 32))
+		plain, ad := testPattern(100), []byte("additional data")
+
+		// the nonce is the first output of the generator.
+		nonce := make([]byte, a.NPubBytes())
+		random.NewDeterministic(testBytes(0x80, 32)).Buf(nonce)
+
+		rnd := random.NewDeterministic(testBytes(0x80, 32))
+		cipher, err := Encrypt(a, rnd, nil, plain, ad)
+		if err != nil {
+			t.Fatal(v.name, err)
+		}
+
+		// the header is authenticated in front of the ad.
+		header := append([]byte{Easy_Version}, nonce...)
+		bound := append(append([]byte{}, header...), ad...)
+		expect := append(header, a.Seal(nil, nonce, plain, bound)...)
+		if !bytes.Equal(expect, cipher) {
+			t.Error("expected result did not match computed", v.name, expect, cipher)
+		}
+
+		got, err := Decrypt(a, nil, cipher, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, plain, got, err)
+		}
+
+		// a second message uses a new nonce.
+		again, _ := Encrypt(a, rnd, nil, plain, ad)
+		if bytes.Equal(cipher[:Easy_HeaderBytes+a.NPubBytes()], again[:Easy_HeaderBytes+a.NPubBytes()]) {
+			t.Error("nonce was reused", v.name)
+		}
+
+		// appending to a buffer with enough room does not move the output.
+		buf := make([]byte, 3, 3+len(cipher))
+		out, _ := Encrypt(a, random.NewDeterministic(testBytes(0x80, 32)), buf, plain, ad)
+		if !bytes.Equal(cipher, out) || &out[0] != &buf[:4][3] {
+			t.Error("expected result did not match computed", v.name, cipher, out)
+		}
+	}
+}
+
+// allocAEAD ignores the capacity of dst and dstMac, and writes its output to
+// new buffers.
+type allocAEAD struct {
+	godium.AEAD
+}
+
+func (a allocAEAD) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
+	cipher = append(dst[:len(dst):len(dst)], a.AEAD.Seal(nil, nonce, plain, ad)...)
+	return
+}
+
+func (a allocAEAD) SealDetached(dst, dstMac, nonce, plain, ad []byte) (cipher, mac []byte) {
+	c, m := a.AEAD.SealDetached(nil, nil, nonce, plain, ad)
+	cipher = append(dst[:len(dst):len(dst)], c...)
+	mac = append(dstMac[:len(dstMac):len(dstMac)], m...)
+	return
+}
+
+func (a allocAEAD) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
+	p, err := a.AEAD.Open(nil, nonce, cipher, ad)
+	plain = append(dst[:len(dst):len(dst)], p...)
+	return
+}
+
+func (a allocAEAD) OpenDetached(dst, nonce, cipher, mac, ad []byte) (plain []byte, err error) {
+	p, err := a.AEAD.OpenDetached(nil, nonce, cipher, mac, ad)
+	plain = append(dst[:len(dst):len(dst)], p...)
+	return
+}
+
+func TestEasyAllocatingSeal(t *testing.T) {
+	skipNonceReuse(t)
+
+	a := NewXChacha20Poly1305Ietf(testBytes(0, 32))
+	plain, ad := testPattern(100), []byte("additional data")
+	expect, _ := Encrypt(a, random.NewDeterministic(testBytes(0x80, 32)), nil, plain, ad)
+
+	buf := make([]byte, 3, 3+len(expect))
+	out, err := Encrypt(allocAEAD{a}, random.NewDeterministic(testBytes(0x80, 32)), buf, plain, ad)
+	if err != nil || !bytes.Equal(expect, out) {
+		t.Error("expected result did not match computed", expect, out, err)
+	}
+}
+
+func TestEasyInvalid(t *testing.T) {
+	rnd := random.NewDeterministic(testBytes(0x80, 32))
+
+	for _, v := range aeads {
+		a := v.new(testBytes(0, 32))
+		cipher, _ := Encrypt(a, rnd, nil, testPattern(100), nil)
+
+		cipher[0] = Easy_Version + 1
+		if _, err := Decrypt(a, nil, cipher, nil); err != godium.ErrUnknownVersion {
+			t.Error("unknown version was accepted", v.name, err)
+		}
+		cipher[0] = Easy_Version
+
+		cipher[1] ^= 1
+		if _, err := Decrypt(a, nil, cipher, nil); err != godium.ErrForgedOrCorrupted {
+			t.Error("forged nonce was accepted", v.name, err)
+		}
+
+		short := cipher[:Easy_HeaderBytes+a.NPubBytes()+a.ABytes()-1]
+		if _, err := Decrypt(a, nil, short, nil); err != godium.ErrCipherTooShort {
+			t.Error("short message was accepted", v.name, err)
+		}
+	}
+}
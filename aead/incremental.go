@@ -0,0 +1,488 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"encoding/binary"
+	"io"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/auth"
+	"go.artemisc.eu/godium/noncecheck"
+	"go.artemisc.eu/godium/onetimeauth"
+	"go.artemisc.eu/godium/random"
+	"go.artemisc.eu/godium/stream"
+)
+
+const (
+	// incrementalBufferBytes is the size of the chunks the Sealer encrypts,
+	// and the Opener spools and decrypts, at a time.
+	incrementalBufferBytes = 4096
+
+	// incrementalSpoolTagBytes is the size of the tag that follows each chunk
+	// of ciphertext in the spool of an Opener.
+	incrementalSpoolTagBytes = auth.HmacSha512256_Bytes
+)
+
+var (
+	spoolReader = random.New()
+)
+
+// incremental holds the state shared by the Sealer and the Opener of the
+// ChaCha20-Poly1305 family. The IETF constructions pad the additional data
+// and the ciphertext to 16 bytes, and authenticate both lengths at the end,
+// while the original construction authenticates each length right after
+// its data.
+type incremental struct {
+	s     godium.Stream
+	p     onetimeauth.Poly1305
+	ietf  bool
+	adLen uint64
+	mLen  uint64
+	msg   bool
+	done  bool
+}
+
+// init keys Poly1305 with the first 32 bytes of keystream block 0, which
+// leaves the stream at block 1.
+func (c *incremental) init(s godium.Stream, ietf bool) {
+	var block0 [stream.Chacha20_BlockBytes]byte
+
+	s.KeyStream(block0[:])
+	c.p.ReKey(block0[:onetimeauth.Poly1305_KeyBytes])
+	godium.Wipe(block0[:])
+
+	c.s = s
+	c.ietf = ietf
+}
+
+// writeAD absorbs additional data. It fails once the message has started.
+func (c *incremental) writeAD(ad []byte) (n int, err error) {
+	switch {
+	case c.done:
+		err = ErrFinished
+	case c.msg:
+		err = ErrADAfterMessage
+	default:
+		c.p.Write(ad)
+		c.adLen += uint64(len(ad))
+		n = len(ad)
+	}
+	return
+}
+
+// startMessage ends the additional data.
+func (c *incremental) startMessage() {
+	var slen [8]byte
+
+	if c.msg {
+		return
+	}
+	c.msg = true
+
+	if c.ietf {
+		c.p.Write(pad0[:(0x10-c.adLen)&0xf])
+	} else {
+		binary.LittleEndian.PutUint64(slen[:], c.adLen)
+		c.p.Write(slen[:])
+	}
+}
+
+// absorb authenticates the next part of the ciphertext. It panics if an IETF
+// message grows longer than its counter allows.
+func (c *incremental) absorb(cipher []byte) {
+	c.mLen += uint64(len(cipher))
+	if c.ietf && c.mLen > Chacha20Poly1305Ietf_MessageBytesMax {
+		panic("aead: message too long for ChaCha20-Poly1305")
+	}
+	c.p.Write(cipher)
+}
+
+// finish absorbs the padding and lengths that end the message.
+func (c *incremental) finish() {
+	var slen [16]byte
+
+	c.startMessage()
+	c.done = true
+
+	if c.ietf {
+		c.p.Write(pad0[:(0x10-c.mLen)&0xf])
+		binary.LittleEndian.PutUint64(slen[:8], c.adLen)
+		binary.LittleEndian.PutUint64(slen[8:], c.mLen)
+		c.p.Write(slen[:])
+	} else {
+		binary.LittleEndian.PutUint64(slen[:8], c.mLen)
+		c.p.Write(slen[:8])
+	}
+}
+
+// wipe erases the keys.
+func (c *incremental) wipe() {
+	c.s.Wipe()
+	c.p.Wipe()
+	c.done = true
+}
+
+// Sealer encrypts a message of any length incrementally, and produces the
+// same bytes as Seal of the matching AEAD. All additional data must be
+// written with WriteAD before the message is written with Write. The
+// ciphertext is written to the underlying writer as it is produced, and
+// Final appends the tag.
+type Sealer struct {
+	incremental
+	w   io.Writer
+	buf [incrementalBufferBytes]byte
+}
+
+// NewChacha20Poly1305Sealer
+func NewChacha20Poly1305Sealer(w io.Writer, key, nonce []byte) (s *Sealer) {
+	noncecheck.Record("aead chacha20poly1305", key, nonce[:Chacha20Poly1305_NPubBytes])
+	s = &Sealer{w: w}
+	s.init(stream.NewChacha20(key, nonce), false)
+	return
+}
+
+// NewChacha20Poly1305IetfSealer
+func NewChacha20Poly1305IetfSealer(w io.Writer, key, nonce []byte) (s *Sealer) {
+	noncecheck.Record("aead chacha20poly1305ietf", key, nonce[:Chacha20Poly1305Ietf_NPubBytes])
+	s = &Sealer{w: w}
+	s.init(stream.NewChacha20Ietf(key, nonce), true)
+	return
+}
+
+// NewXChacha20Poly1305IetfSealer
+func NewXChacha20Poly1305IetfSealer(w io.Writer, key, nonce []byte) (s *Sealer) {
+	// ChaCha20 with a 32 bit counter under the subkey is the same as XChaCha20
+	// for messages up to Chacha20Poly1305Ietf_MessageBytesMax.
+	noncecheck.Record("aead xchacha20poly1305ietf", key, nonce[:XChacha20Poly1305Ietf_NPubBytes])
+	s = &Sealer{w: w}
+	s.init(stream.NewXChacha20(key, nonce), true)
+	return
+}
+
+// WriteAD absorbs the next part of the additional data. It returns
+// ErrADAfterMessage once the message has started.
+func (s *Sealer) WriteAD(ad []byte) (n int, err error) {
+	n, err = s.writeAD(ad)
+	return
+}
+
+// Write encrypts the next part of the message, and writes the ciphertext to
+// the underlying writer.
+func (s *Sealer) Write(plain []byte) (n int, err error) {
+	if s.done {
+		err = ErrFinished
+		return
+	}
+	s.startMessage()
+
+	for len(plain) > 0 {
+		chunk := s.buf[:]
+		if len(plain) < len(chunk) {
+			chunk = chunk[:len(plain)]
+		}
+
+		s.s.XORKeyStream(chunk, plain[:len(chunk)])
+		s.absorb(chunk)
+		if _, err = s.w.Write(chunk); err != nil {
+			return
+		}
+
+		n += len(chunk)
+		plain = plain[len(chunk):]
+	}
+	return
+}
+
+// Final writes the tag to the underlying writer, and wipes the keys.
+func (s *Sealer) Final() (err error) {
+	var mac [Chacha20Poly1305_ABytes]byte
+
+	if s.done {
+		err = ErrFinished
+		return
+	}
+
+	s.finish()
+	s.p.Sum(mac[:0])
+	s.Wipe()
+
+	_, err = s.w.Write(mac[:])
+	return
+}
+
+// Wipe erases the keys and the buffered ciphertext.
+func (s *Sealer) Wipe() {
+	s.wipe()
+	godium.Wipe(s.buf[:])
+}
+
+// Opener authenticates a message of any length incrementally, and only
+// releases the plaintext once the tag has been verified, so unverified
+// plaintext is never exposed. The ciphertext, followed by the tag, is written
+// with Write after all additional data has been written with WriteAD.
+//
+// The Opener keeps the ciphertext in memory, or in the spool provided by the
+// caller, like a temporary file. The spool is used from the offset it has when
+// the first chunk is spooled, and must not be used by the caller until Final
+// returns. Only ciphertext is spooled, in chunks that are each followed by an
+// HMAC-SHA-512/256 tag under a random key that never leaves the Opener. Final
+// checks every chunk before it releases any plaintext, so a spool that is
+// modified after the message was verified cannot make the Opener release
+// plaintext that was not authenticated.
+type Opener struct {
+	incremental
+	err        error
+	spool      io.ReadWriteSeeker
+	spoolMac   godium.Auth
+	spoolStart int64
+	chunks     uint64
+	chunkLen   int
+	chunk      [incrementalBufferBytes]byte
+	mem        []byte
+	tail       [Chacha20Poly1305_ABytes]byte
+	tailLen    int
+	buf        [incrementalBufferBytes + incrementalSpoolTagBytes]byte
+}
+
+// NewChacha20Poly1305Opener creates an Opener that keeps the ciphertext in
+// the spool, or in memory if spool is nil.
+func NewChacha20Poly1305Opener(spool io.ReadWriteSeeker, key, nonce []byte) (o *Opener) {
+	o = &Opener{spool: spool}
+	o.init(stream.NewChacha20(key, nonce), false)
+	return
+}
+
+// NewChacha20Poly1305IetfOpener creates an Opener that keeps the ciphertext
+// in the spool, or in memory if spool is nil.
+func NewChacha20Poly1305IetfOpener(spool io.ReadWriteSeeker, key, nonce []byte) (o *Opener) {
+	o = &Opener{spool: spool}
+	o.init(stream.NewChacha20Ietf(key, nonce), true)
+	return
+}
+
+// NewXChacha20Poly1305IetfOpener creates an Opener that keeps the ciphertext
+// in the spool, or in memory if spool is nil.
+func NewXChacha20Poly1305IetfOpener(spool io.ReadWriteSeeker, key, nonce []byte) (o *Opener) {
+	o = &Opener{spool: spool}
+	o.init(stream.NewXChacha20(key, nonce), true)
+	return
+}
+
+// WriteAD absorbs the next part of the additional data. It returns
+// ErrADAfterMessage once the ciphertext has started.
+func (o *Opener) WriteAD(ad []byte) (n int, err error) {
+	n, err = o.writeAD(ad)
+	return
+}
+
+// Write absorbs the next part of the ciphertext and tag. The last
+// Chacha20Poly1305_ABytes bytes written are held back as the tag.
+//
+// If the ciphertext can not be spooled, the message can no longer be opened:
+// the keys are wiped, and Write and Final return the error of the spool.
+func (o *Opener) Write(cipher []byte) (n int, err error) {
+	if o.err != nil {
+		err = o.err
+		return
+	}
+	if o.done {
+		err = ErrFinished
+		return
+	}
+	o.startMessage()
+	n = len(cipher)
+
+	if o.tailLen+len(cipher) <= len(o.tail) {
+		o.tailLen += copy(o.tail[o.tailLen:], cipher)
+		return
+	}
+
+	// everything but the last bytes written is ciphertext.
+	release := o.tailLen + len(cipher) - len(o.tail)
+	if release <= o.tailLen {
+		err = o.store(o.tail[:release])
+		copy(o.tail[:], o.tail[release:o.tailLen])
+		copy(o.tail[o.tailLen-release:], cipher)
+	} else {
+		if err = o.store(o.tail[:o.tailLen]); err == nil {
+			err = o.store(cipher[:release-o.tailLen])
+		}
+		copy(o.tail[:], cipher[release-o.tailLen:])
+	}
+	o.tailLen = len(o.tail)
+
+	if err != nil {
+		o.err = err
+		o.Wipe()
+		n = 0
+	}
+	return
+}
+
+// store authenticates and keeps a part of the ciphertext. With a spool, the
+// ciphertext is collected into chunks, and every full chunk is spooled.
+func (o *Opener) store(cipher []byte) (err error) {
+	o.absorb(cipher)
+
+	if o.spool == nil {
+		o.mem = append(o.mem, cipher...)
+		return
+	}
+
+	for len(cipher) > 0 && err == nil {
+		n := copy(o.chunk[o.chunkLen:], cipher)
+		o.chunkLen += n
+		cipher = cipher[n:]
+
+		if o.chunkLen == len(o.chunk) {
+			err = o.spoolChunk()
+		}
+	}
+	return
+}
+
+// spoolChunk writes the full chunk to the spool, followed by its tag. The
+// first chunk sets up the key of the tags and the offset of the spool.
+func (o *Opener) spoolChunk() (err error) {
+	if o.spoolMac == nil {
+		var key godium.Key
+
+		if o.spoolStart, err = o.spool.Seek(0, io.SeekCurrent); err != nil {
+			return
+		}
+		if key, err = auth.KeyGenHmacSha512256(spoolReader); err != nil {
+			return
+		}
+		o.spoolMac = auth.NewHmacSha512256(key)
+		godium.Wipe(key)
+	}
+
+	n := copy(o.buf[:], o.chunk[:])
+	o.chunkMac(o.chunks, o.chunk[:]).Sum(o.buf[:n])
+	if _, err = o.spool.Write(o.buf[:n+incrementalSpoolTagBytes]); err != nil {
+		return
+	}
+
+	o.chunks++
+	o.chunkLen = 0
+	return
+}
+
+// chunkMac resets the spool MAC and absorbs the chunk with the given index.
+// The index keeps chunks from being reordered in the spool.
+func (o *Opener) chunkMac(index uint64, chunk []byte) (mac godium.Auth) {
+	var idx [8]byte
+
+	binary.LittleEndian.PutUint64(idx[:], index)
+	mac = o.spoolMac
+	mac.Reset()
+	mac.Write(idx[:])
+	mac.Write(chunk)
+	return
+}
+
+// Final verifies the tag, and writes the plaintext to w only if the message
+// is authentic. It returns ErrCipherTooShort if less than a tag was written,
+// and ErrForgedOrCorrupted if the tag is invalid. The keys are wiped in both
+// cases.
+//
+// With a spool, Final reads it twice: every chunk is checked against its tag
+// before any plaintext is written to w, and checked again as it is decrypted.
+// A chunk that does not match its tag makes Final return ErrForgedOrCorrupted
+// without releasing any plaintext. Only a spool that is modified while Final
+// runs can make it stop after writing part of the plaintext, which is then
+// still authentic.
+func (o *Opener) Final(w io.Writer) (err error) {
+	if o.err != nil {
+		err = o.err
+		return
+	}
+	if o.done {
+		err = ErrFinished
+		return
+	}
+	defer o.Wipe()
+
+	if o.tailLen < len(o.tail) {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	o.finish()
+	if !o.p.Verify(o.tail[:]) {
+		err = godium.ErrForgedOrCorrupted
+		return
+	}
+
+	if o.spool == nil {
+		err = o.release(w, o.mem)
+		return
+	}
+
+	err = o.rewind()
+	for i := uint64(0); i < o.chunks && err == nil; i++ {
+		_, err = o.readChunk(i)
+	}
+	if err != nil {
+		return
+	}
+
+	err = o.rewind()
+	for i := uint64(0); i < o.chunks && err == nil; i++ {
+		var chunk []byte
+		if chunk, err = o.readChunk(i); err == nil {
+			err = o.release(w, chunk)
+		}
+	}
+	if err == nil {
+		err = o.release(w, o.chunk[:o.chunkLen])
+	}
+	return
+}
+
+// rewind seeks the spool back to the first chunk.
+func (o *Opener) rewind() (err error) {
+	if o.chunks > 0 {
+		_, err = o.spool.Seek(o.spoolStart, io.SeekStart)
+	}
+	return
+}
+
+// readChunk reads the next chunk from the spool into buf, and checks it
+// against its tag.
+func (o *Opener) readChunk(index uint64) (chunk []byte, err error) {
+	chunk = o.buf[:incrementalBufferBytes]
+	if _, err = io.ReadFull(o.spool, o.buf[:]); err != nil {
+		return
+	}
+
+	if !o.chunkMac(index, chunk).Verify(o.buf[len(chunk):]) {
+		err = godium.ErrForgedOrCorrupted
+	}
+	return
+}
+
+// release decrypts the verified ciphertext in place and writes it to w.
+func (o *Opener) release(w io.Writer, cipher []byte) (err error) {
+	o.s.XORKeyStream(cipher, cipher)
+	_, err = w.Write(cipher)
+	godium.Wipe(cipher)
+	return
+}
+
+// Wipe erases the keys and the buffered plaintext and ciphertext.
+func (o *Opener) Wipe() {
+	o.wipe()
+	if o.spoolMac != nil {
+		o.spoolMac.Wipe()
+	}
+	godium.Wipe(o.mem)
+	godium.Wipe(o.chunk[:])
+	godium.Wipe(o.buf[:])
+	o.mem = nil
+}
@@ -0,0 +1,301 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/noncecheck"
+)
+
+// incrementalVariants pairs the incremental APIs with their one-shot AEAD.
+var incrementalVariants = []struct {
+	name   string
+	new    func(key []byte) godium.AEAD
+	sealer func(w io.Writer, key, nonce []byte) *Sealer
+	opener func(spool io.ReadWriteSeeker, key, nonce []byte) *Opener
+}{
+	{"chacha20poly1305", NewChacha20Poly1305,
+		NewChacha20Poly1305Sealer, NewChacha20Poly1305Opener},
+	{"chacha20poly1305_ietf", NewChacha20Poly1305Ietf,
+		NewChacha20Poly1305IetfSealer, NewChacha20Poly1305IetfOpener},
+	{"xchacha20poly1305_ietf", NewXChacha20Poly1305Ietf,
+		NewXChacha20Poly1305IetfSealer, NewXChacha20Poly1305IetfOpener},
+}
+
+// writeChunks writes data to w in chunks of random sizes, up to max bytes.
+func writeChunks(w func([]byte) (int, error), r *rand.Rand, data []byte, max int) (err error) {
+	for len(data) > 0 && err == nil {
+		n := r.Intn(max + 1)
+		if n > len(data) {
+			n = len(data)
+		}
+		_, err = w(data[:n])
+		data = data[n:]
+	}
+	return
+}
+
+func TestIncremental(t *testing.T) {
+	skipNonceReuse(t)
+
+	r := rand.New(rand.NewSource(1))
+	key := testBytes(0, 32)
+
+	for _, v := range incrementalVariants {
+		a := v.new(key)
+		nonce := testBytes(0x40, a.NPubBytes())
+
+		for _, size := range []int{0, 1, 15, 16, 17, 63, 64, 65, 1000, 5000, 10000} {
+			plain, ad := testPattern(size), testPattern(size%50+size/3)
+			expect := a.Seal(nil, nonce, plain, ad)
+
+			var sealed bytes.Buffer
+			s := v.sealer(&sealed, key, nonce)
+			if err := writeChunks(s.WriteAD, r, ad, 40); err != nil {
+				t.Fatal(v.name, size, err)
+			}
+			if err := writeChunks(s.Write, r, plain, 5000); err != nil {
+				t.Fatal(v.name, size, err)
+			}
+			if err := s.Final(); err != nil {
+				t.Fatal(v.name, size, err)
+			}
+			if !bytes.Equal(expect, sealed.Bytes()) {
+				t.Error("expected result did not match computed", v.name, size)
+			}
+
+			var opened bytes.Buffer
+			o := v.opener(nil, key, nonce)
+			writeChunks(o.WriteAD, r, ad, 40)
+			writeChunks(o.Write, r, expect, 37)
+			if err := o.Final(&opened); err != nil || !bytes.Equal(plain, opened.Bytes()) {
+				t.Error("expected result did not match computed", v.name, size, err)
+			}
+		}
+	}
+}
+
+func TestIncrementalSpool(t *testing.T) {
+	noncecheck.Reset()
+
+	r := rand.New(rand.NewSource(2))
+	key := testBytes(0, 32)
+	prefix := []byte("existing contents before the spool")
+	chunk := incrementalBufferBytes + incrementalSpoolTagBytes
+
+	spool, err := ioutil.TempFile("", "godium-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	// open spools the ciphertext after prefix, and returns the opened message.
+	open := func(v int, sealed, ad []byte, tamper func()) (opened []byte, err error) {
+		var buf bytes.Buffer
+
+		spool.Truncate(0)
+		spool.Seek(0, io.SeekStart)
+		spool.Write(prefix)
+
+		a := incrementalVariants[v].new(key)
+		o := incrementalVariants[v].opener(spool, key, testBytes(0x40, a.NPubBytes()))
+		o.WriteAD(ad)
+		writeChunks(o.Write, r, sealed, 3000)
+		if tamper != nil {
+			tamper()
+		}
+		err = o.Final(&buf)
+		opened = buf.Bytes()
+		return
+	}
+
+	for i, v := range incrementalVariants {
+		a := v.new(key)
+		nonce := testBytes(0x40, a.NPubBytes())
+		plain, ad := testPattern(10000), []byte("additional data")
+		sealed := a.Seal(nil, nonce, plain, ad)
+
+		opened, err := open(i, sealed, ad, nil)
+		if err != nil || !bytes.Equal(plain, opened) {
+			t.Error("expected result did not match computed", v.name, err)
+		}
+
+		// only full chunks of ciphertext are spooled, each followed by a tag.
+		spooled, _ := ioutil.ReadFile(spool.Name())
+		if !bytes.HasPrefix(spooled, prefix) || len(spooled) != len(prefix)+2*chunk {
+			t.Fatal("unexpected spool contents", v.name, len(spooled))
+		}
+		spooled = spooled[len(prefix):]
+		for c := 0; c < 2; c++ {
+			got := spooled[c*chunk : c*chunk+incrementalBufferBytes]
+			if !bytes.Equal(sealed[c*incrementalBufferBytes:(c+1)*incrementalBufferBytes], got) {
+				t.Error("spool does not hold the ciphertext", v.name, c)
+			}
+		}
+
+		flip := func(off int64) func() {
+			return func() {
+				b := make([]byte, 1)
+				spool.ReadAt(b, off)
+				b[0] ^= 1
+				spool.WriteAt(b, off)
+			}
+		}
+
+		// a modified spool releases no plaintext, wherever the change is.
+		tampers := []struct {
+			name   string
+			err    error
+			tamper func()
+		}{
+			{"first chunk", godium.ErrForgedOrCorrupted, flip(int64(len(prefix) + 10))},
+			{"second tag", godium.ErrForgedOrCorrupted, flip(int64(len(prefix) + 2*chunk - 1))},
+			{"truncated", io.ErrUnexpectedEOF, func() { spool.Truncate(int64(len(prefix) + 2*chunk - 1)) }},
+			{"swapped chunks", godium.ErrForgedOrCorrupted, func() {
+				first, second := make([]byte, chunk), make([]byte, chunk)
+				spool.ReadAt(first, int64(len(prefix)))
+				spool.ReadAt(second, int64(len(prefix)+chunk))
+				spool.WriteAt(second, int64(len(prefix)))
+				spool.WriteAt(first, int64(len(prefix)+chunk))
+			}},
+		}
+		for _, c := range tampers {
+			opened, err := open(i, sealed, ad, c.tamper)
+			if err != c.err {
+				t.Error("modified spool was accepted", v.name, c.name, err)
+			}
+			if len(opened) != 0 {
+				t.Error("plaintext of a modified spool was released", v.name, c.name, len(opened))
+			}
+		}
+	}
+}
+
+// failingSpool accepts a number of writes, and fails all writes after them.
+type failingSpool struct {
+	bytes.Buffer
+	writes int
+}
+
+var errSpool = errors.New("spool full")
+
+func (s *failingSpool) Write(p []byte) (n int, err error) {
+	if s.writes == 0 {
+		err = errSpool
+		return
+	}
+	s.writes--
+	n, err = s.Buffer.Write(p)
+	return
+}
+
+func (s *failingSpool) Seek(offset int64, whence int) (n int64, err error) {
+	return
+}
+
+func TestIncrementalSpoolError(t *testing.T) {
+	noncecheck.Reset()
+
+	key := testBytes(0, 32)
+
+	for _, v := range incrementalVariants {
+		a := v.new(key)
+		nonce := testBytes(0x40, a.NPubBytes())
+		sealed := a.Seal(nil, nonce, testPattern(10000), nil)
+
+		// the second chunk can not be spooled
+		o := v.opener(&failingSpool{writes: 1}, key, nonce)
+		if _, err := o.Write(sealed[:incrementalBufferBytes+Chacha20Poly1305_ABytes]); err != nil {
+			t.Fatal(v.name, err)
+		}
+		if n, err := o.Write(sealed[incrementalBufferBytes+Chacha20Poly1305_ABytes:]); err != errSpool || n != 0 {
+			t.Error("spool error was not returned", v.name, n, err)
+		}
+
+		// the opener stays failed, even for writes that need no spooling
+		if _, err := o.Write(nil); err != errSpool {
+			t.Error("opener was used after a spool error", v.name, err)
+		}
+
+		var opened bytes.Buffer
+		if err := o.Final(&opened); err != errSpool || opened.Len() != 0 {
+			t.Error("opener was used after a spool error", v.name, opened.Len(), err)
+		}
+	}
+}
+
+func TestIncrementalForged(t *testing.T) {
+	noncecheck.Reset()
+
+	key := testBytes(0, 32)
+
+	for _, v := range incrementalVariants {
+		a := v.new(key)
+		nonce := testBytes(0x40, a.NPubBytes())
+		plain, ad := testPattern(100), []byte("additional data")
+		sealed := a.Seal(nil, nonce, plain, ad)
+
+		for _, i := range []int{0, 50, len(sealed) - 1} {
+			forged := append([]byte(nil), sealed...)
+			forged[i] ^= 1
+
+			var opened bytes.Buffer
+			o := v.opener(nil, key, nonce)
+			o.WriteAD(ad)
+			o.Write(forged)
+			if err := o.Final(&opened); err != godium.ErrForgedOrCorrupted {
+				t.Error("forged message was accepted", v.name, i, err)
+			}
+			if opened.Len() != 0 {
+				t.Error("plaintext of a forged message was released", v.name, i)
+			}
+		}
+
+		o := v.opener(nil, key, nonce)
+		o.Write(sealed[:Chacha20Poly1305_ABytes-1])
+		if err := o.Final(ioutil.Discard); err != godium.ErrCipherTooShort {
+			t.Error("short ciphertext was accepted", v.name, err)
+		}
+		if err := o.Final(ioutil.Discard); err != ErrFinished {
+			t.Error("opener was used after Final", v.name, err)
+		}
+	}
+}
+
+func TestIncrementalOrder(t *testing.T) {
+	noncecheck.Reset()
+
+	key, nonce := testBytes(0, 32), testBytes(0x40, Chacha20Poly1305Ietf_NPubBytes)
+
+	s := NewChacha20Poly1305IetfSealer(ioutil.Discard, key, nonce)
+	s.Write([]byte("message"))
+	if _, err := s.WriteAD([]byte("ad")); err != ErrADAfterMessage {
+		t.Error("additional data was accepted after the message", err)
+	}
+	s.Final()
+	if _, err := s.Write([]byte("message")); err != ErrFinished {
+		t.Error("sealer was used after Final", err)
+	}
+	if err := s.Final(); err != ErrFinished {
+		t.Error("sealer was used after Final", err)
+	}
+
+	o := NewChacha20Poly1305IetfOpener(nil, key, nonce)
+	o.Write([]byte("message"))
+	if _, err := o.WriteAD([]byte("ad")); err != ErrADAfterMessage {
+		t.Error("additional data was accepted after the message", err)
+	}
+}
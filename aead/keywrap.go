@@ -0,0 +1,72 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+)
+
+// WrapKeyXChacha20Blake2bSiv deterministically encrypts key under the key
+// encryption key kek, bound to the optional additional data, with
+// XChaCha20-BLAKE2b-SIV without a nonce. Wrapping the same key twice gives the
+// same result, which only reveals that the keys are equal. The result is
+// len(key) + XChacha20Blake2bSiv_ABytes long, and appended to dst.
+func WrapKeyXChacha20Blake2bSiv(dst, kek, key, ad []byte) (wrapped []byte) {
+	var a xchacha20blake2bsiv
+
+	klen := uint64(len(key))
+	wrapped = internal.AllocDst(dst, klen+XChacha20Blake2bSiv_ABytes)
+
+	a.init(kek)
+	a.seal(wrapped[:klen], wrapped[klen:], nil, key, ad)
+	a.Wipe()
+	return
+}
+
+// UnwrapKeyXChacha20Blake2bSiv decrypts a key wrapped with
+// WrapKeyXChacha20Blake2bSiv, and appends it to dst.
+func UnwrapKeyXChacha20Blake2bSiv(dst, kek, wrapped, ad []byte) (key []byte, err error) {
+	var a xchacha20blake2bsiv
+
+	if len(wrapped) < XChacha20Blake2bSiv_ABytes {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	klen := uint64(len(wrapped) - XChacha20Blake2bSiv_ABytes)
+	key = internal.AllocDst(dst, klen)
+
+	a.init(kek)
+	err = a.open(key, nil, wrapped[:klen], wrapped[klen:], ad)
+	a.Wipe()
+	return
+}
+
+// WrapKeyAes256GcmSiv deterministically encrypts key under the key
+// encryption key kek, bound to the optional additional data, with
+// AES-256-GCM-SIV and an all zero nonce. The result is
+// len(key) + Aes256GcmSiv_ABytes long, and appended to dst.
+func WrapKeyAes256GcmSiv(dst, kek, key, ad []byte) (wrapped []byte) {
+	var nonce [Aes256GcmSiv_NPubBytes]byte
+
+	a := NewAes256GcmSiv(kek)
+	wrapped = a.Seal(dst, nonce[:], key, ad)
+	a.Wipe()
+	return
+}
+
+// UnwrapKeyAes256GcmSiv decrypts a key wrapped with WrapKeyAes256GcmSiv, and
+// appends it to dst.
+func UnwrapKeyAes256GcmSiv(dst, kek, wrapped, ad []byte) (key []byte, err error) {
+	var nonce [Aes256GcmSiv_NPubBytes]byte
+
+	a := NewAes256GcmSiv(kek)
+	key, err = a.Open(dst, nonce[:], wrapped, ad)
+	a.Wipe()
+	return
+}
@@ -0,0 +1,125 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// polyval implements the POLYVAL universal hash of RFC 8452, section 3. Input
+// is absorbed in 16 byte blocks, a partial block at the end of Update is
+// padded with zeros.
+type polyval struct {
+	h [2]uint64
+	s [2]uint64
+}
+
+// init sets the hash key, and clears the accumulator.
+func (p *polyval) init(key []byte) {
+	p.h[0] = binary.LittleEndian.Uint64(key[0:])
+	p.h[1] = binary.LittleEndian.Uint64(key[8:])
+	p.s = [2]uint64{}
+}
+
+// update absorbs data, padded with zeros to a multiple of 16 bytes.
+func (p *polyval) update(data []byte) {
+	var block [16]byte
+
+	for len(data) > 0 {
+		in := data
+		if len(data) < len(block) {
+			copy(block[:], data)
+			in = block[:]
+		}
+
+		p.s[0] ^= binary.LittleEndian.Uint64(in[0:])
+		p.s[1] ^= binary.LittleEndian.Uint64(in[8:])
+		p.s = polyvalDot(p.s, p.h)
+
+		if len(data) < len(block) {
+			break
+		}
+		data = data[len(block):]
+	}
+}
+
+// sum writes the hash to out.
+func (p *polyval) sum(out *[16]byte) {
+	binary.LittleEndian.PutUint64(out[0:], p.s[0])
+	binary.LittleEndian.PutUint64(out[8:], p.s[1])
+}
+
+// wipe erases the key and the accumulator.
+func (p *polyval) wipe() {
+	p.h = [2]uint64{}
+	p.s = [2]uint64{}
+}
+
+// polyvalDot computes a * b * x^-128 in the POLYVAL field. The carry-less
+// products are computed with integer multiplications of sparse operands, so
+// the timing does not depend on the inputs. The reversed operands produce the
+// high halves of the products.
+func polyvalDot(a, b [2]uint64) (r [2]uint64) {
+	a0, a1 := a[0], a[1]
+	a0r, a1r := bits.Reverse64(a0), bits.Reverse64(a1)
+	a2, a2r := a0^a1, a0r^a1r
+
+	b0, b1 := b[0], b[1]
+	b0r, b1r := bits.Reverse64(b0), bits.Reverse64(b1)
+	b2, b2r := b0^b1, b0r^b1r
+
+	// Karatsuba multiplication of the 128 bit operands.
+	z0 := clmul64(a0, b0)
+	z1 := clmul64(a1, b1)
+	z2 := clmul64(a2, b2)
+	z0h := clmul64(a0r, b0r)
+	z1h := clmul64(a1r, b1r)
+	z2h := clmul64(a2r, b2r)
+
+	z2 ^= z0 ^ z1
+	z2h ^= z0h ^ z1h
+	z0h = bits.Reverse64(z0h) >> 1
+	z1h = bits.Reverse64(z1h) >> 1
+	z2h = bits.Reverse64(z2h) >> 1
+
+	v0 := z0
+	v1 := z0h ^ z2
+	v2 := z1 ^ z2h
+	v3 := z1h
+
+	// Montgomery reduction by x^128 + x^127 + x^126 + x^121 + 1.
+	v2 ^= v0 ^ v0>>1 ^ v0>>2 ^ v0>>7
+	v1 ^= v0<<63 ^ v0<<62 ^ v0<<57
+	v3 ^= v1 ^ v1>>1 ^ v1>>2 ^ v1>>7
+	v2 ^= v1<<63 ^ v1<<62 ^ v1<<57
+
+	r[0], r[1] = v2, v3
+	return
+}
+
+// clmul64 returns the lower 64 bits of the carry-less product of x and y. Every
+// fourth bit of the operands is multiplied at a time, leaving room for the
+// carries between the bits that are kept.
+func clmul64(x, y uint64) uint64 {
+	const (
+		m0 = 0x1111111111111111
+		m1 = 0x2222222222222222
+		m2 = 0x4444444444444444
+		m3 = 0x8888888888888888
+	)
+
+	x0, x1, x2, x3 := x&m0, x&m1, x&m2, x&m3
+	y0, y1, y2, y3 := y&m0, y&m1, y&m2, y&m3
+
+	z0 := x0*y0 ^ x1*y3 ^ x2*y2 ^ x3*y1
+	z1 := x0*y1 ^ x1*y0 ^ x2*y3 ^ x3*y2
+	z2 := x0*y2 ^ x1*y1 ^ x2*y0 ^ x3*y3
+	z3 := x0*y3 ^ x1*y2 ^ x2*y1 ^ x3*y0
+
+	return z0&m0 | z1&m1 | z2&m2 | z3&m3
+}
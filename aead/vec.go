@@ -0,0 +1,166 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/stream"
+)
+
+// vecAEAD is implemented by the AEADs that seal and open scattered buffers
+// directly, through the incremental state of the ChaCha20-Poly1305 family.
+type vecAEAD interface {
+	initIncremental(c *incremental, nonce []byte)
+	recordNonce(nonce []byte)
+}
+
+func (a *chacha20poly1305) initIncremental(c *incremental, nonce []byte) {
+	c.init(stream.NewChacha20(a.Key, nonce), false)
+}
+
+func (a *chacha20poly1305ietf) initIncremental(c *incremental, nonce []byte) {
+	c.init(stream.NewChacha20Ietf(a.Key, nonce), true)
+}
+
+func (a *xchacha20poly1305ietf) initIncremental(c *incremental, nonce []byte) {
+	c.init(stream.NewXChacha20(a.Key, nonce), true)
+}
+
+// SealVec seals the concatenation of the plain segments, with the
+// concatenation of the ad segments as additional data, and writes the same
+// bytes as Seal across the dst segments. It returns the number of bytes
+// written, and panics if the dst segments are shorter than that. The
+// ChaCha20-Poly1305 AEADs work on the segments directly, the others copy
+// through contiguous buffers. The plain and dst segments may only overlap if
+// they are the same memory at the same offsets.
+func SealVec(a godium.AEAD, dst [][]byte, nonce []byte, plain, ad [][]byte) (n int) {
+	var c incremental
+	var mac [Chacha20Poly1305_ABytes]byte
+
+	out, in := internal.NewVec(dst), internal.NewVec(plain)
+
+	v, ok := a.(vecAEAD)
+	if !ok {
+		// AEADs from other packages may pad the message, so the output of
+		// Seal is not always len(plain)+Overhead().
+		buf := internal.Gather(nil, plain)
+		sealed := a.Seal(nil, nonce, buf, internal.Gather(nil, ad))
+		godium.Wipe(buf)
+
+		n = len(sealed)
+		if out.Len() < n {
+			panic("aead: output segments too short")
+		}
+		out.Write(sealed)
+		return
+	}
+
+	n = in.Len() + a.Overhead()
+	if out.Len() < n {
+		panic("aead: output segments too short")
+	}
+
+	v.recordNonce(nonce)
+	v.initIncremental(&c, nonce)
+	for _, s := range ad {
+		c.writeAD(s)
+	}
+	c.startMessage()
+
+	for in.Len() > 0 {
+		src := in.Next(in.Len())
+		for len(src) > 0 {
+			d := out.Next(len(src))
+			c.s.XORKeyStream(d, src[:len(d)])
+			c.absorb(d)
+			src = src[len(d):]
+		}
+	}
+
+	c.finish()
+	c.p.Sum(mac[:0])
+	out.Write(mac[:])
+
+	c.wipe()
+	return
+}
+
+// OpenVec opens the concatenation of the cipher segments, with the
+// concatenation of the ad segments as additional data, and writes the same
+// bytes as Open across the dst segments. It returns the number of bytes
+// written, and panics if the dst segments are shorter than that. Nothing is
+// written if the message is not authentic. The cipher and dst segments may
+// only overlap if they are the same memory at the same offsets.
+func OpenVec(a godium.AEAD, dst [][]byte, nonce []byte, cipher, ad [][]byte) (n int, err error) {
+	var c incremental
+	var mac [Chacha20Poly1305_ABytes]byte
+
+	out, in := internal.NewVec(dst), internal.NewVec(cipher)
+	if in.Len() < a.Overhead() {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	v, ok := a.(vecAEAD)
+	if !ok {
+		var plain []byte
+
+		plain, err = a.Open(nil, nonce, internal.Gather(nil, cipher), internal.Gather(nil, ad))
+		if err == nil {
+			if out.Len() < len(plain) {
+				godium.Wipe(plain)
+				panic("aead: output segments too short")
+			}
+			n = out.Write(plain)
+		}
+
+		godium.Wipe(plain)
+		return
+	}
+
+	mlen := in.Len() - a.Overhead()
+	if out.Len() < mlen {
+		panic("aead: output segments too short")
+	}
+
+	v.initIncremental(&c, nonce)
+	defer c.wipe()
+
+	for _, s := range ad {
+		c.writeAD(s)
+	}
+	c.startMessage()
+
+	// authenticate the ciphertext, and come back to decrypt it.
+	start := in
+	for left := mlen; left > 0; {
+		src := in.Next(left)
+		c.absorb(src)
+		left -= len(src)
+	}
+	in.Read(mac[:])
+
+	c.finish()
+	if !c.p.Verify(mac[:]) {
+		err = godium.ErrForgedOrCorrupted
+		return
+	}
+
+	for left := mlen; left > 0; {
+		src := start.Next(left)
+		left -= len(src)
+		for len(src) > 0 {
+			d := out.Next(len(src))
+			c.s.XORKeyStream(d, src[:len(d)])
+			src = src[len(d):]
+		}
+	}
+
+	n = mlen
+	return
+}
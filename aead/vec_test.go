@@ -0,0 +1,167 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/noncecheck"
+	"go.artemisc.eu/godium/utils"
+)
+
+// segment splits data into segments of random sizes, some of them empty. The
+// segments share the memory of data.
+func segment(r *rand.Rand, data []byte) (segs [][]byte) {
+	for len(data) > 0 {
+		n := r.Intn(len(data)/2 + 2)
+		if n > len(data) {
+			n = len(data)
+		}
+		segs = append(segs, data[:n:n])
+		data = data[n:]
+	}
+	return append(segs, nil)
+}
+
+func TestVec(t *testing.T) {
+	skipNonceReuse(t)
+
+	r := rand.New(rand.NewSource(1))
+	key := testBytes(0, 32)
+
+	all := append([]struct {
+		name string
+		new  func(key []byte) godium.AEAD
+	}{{"committing chacha20poly1305_ietf", func(key []byte) godium.AEAD {
+		return NewCommitting(key, NewChacha20Poly1305Ietf)
+	}}}, aeads...)
+
+	for _, v := range all {
+		a := v.new(key)
+		nonce := testBytes(0x40, a.NPubBytes())
+
+		for _, size := range []int{0, 1, 16, 63, 64, 65, 200, 1000} {
+			for i := 0; i < 10; i++ {
+				plain, ad := testPattern(size), testPattern(r.Intn(100))
+				expect := a.Seal(nil, nonce, plain, ad)
+
+				sealed := make([]byte, len(expect))
+				n := SealVec(a, segment(r, sealed), nonce, segment(r, plain), segment(r, ad))
+				if n != len(expect) || !bytes.Equal(expect, sealed) {
+					t.Fatal("expected result did not match computed", v.name, size, i)
+				}
+
+				opened := make([]byte, size)
+				n, err := OpenVec(a, segment(r, opened), nonce, segment(r, sealed), segment(r, ad))
+				if err != nil || n != size || !bytes.Equal(plain, opened) {
+					t.Fatal("expected result did not match computed", v.name, size, i, err)
+				}
+
+				// in place, with the same segmentation.
+				buf := append(append([]byte(nil), plain...), make([]byte, a.Overhead())...)
+				segs := segment(r, buf)
+				SealVec(a, segs, nonce, segment(r, buf[:size]), segment(r, ad))
+				if !bytes.Equal(expect, buf) {
+					t.Fatal("expected result did not match computed", v.name, size, i)
+				}
+				_, err = OpenVec(a, segs, nonce, segs, segment(r, ad))
+				if err != nil || !bytes.Equal(plain, buf[:size]) {
+					t.Fatal("expected result did not match computed", v.name, size, i, err)
+				}
+			}
+		}
+	}
+}
+
+// paddedAEAD pads the message before sealing it, so its ciphertext is longer
+// than the message plus Overhead.
+type paddedAEAD struct {
+	godium.AEAD
+	padding godium.Padding
+}
+
+func (a *paddedAEAD) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
+	cipher = a.AEAD.Seal(dst, nonce, a.padding.Pad(append([]byte(nil), plain...)), ad)
+	return
+}
+
+func (a *paddedAEAD) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
+	if plain, err = a.AEAD.Open(dst, nonce, cipher, ad); err == nil {
+		plain, err = a.padding.Unpad(plain)
+	}
+	return
+}
+
+func TestVecPadded(t *testing.T) {
+	skipNonceReuse(t)
+
+	r := rand.New(rand.NewSource(3))
+	a := &paddedAEAD{NewChacha20Poly1305Ietf(testBytes(0, 32)), utils.NewBlockPadding(64)}
+	nonce := testBytes(0x40, a.NPubBytes())
+
+	for _, size := range []int{0, 1, 63, 64, 65, 200} {
+		plain, ad := testPattern(size), testPattern(r.Intn(100))
+		expect := a.Seal(nil, nonce, plain, ad)
+
+		sealed := make([]byte, len(expect))
+		n := SealVec(a, segment(r, sealed), nonce, segment(r, plain), segment(r, ad))
+		if n != len(expect) || !bytes.Equal(expect, sealed) {
+			t.Fatal("expected result did not match computed", size, n)
+		}
+
+		opened := make([]byte, size)
+		n, err := OpenVec(a, segment(r, opened), nonce, segment(r, sealed), segment(r, ad))
+		if err != nil || n != size || !bytes.Equal(plain, opened) {
+			t.Fatal("expected result did not match computed", size, n, err)
+		}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("short output segments were accepted", size)
+				}
+			}()
+			SealVec(a, segment(r, make([]byte, len(expect)-1)), nonce, segment(r, plain), segment(r, ad))
+		}()
+	}
+}
+
+func TestVecForged(t *testing.T) {
+	noncecheck.Reset()
+
+	r := rand.New(rand.NewSource(2))
+	key := testBytes(0, 32)
+
+	for _, v := range aeads {
+		a := v.new(key)
+		nonce := testBytes(0x40, a.NPubBytes())
+		plain, ad := testPattern(100), []byte("additional data")
+		sealed := a.Seal(nil, nonce, plain, ad)
+
+		for _, i := range []int{0, 50, len(sealed) - 1} {
+			forged := append([]byte(nil), sealed...)
+			forged[i] ^= 1
+
+			opened := make([]byte, len(plain))
+			n, err := OpenVec(a, segment(r, opened), nonce, segment(r, forged), [][]byte{ad})
+			if err != godium.ErrForgedOrCorrupted || n != 0 {
+				t.Error("forged message was accepted", v.name, i, err)
+			}
+			if !bytes.Equal(opened, make([]byte, len(plain))) {
+				t.Error("plaintext of a forged message was written", v.name, i)
+			}
+		}
+
+		short := segment(r, sealed[:a.Overhead()-1])
+		if _, err := OpenVec(a, nil, nonce, short, [][]byte{ad}); err != godium.ErrCipherTooShort {
+			t.Error("short ciphertext was accepted", v.name, err)
+		}
+	}
+}
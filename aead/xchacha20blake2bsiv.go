@@ -0,0 +1,151 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"crypto/subtle"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/generichash"
+	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/stream"
+)
+
+const (
+	XChacha20Blake2bSiv_KeyBytes  = 32
+	XChacha20Blake2bSiv_NSecBytes = 0
+	XChacha20Blake2bSiv_NPubBytes = 16
+	XChacha20Blake2bSiv_ABytes    = 32
+)
+
+// labels of the BLAKE2b tuple hashes used by XChacha20Blake2bSiv.
+const (
+	xchacha20blake2bsivLabel    = "godium xchacha20blake2bsiv"
+	xchacha20blake2bsivMacLabel = "godium xchacha20blake2bsiv mac"
+	xchacha20blake2bsivEncLabel = "godium xchacha20blake2bsiv enc"
+)
+
+// xchacha20blake2bsiv implements a deterministic SIV construction. The tag is
+// a keyed BLAKE2b tuple hash of the nonce, the additional data and the
+// message, and its first 24 bytes are the XChaCha20 nonce that encrypts the
+// message. Reusing a nonce only reveals whether the same message was sealed
+// twice with the same additional data, and sealing without a nonce is a
+// deterministic key wrap. The MAC and encryption keys are derived from the
+// key with tuple hashes under their own labels.
+type xchacha20blake2bsiv struct {
+	macKey [32]byte
+	encKey [32]byte
+}
+
+// NewXChacha20Blake2bSiv
+func NewXChacha20Blake2bSiv(key []byte) (impl godium.AEAD) {
+	a := new(xchacha20blake2bsiv)
+	a.init(key)
+	impl = a
+	return
+}
+
+// init derives the MAC and encryption keys. It panics if the key is not 32
+// bytes long.
+func (a *xchacha20blake2bsiv) init(key []byte) {
+	if len(key) != XChacha20Blake2bSiv_KeyBytes {
+		panic("aead: invalid XChaCha20-BLAKE2b-SIV key length")
+	}
+
+	_, _ = generichash.Blake2bTupleSum(a.macKey[:0], 32, key, xchacha20blake2bsivMacLabel)
+	_, _ = generichash.Blake2bTupleSum(a.encKey[:0], 32, key, xchacha20blake2bsivEncLabel)
+}
+
+// Wipe
+func (a *xchacha20blake2bsiv) Wipe() {
+	godium.Wipe(a.macKey[:])
+	godium.Wipe(a.encKey[:])
+}
+
+// tag computes the synthetic IV of the nonce, the additional data and plain.
+func (a *xchacha20blake2bsiv) tag(tag *[XChacha20Blake2bSiv_ABytes]byte, nonce, plain, ad []byte) {
+	_, _ = generichash.Blake2bTupleSum(tag[:0], XChacha20Blake2bSiv_ABytes, a.macKey[:],
+		xchacha20blake2bsivLabel, nonce, ad, plain)
+}
+
+// seal accepts nonces of any length, including none.
+func (a *xchacha20blake2bsiv) seal(cipher, mac, nonce, plain, ad []byte) {
+	var tag [XChacha20Blake2bSiv_ABytes]byte
+
+	a.tag(&tag, nonce, plain, ad)
+	stream.XChacha20XORIc(cipher, plain, tag[:stream.XChacha20_NonceBytes], 0, a.encKey[:])
+	copy(mac, tag[:])
+}
+
+// open decrypts cipher into plain, and erases plain if the tag is invalid.
+func (a *xchacha20blake2bsiv) open(plain, nonce, cipher, mac, ad []byte) (err error) {
+	var tag, expect [XChacha20Blake2bSiv_ABytes]byte
+
+	if len(mac) != XChacha20Blake2bSiv_ABytes {
+		err = godium.ErrForgedOrCorrupted
+		return
+	}
+	copy(tag[:], mac)
+
+	stream.XChacha20XORIc(plain, cipher, tag[:stream.XChacha20_NonceBytes], 0, a.encKey[:])
+	a.tag(&expect, nonce, plain, ad)
+
+	if subtle.ConstantTimeCompare(expect[:], tag[:]) != 1 {
+		godium.Wipe(plain)
+		err = godium.ErrForgedOrCorrupted
+	}
+	return
+}
+
+// SealDetached
+func (a *xchacha20blake2bsiv) SealDetached(dst, dstMac, nonce, plain, ad []byte) (cipher, mac []byte) {
+	cipher = internal.AllocDst(dst, uint64(len(plain)))
+	mac = internal.AllocDst(dstMac, XChacha20Blake2bSiv_ABytes)
+
+	a.seal(cipher, mac, nonce[:XChacha20Blake2bSiv_NPubBytes], plain, ad)
+	return
+}
+
+// Seal
+func (a *xchacha20blake2bsiv) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
+	mlen := uint64(len(plain))
+	cipher = internal.AllocDst(dst, mlen+XChacha20Blake2bSiv_ABytes)
+
+	// call with slices of len == 0, pointing to the right parts of cipher.
+	_, _ = a.SealDetached(cipher[0:0], cipher[mlen:mlen], nonce, plain, ad)
+	return
+}
+
+// OpenDetached decrypts cipher before checking the tag of the plaintext. The
+// output is erased if the tag is invalid.
+func (a *xchacha20blake2bsiv) OpenDetached(dst, nonce, cipher, mac, ad []byte) (plain []byte, err error) {
+	plain = internal.AllocDst(dst, uint64(len(cipher)))
+
+	err = a.open(plain, nonce[:XChacha20Blake2bSiv_NPubBytes], cipher, mac, ad)
+	return
+}
+
+// Open
+func (a *xchacha20blake2bsiv) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
+	if len(cipher) < XChacha20Blake2bSiv_ABytes {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	mlen := uint64(len(cipher) - XChacha20Blake2bSiv_ABytes)
+	plain = internal.AllocDst(dst, mlen)
+
+	_, err = a.OpenDetached(plain[:0], nonce, cipher[:mlen], cipher[mlen:], ad)
+	return
+}
+
+func (a *xchacha20blake2bsiv) Overhead() int  { return XChacha20Blake2bSiv_ABytes }
+func (a *xchacha20blake2bsiv) NonceSize() int { return XChacha20Blake2bSiv_NPubBytes }
+func (a *xchacha20blake2bsiv) KeyBytes() int  { return XChacha20Blake2bSiv_KeyBytes }
+func (a *xchacha20blake2bsiv) NSecBytes() int { return XChacha20Blake2bSiv_NSecBytes }
+func (a *xchacha20blake2bsiv) NPubBytes() int { return XChacha20Blake2bSiv_NPubBytes }
+func (a *xchacha20blake2bsiv) ABytes() int    { return XChacha20Blake2bSiv_ABytes }
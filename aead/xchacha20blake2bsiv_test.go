@@ -0,0 +1,137 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package aead
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+func TestXChacha20Blake2bSiv(t *testing.T) {
+	// computed with python's hashlib and libsodium's crypto_stream_xchacha20,
+	// under the key 00..1f and the nonce 40..4f. The hash covers the
+	// ciphertexts of testPattern(n) with additional data testPattern(n % 50),
+	// for n up to 600.
+	empty := "ad43767273758fdd9c02eae12bf1fe0877654fcaa8f3e16aeff4e71a42f695e2"
+	short := "fa39918bbab2c7b5f1546a3fc942529390e246c7a32b840f783d4b6245ca3cc6" +
+		"9acec527903b898e3344d8e41cefe10e09e32373d8c723afa61a77ea1bf2b011" +
+		"8c33d5bac87995fc762faa89911cc170e6d10380ccf87570a7c37415caeb0bf0" +
+		"13de0080b685c049ca99af696ea6b1fb406dd4f61b22dd9469c5ead30a3b7824" +
+		"f213f5bf"
+	hash := "1a5a6b9ec41da112e1b6f34591bf7ee9320618fa22509539120d874f0891c749"
+
+	a := NewXChacha20Blake2bSiv(testBytes(0, 32))
+	nonce := testBytes(0x40, XChacha20Blake2bSiv_NPubBytes)
+
+	expect, _ := hex.DecodeString(empty)
+	got := a.Seal(nil, nonce, nil, nil)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	expect, _ = hex.DecodeString(short)
+	got = a.Seal(nil, nonce, testPattern(100), []byte("additional data"))
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	h := sha256.New()
+	for n := 0; n < 600; n++ {
+		plain, ad := testPattern(n), testPattern(n%50)
+		cipher := a.Seal(nil, nonce, plain, ad)
+		h.Write(cipher)
+
+		got, err := a.Open(nil, nonce, cipher, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", n, err)
+		}
+	}
+	expect, _ = hex.DecodeString(hash)
+	if got = h.Sum(nil); !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestXChacha20Blake2bSivForged(t *testing.T) {
+	a := NewXChacha20Blake2bSiv(testBytes(0, 32))
+	nonce := testBytes(0x40, XChacha20Blake2bSiv_NPubBytes)
+	plain, ad := testPattern(100), []byte("additional data")
+	cipher := a.Seal(nil, nonce, plain, ad)
+
+	for _, i := range []int{0, 50, len(cipher) - 1} {
+		forged := append([]byte(nil), cipher...)
+		forged[i] ^= 1
+
+		got, err := a.Open(nil, nonce, forged, ad)
+		if err != godium.ErrForgedOrCorrupted {
+			t.Error("forged message was accepted", i, err)
+		}
+		if !bytes.Equal(got, make([]byte, len(plain))) {
+			t.Error("output of a forged message was not erased", i)
+		}
+	}
+
+	other := testBytes(0x41, XChacha20Blake2bSiv_NPubBytes)
+	if _, err := a.Open(nil, other, cipher, ad); err != godium.ErrForgedOrCorrupted {
+		t.Error("message was accepted under a different nonce", err)
+	}
+	if _, err := a.Open(nil, nonce, cipher[:XChacha20Blake2bSiv_ABytes-1], ad); err != godium.ErrCipherTooShort {
+		t.Error("short ciphertext was accepted", err)
+	}
+}
+
+func TestXChacha20Blake2bSivInPlace(t *testing.T) {
+	a := NewXChacha20Blake2bSiv(testBytes(0, 32))
+	nonce := testBytes(0x40, XChacha20Blake2bSiv_NPubBytes)
+	plain := testPattern(100)
+	expect := a.Seal(nil, nonce, plain, nil)
+
+	buf := append([]byte(nil), plain...)
+	got := a.Seal(buf[:0], nonce, buf, nil)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	got, err := a.Open(got[:0], nonce, got, nil)
+	if err != nil || !bytes.Equal(plain, got) {
+		t.Error("expected result did not match computed", plain, got, err)
+	}
+}
+
+func TestXChacha20Blake2bSivKeyWrap(t *testing.T) {
+	// computed like TestXChacha20Blake2bSiv, without a nonce.
+	expect, _ := hex.DecodeString("4b6168af2c8042391584ffea4132d85f9ef5b18b41928995a186ad30bca0f9f1" +
+		"9702ae2d8aa3e8bc3161b65f7052f5551942a29bc37b857dac56e5fbc66ec528")
+	kek, key := testBytes(0, 32), testBytes(0x80, 32)
+
+	wrapped := WrapKeyXChacha20Blake2bSiv(nil, kek, key, nil)
+	if !bytes.Equal(expect, wrapped) {
+		t.Error("expected result did not match computed", expect, wrapped)
+	}
+
+	got, err := UnwrapKeyXChacha20Blake2bSiv(nil, kek, wrapped, nil)
+	if err != nil || !bytes.Equal(key, got) {
+		t.Error("expected result did not match computed", key, got, err)
+	}
+	if _, err = UnwrapKeyXChacha20Blake2bSiv(nil, kek, wrapped, []byte("key id")); err != godium.ErrForgedOrCorrupted {
+		t.Error("key was unwrapped with different additional data", err)
+	}
+	if _, err = UnwrapKeyXChacha20Blake2bSiv(nil, testBytes(1, 32), wrapped, nil); err != godium.ErrForgedOrCorrupted {
+		t.Error("key was unwrapped with a different key", err)
+	}
+}
+
+func BenchmarkXChacha20Blake2bSiv_64(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewXChacha20Blake2bSiv, 64)
+}
+func BenchmarkXChacha20Blake2bSiv_8K(b *testing.B) {
+	benchmarkChacha20Poly1305(b, NewXChacha20Blake2bSiv, 8192)
+}
@@ -10,6 +10,7 @@ import (
 	"go.artemisc.eu/godium"
 	"go.artemisc.eu/godium/core"
 	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/noncecheck"
 )
 
 const (
@@ -17,21 +18,21 @@ const (
 	XChacha20Poly1305Ietf_NSecBytes = 0
 	XChacha20Poly1305Ietf_NPubBytes = 24
 	XChacha20Poly1305Ietf_ABytes    = 16
-)
 
-// fixme implement the actual sigma constant for HChacha20
-var sigmaConstant []byte = nil
+	XChacha20Poly1305Ietf_MessageBytesMax = Chacha20Poly1305Ietf_MessageBytesMax
+)
 
+// xchacha20poly1305ietf implements the XChaCha20-Poly1305 construction, which
+// runs chacha20poly1305ietf under a subkey derived from the first 16 bytes of
+// the nonce.
 type xchacha20poly1305ietf struct {
 	godium.Key
-	*chacha20poly1305ietf
 }
 
 // NewXChacha20Poly1305Ietf
 func NewXChacha20Poly1305Ietf(key []byte) (impl godium.AEAD) {
 	impl = &xchacha20poly1305ietf{
-		Key:                  internal.Copy(key, XChacha20Poly1305Ietf_KeyBytes),
-		chacha20poly1305ietf: new(chacha20poly1305ietf),
+		Key: internal.Copy(key, XChacha20Poly1305Ietf_KeyBytes),
 	}
 	return
 }
@@ -39,65 +40,96 @@ func NewXChacha20Poly1305Ietf(key []byte) (impl godium.AEAD) {
 // Wipe
 func (a *xchacha20poly1305ietf) Wipe() {
 	godium.Wipe(a.Key)
-	a.chacha20poly1305ietf.Wipe()
+}
+
+// recordNonce records the nonce of a seal when nonce reuse is checked.
+func (a *xchacha20poly1305ietf) recordNonce(nonce []byte) {
+	noncecheck.Record("aead xchacha20poly1305ietf", a.Key, nonce[:XChacha20Poly1305Ietf_NPubBytes])
 }
 
 // initAead performs the seal/open common setup of generating a new subkey and
 // nonce to be passed to the chacha20poly1305ietf implementation.
-func (a *xchacha20poly1305ietf) initAead(nonce []byte) (nonce2 []byte) {
-	const (
-		// aliases
-		npubBytes = Chacha20Poly1305Ietf_NPubBytes
-		keyBytes  = XChacha20Poly1305Ietf_KeyBytes
-	)
-
-	key2 := make([]byte, 0, keyBytes)
-	nonce2 = make([]byte, npubBytes)
+func (a *xchacha20poly1305ietf) initAead(subKey *[Chacha20Poly1305Ietf_KeyBytes]byte,
+	nonce2 *[Chacha20Poly1305Ietf_NPubBytes]byte, nonce []byte) {
 
-	key2 = core.HChacha20(key2, nonce2, a.Key, sigmaConstant)
-	copy(nonce2[4:], nonce[core.HChacha20_InputBytes:core.HChacha20_InputBytes+8])
+	nonce = nonce[:XChacha20Poly1305Ietf_NPubBytes]
 
-	a.chacha20poly1305ietf.Key = key2
-	return
+	core.HChacha20(subKey[:0], nonce, a.Key, nil)
+	copy(nonce2[4:], nonce[core.HChacha20_InputBytes:])
 }
 
-// Seal
+// SealDetached
 func (a *xchacha20poly1305ietf) SealDetached(dst, dstMac, nonce, plain, ad []byte) (cipher, mac []byte) {
-	nonce2 := a.initAead(nonce)
+	var subKey [Chacha20Poly1305Ietf_KeyBytes]byte
+	var nonce2 [Chacha20Poly1305Ietf_NPubBytes]byte
+
+	mlen := uint64(len(plain))
+	if mlen > XChacha20Poly1305Ietf_MessageBytesMax {
+		panic("aead: message too long for XChaCha20-Poly1305")
+	}
 
-	cipher, mac = a.chacha20poly1305ietf.SealDetached(dst, dstMac, nonce2, plain, ad)
+	cipher = internal.AllocDst(dst, mlen)
+	mac = internal.AllocDst(dstMac, XChacha20Poly1305Ietf_ABytes)
 
-	godium.Wipe(a.chacha20poly1305ietf.Key)
+	a.recordNonce(nonce)
+	a.initAead(&subKey, &nonce2, nonce)
+	sealIetfGeneric(cipher, mac, subKey[:], nonce2[:], plain, ad)
+
+	godium.Wipe(subKey[:])
 	return
 }
 
 // Seal
 func (a *xchacha20poly1305ietf) Seal(dst, nonce, plain, ad []byte) (cipher []byte) {
-	nonce2 := a.initAead(nonce)
+	var subKey [Chacha20Poly1305Ietf_KeyBytes]byte
+	var nonce2 [Chacha20Poly1305Ietf_NPubBytes]byte
+
+	mlen := uint64(len(plain))
+	if mlen > XChacha20Poly1305Ietf_MessageBytesMax {
+		panic("aead: message too long for XChaCha20-Poly1305")
+	}
+
+	cipher = internal.AllocDst(dst, mlen+XChacha20Poly1305Ietf_ABytes)
 
-	cipher = a.chacha20poly1305ietf.Seal(dst, nonce2, plain, ad)
+	a.recordNonce(nonce)
+	a.initAead(&subKey, &nonce2, nonce)
+	sealIetf(cipher, subKey[:], nonce2[:], plain, ad)
 
-	godium.Wipe(a.chacha20poly1305ietf.Key)
+	godium.Wipe(subKey[:])
 	return
 }
 
 // OpenDetached
 func (a *xchacha20poly1305ietf) OpenDetached(dst, nonce, cipher, mac, ad []byte) (plain []byte, err error) {
-	nonce2 := a.initAead(nonce)
+	var subKey [Chacha20Poly1305Ietf_KeyBytes]byte
+	var nonce2 [Chacha20Poly1305Ietf_NPubBytes]byte
 
-	plain, err = a.chacha20poly1305ietf.OpenDetached(dst, nonce2, cipher, mac, ad)
+	plain = internal.AllocDst(dst, uint64(len(cipher)))
 
-	godium.Wipe(a.chacha20poly1305ietf.Key)
+	a.initAead(&subKey, &nonce2, nonce)
+	err = openIetfGeneric(plain, subKey[:], nonce2[:], cipher, mac, ad)
+
+	godium.Wipe(subKey[:])
 	return
 }
 
 // Open
 func (a *xchacha20poly1305ietf) Open(dst, nonce, cipher, ad []byte) (plain []byte, err error) {
-	nonce2 := a.initAead(nonce)
+	var subKey [Chacha20Poly1305Ietf_KeyBytes]byte
+	var nonce2 [Chacha20Poly1305Ietf_NPubBytes]byte
+
+	if len(cipher) < XChacha20Poly1305Ietf_ABytes {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	mlen := uint64(len(cipher) - XChacha20Poly1305Ietf_ABytes)
+	plain = internal.AllocDst(dst, mlen)
 
-	plain, err = a.chacha20poly1305ietf.Open(dst, nonce2, cipher, ad)
+	a.initAead(&subKey, &nonce2, nonce)
+	err = openIetf(plain, subKey[:], nonce2[:], cipher, ad)
 
-	godium.Wipe(a.chacha20poly1305ietf.Key)
+	godium.Wipe(subKey[:])
 	return
 }
 
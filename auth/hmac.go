@@ -10,12 +10,14 @@
 package auth // import "go.artemisc.eu/godium/auth"
 
 import (
-	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/sha512"
+	"crypto/subtle"
+	"encoding"
 	"hash"
 
 	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
 )
 
 const (
@@ -33,11 +35,18 @@ const (
 	KeyBytes  = HmacSha512256_KeyBytes
 )
 
-// hmacImpl implements the godium.Auth API on top of golang's own hash and hmac
-// implementations.
+// hmacImpl implements the godium.Auth API as HMAC on top of golang's own hash
+// implementations. Unlike crypto/hmac, the state of the inner hash can be
+// serialized. The key is not kept, only the pads derived from it.
 type hmacImpl struct {
-	hash.Hash
-	key []byte
+	inner     hash.Hash
+	outer     hash.Hash
+	ipad      []byte
+	opad      []byte
+	sum       [sha512.Size]byte
+	size      int
+	keyBytes  int
+	primitive string
 }
 
 // New
@@ -46,43 +55,235 @@ func New(key []byte) (auth godium.Auth) {
 	return
 }
 
-// NewHmacSha256
+// Sum appends the authentication tag of data under key to dst, like
+// crypto_auth.
+func Sum(dst, data, key []byte) (tag []byte) {
+	tag = SumHmacSha512256(dst, data, key)
+	return
+}
+
+// Verify checks the authentication tag of data under key in constant time,
+// like crypto_auth_verify.
+func Verify(tag, data, key []byte) (valid bool) {
+	valid = VerifyHmacSha512256(tag, data, key)
+	return
+}
+
+// KeyGen generates a random key of KeyBytes bytes, like crypto_auth_keygen.
+func KeyGen(random godium.Random) (key godium.Key, err error) {
+	key, err = KeyGenHmacSha512256(random)
+	return
+}
+
+// NewHmacSha256 creates an HMAC-SHA-256 state, like
+// crypto_auth_hmacsha256_init. The key can have any length, a key longer than
+// the block size of SHA-256 is hashed first. The key is not retained.
 func NewHmacSha256(key []byte) (auth godium.Auth) {
-	auth = &hmacImpl{
-		Hash: hmac.New(sha256.New, key),
-		key:  key,
-	}
+	auth = newHmac("hmacsha256", sha256.New, HmacSha256_Bytes, HmacSha256_KeyBytes, key)
 	return
 }
 
-// NewHmacSha512
+// NewHmacSha512 creates an HMAC-SHA-512 state, like
+// crypto_auth_hmacsha512_init. The key can have any length, and is not
+// retained.
 func NewHmacSha512(key []byte) (auth godium.Auth) {
-	auth = &hmacImpl{
-		Hash: hmac.New(sha512.New, key),
-		key:  key,
-	}
+	auth = newHmac("hmacsha512", sha512.New, HmacSha512_Bytes, HmacSha512_KeyBytes, key)
 	return
 }
 
-// NewHmacSha512256
+// NewHmacSha512256 creates an HMAC-SHA-512 state with the tag truncated to 32
+// bytes, like crypto_auth_hmacsha512256_init. This is not HMAC-SHA-512/256.
+// The key can have any length, and is not retained.
 func NewHmacSha512256(key []byte) (auth godium.Auth) {
-	auth = &hmacImpl{
-		Hash: hmac.New(sha512.New512_256, key),
-		key:  key,
+	auth = newHmac("hmacsha512256", sha512.New, HmacSha512256_Bytes, HmacSha512256_KeyBytes, key)
+	return
+}
+
+// SumHmacSha256 appends the HMAC-SHA-256 tag of data under key to dst, like
+// crypto_auth_hmacsha256.
+func SumHmacSha256(dst, data, key []byte) (tag []byte) {
+	tag = sumHmac(NewHmacSha256(key), dst, data)
+	return
+}
+
+// SumHmacSha512 appends the HMAC-SHA-512 tag of data under key to dst, like
+// crypto_auth_hmacsha512.
+func SumHmacSha512(dst, data, key []byte) (tag []byte) {
+	tag = sumHmac(NewHmacSha512(key), dst, data)
+	return
+}
+
+// SumHmacSha512256 appends the truncated HMAC-SHA-512 tag of data under key
+// to dst, like crypto_auth_hmacsha512256.
+func SumHmacSha512256(dst, data, key []byte) (tag []byte) {
+	tag = sumHmac(NewHmacSha512256(key), dst, data)
+	return
+}
+
+// VerifyHmacSha256 checks the HMAC-SHA-256 tag of data under key in constant
+// time, like crypto_auth_hmacsha256_verify.
+func VerifyHmacSha256(tag, data, key []byte) (valid bool) {
+	valid = verifyHmac(NewHmacSha256(key), tag, data)
+	return
+}
+
+// VerifyHmacSha512 checks the HMAC-SHA-512 tag of data under key in constant
+// time, like crypto_auth_hmacsha512_verify.
+func VerifyHmacSha512(tag, data, key []byte) (valid bool) {
+	valid = verifyHmac(NewHmacSha512(key), tag, data)
+	return
+}
+
+// VerifyHmacSha512256 checks the truncated HMAC-SHA-512 tag of data under key
+// in constant time, like crypto_auth_hmacsha512256_verify.
+func VerifyHmacSha512256(tag, data, key []byte) (valid bool) {
+	valid = verifyHmac(NewHmacSha512256(key), tag, data)
+	return
+}
+
+// KeyGenHmacSha256
+func KeyGenHmacSha256(random godium.Random) (key godium.Key, err error) {
+	key, err = random.KeyGen(HmacSha256_KeyBytes)
+	return
+}
+
+// KeyGenHmacSha512
+func KeyGenHmacSha512(random godium.Random) (key godium.Key, err error) {
+	key, err = random.KeyGen(HmacSha512_KeyBytes)
+	return
+}
+
+// KeyGenHmacSha512256
+func KeyGenHmacSha512256(random godium.Random) (key godium.Key, err error) {
+	key, err = random.KeyGen(HmacSha512256_KeyBytes)
+	return
+}
+
+// sumHmac appends the tag of data to dst, and wipes the state.
+func sumHmac(a godium.Auth, dst, data []byte) (tag []byte) {
+	a.Write(data)
+	tag = a.Sum(dst)
+	a.Wipe()
+	return
+}
+
+// verifyHmac checks the tag of data, and wipes the state.
+func verifyHmac(a godium.Auth, tag, data []byte) (valid bool) {
+	a.Write(data)
+	valid = a.Verify(tag)
+	a.Wipe()
+	return
+}
+
+// newHmac derives the inner and outer pads from the key, as specified by
+// RFC 2104. The tag is truncated to size bytes.
+func newHmac(primitive string, h func() hash.Hash, size, keyBytes int, key []byte) (a *hmacImpl) {
+	a = &hmacImpl{
+		inner:     h(),
+		outer:     h(),
+		size:      size,
+		keyBytes:  keyBytes,
+		primitive: primitive,
 	}
+
+	blockSize := a.inner.BlockSize()
+	a.ipad = make([]byte, blockSize)
+	a.opad = make([]byte, blockSize)
+
+	if len(key) > blockSize {
+		a.outer.Write(key)
+		a.outer.Sum(a.ipad[:0])
+		a.outer.Reset()
+	} else {
+		copy(a.ipad, key)
+	}
+	copy(a.opad, a.ipad)
+
+	for i := range a.ipad {
+		a.ipad[i] ^= 0x36
+		a.opad[i] ^= 0x5c
+	}
+
+	a.inner.Write(a.ipad)
 	return
 }
 
-// Wipe
+// Write
+func (h *hmacImpl) Write(p []byte) (n int, err error) {
+	n, err = h.inner.Write(p)
+	return
+}
+
+// Sum appends the authentication tag to dst, without changing the state.
+func (h *hmacImpl) Sum(dst []byte) (tag []byte) {
+	tag = append(dst, h.tag()...)
+	godium.Wipe(h.sum[:])
+	return
+}
+
+// tag computes the authentication tag into h.sum, so that neither Sum nor
+// Verify allocate.
+func (h *hmacImpl) tag() (tag []byte) {
+	sum := h.inner.Sum(h.sum[:0])
+	h.outer.Reset()
+	h.outer.Write(h.opad)
+	h.outer.Write(sum)
+	tag = h.outer.Sum(h.sum[:0])[:h.size]
+	return
+}
+
+// Reset restores the state after creation, keyed with the same key.
+func (h *hmacImpl) Reset() {
+	h.inner.Reset()
+	h.inner.Write(h.ipad)
+}
+
+// Wipe erases the pads derived from the key and the state.
 func (h *hmacImpl) Wipe() {
-	godium.Wipe(h.key)
+	godium.Wipe(h.ipad)
+	godium.Wipe(h.opad)
+	godium.Wipe(h.sum[:])
+	h.inner.Reset()
+	h.outer.Reset()
 }
 
-// Verify
+// Verify checks in constant time whether tag matches the authentication tag
+// of the data written so far, without changing the state.
 func (h *hmacImpl) Verify(tag []byte) (valid bool) {
-	valid = hmac.Equal(h.Sum(nil), tag)
+	valid = subtle.ConstantTimeCompare(h.tag(), tag) == 1
+	godium.Wipe(h.sum[:])
+	return
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The state contains the
+// inner hash of the data written so far, but not the key. It must be restored
+// into an Auth created with the same key, and should be kept as secret as the
+// data.
+func (h *hmacImpl) MarshalBinary() (state []byte, err error) {
+	payload, err := h.inner.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return
+	}
+
+	state = internal.MarshalState(h.primitive, h.Size(), payload)
+	return
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It returns
+// godium.ErrInvalidState if the state was created by a different primitive.
+func (h *hmacImpl) UnmarshalBinary(state []byte) (err error) {
+	payload, err := internal.UnmarshalState(state, h.primitive, h.Size())
+	if err != nil {
+		return
+	}
+
+	if h.inner.(encoding.BinaryUnmarshaler).UnmarshalBinary(payload) != nil {
+		err = godium.ErrInvalidState
+	}
 	return
 }
 
-func (h *hmacImpl) Bytes() int    { return h.Size() }
-func (h *hmacImpl) KeyBytes() int { return len(h.key) }
+func (h *hmacImpl) Size() int      { return h.size }
+func (h *hmacImpl) BlockSize() int { return h.inner.BlockSize() }
+func (h *hmacImpl) Bytes() int     { return h.size }
+func (h *hmacImpl) KeyBytes() int  { return h.keyBytes }
@@ -0,0 +1,171 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package auth
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+func TestHmacSha256(t *testing.T) {
+	// RFC 4231, test case 2
+	expect := []byte{
+		0x5b, 0xdc, 0xc1, 0x46, 0xbf, 0x60, 0x75, 0x4e,
+		0x6a, 0x04, 0x24, 0x26, 0x08, 0x95, 0x75, 0xc7,
+		0x5a, 0x00, 0x3f, 0x08, 0x9d, 0x27, 0x39, 0x83,
+		0x9d, 0xec, 0x58, 0xb9, 0x64, 0xec, 0x38, 0x43,
+	}
+
+	h := NewHmacSha256([]byte("Jefe"))
+	h.Write([]byte("what do ya want for nothing?"))
+	got := h.Sum(nil)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	// RFC 4231, test case 6
+	expect = []byte{
+		0x60, 0xe4, 0x31, 0x59, 0x1e, 0xe0, 0xb6, 0x7f,
+		0x0d, 0x8a, 0x26, 0xaa, 0xcb, 0xf5, 0xb7, 0x7f,
+		0x8e, 0x0b, 0xc6, 0x21, 0x37, 0x28, 0xc5, 0x14,
+		0x05, 0x46, 0x04, 0x0f, 0x0e, 0xe3, 0x7f, 0x54,
+	}
+
+	h = NewHmacSha256(bytes.Repeat([]byte{0xaa}, 131))
+	h.Write([]byte("Test Using Larger Than Block-Size Key - Hash Key First"))
+	got = h.Sum(nil)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestHmacState(t *testing.T) {
+	key := []byte("Jefe")
+	msg := []byte("what do ya want for nothing?")
+
+	h := NewHmacSha512(key)
+	h.Write(msg[:10])
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resumed := NewHmacSha512(key)
+	if err = resumed.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+	resumed.Write(msg[10:])
+	h.Write(msg[10:])
+
+	expect, got := h.Sum(nil), resumed.Sum(nil)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	for _, other := range []godium.Auth{NewHmacSha256(key), NewHmacSha512256(key)} {
+		err = other.(encoding.BinaryUnmarshaler).UnmarshalBinary(state)
+		if err != godium.ErrInvalidState {
+			t.Error("state was restored into a different primitive")
+		}
+	}
+}
+
+// hmacTests holds tags computed by libsodium's crypto_auth_*_init, update and
+// final, for keys of the bytes 7i+3 of the given length. The last entry is
+// the crypto_auth_hmacsha512256 vector from libsodium's test/default/auth.c.
+var hmacTests = []struct {
+	newAuth func(key []byte) godium.Auth
+	sum     func(dst, data, key []byte) []byte
+	verify  func(tag, data, key []byte) bool
+	keyLen  int
+	msg     string
+	expect  string
+}{
+	{NewHmacSha256, SumHmacSha256, VerifyHmacSha256, 0, "Sample message for keylen<=blocklen",
+		"b2b6459a3acd345ad74bed341551f20db1e88b3bca465eedd8cef8bf33cee1f1"},
+	{NewHmacSha256, SumHmacSha256, VerifyHmacSha256, 32, "Sample message for keylen<=blocklen",
+		"86bd36ce9a8a0b4acc0e9bb9fc7f18f96c049545c1a7b4dab28585ef6528839c"},
+	{NewHmacSha256, SumHmacSha256, VerifyHmacSha256, 129, "Sample message for keylen<=blocklen",
+		"e31f5c1b3923f4156e064cf3062fbb4d52325d8ebf26f31190ebdfb123c3cf1c"},
+	{NewHmacSha512, SumHmacSha512, VerifyHmacSha512, 0, "Sample message for keylen<=blocklen",
+		"3b74efedf3eb0f7a7f49a994bd17d1a674936d32b90a3f0aae16e288c5dedacf" +
+			"af8ce4aba684ff3e1445b9fe38734d47988e5d42e7fdcb274ac28db01e76cfca"},
+	{NewHmacSha512, SumHmacSha512, VerifyHmacSha512, 32, "Sample message for keylen<=blocklen",
+		"eddce562f90923269dddd6250bc7046ba2e826cb668d5fe24ca020de5e354a7c" +
+			"3d500a175fa13484d4906e45c989c4b461284c3dac121eb4aa5f080834231080"},
+	{NewHmacSha512, SumHmacSha512, VerifyHmacSha512, 129, "Sample message for keylen<=blocklen",
+		"89bb8a394d9e53e1f6477d5fbf7467e9eb98834697f59fec1ae5f7bcec5b356d" +
+			"8617955f56f134bc77dd37fdb28b3bb055898345b83a668d675ee82517ac69c5"},
+	{NewHmacSha512256, SumHmacSha512256, VerifyHmacSha512256, 0, "Sample message for keylen<=blocklen",
+		"3b74efedf3eb0f7a7f49a994bd17d1a674936d32b90a3f0aae16e288c5dedacf"},
+	{NewHmacSha512256, SumHmacSha512256, VerifyHmacSha512256, 32, "Sample message for keylen<=blocklen",
+		"eddce562f90923269dddd6250bc7046ba2e826cb668d5fe24ca020de5e354a7c"},
+	{NewHmacSha512256, SumHmacSha512256, VerifyHmacSha512256, 129, "Sample message for keylen<=blocklen",
+		"89bb8a394d9e53e1f6477d5fbf7467e9eb98834697f59fec1ae5f7bcec5b356d"},
+	{NewHmacSha512256, SumHmacSha512256, VerifyHmacSha512256, -1, "what do ya want for nothing?",
+		"164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea250554"},
+}
+
+func TestHmacLibsodium(t *testing.T) {
+	for _, test := range hmacTests {
+		key := []byte("Jefe")
+		if test.keyLen >= 0 {
+			key = make([]byte, test.keyLen)
+			for i := range key {
+				key[i] = byte(7*i + 3)
+			}
+		}
+		msg := []byte(test.msg)
+		expect, _ := hex.DecodeString(test.expect)
+
+		got := test.sum(nil, msg, key)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", test.keyLen, expect, got)
+		}
+		if !test.verify(expect, msg, key) {
+			t.Error("valid tag was rejected", test.keyLen)
+		}
+
+		expect[len(expect)-1] ^= 1
+		if test.verify(expect, msg, key) || test.verify(expect[:16], msg, key) {
+			t.Error("invalid tag was accepted", test.keyLen)
+		}
+		expect[len(expect)-1] ^= 1
+
+		// the state must not depend on the caller's key after creation
+		h := test.newAuth(key)
+		godium.Wipe(key)
+		h.Write([]byte("discarded by reset"))
+		h.Reset()
+		h.Write(msg)
+		if got = h.Sum(nil); !bytes.Equal(expect, got) || !h.Verify(expect) {
+			t.Error("expected result did not match computed after reset", test.keyLen, expect, got)
+		}
+		if h.Size() != len(expect) || h.KeyBytes() != 32 {
+			t.Error("sizes did not match the constants", h.Size(), h.KeyBytes())
+		}
+	}
+}
+
+func TestHmacVerifyAllocs(t *testing.T) {
+	h := New([]byte("key"))
+	h.Write([]byte("message"))
+	tag := h.Sum(nil)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		if !h.Verify(tag) {
+			t.Error("valid tag was rejected")
+		}
+	})
+	if allocs != 0 {
+		t.Error("Verify allocated", allocs)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package benchmarks
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/minio/blake2b-simd"
+	"go.artemisc.eu/godium/generichash"
+)
+
+var benchSizes = []int{64, 1024, 8192, 65536}
+
+func TestBlake2bMinio(t *testing.T) {
+	data := make([]byte, 1<<16)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	for n := 0; n <= len(data); n += 4093 {
+		got, expect := generichash.Blake2bSum256(data[:n]), blake2b.Sum256(data[:n])
+		if !bytes.Equal(got[:], expect[:]) {
+			t.Error("expected result did not match computed", n, expect, got)
+		}
+
+		got512, expect512 := generichash.Blake2bSum512(data[:n]), blake2b.Sum512(data[:n])
+		if !bytes.Equal(got512[:], expect512[:]) {
+			t.Error("expected result did not match computed", n, expect512, got512)
+		}
+	}
+}
+
+func BenchmarkBlake2bSum512(b *testing.B) {
+	for _, size := range benchSizes {
+		data := make([]byte, size)
+
+		b.Run("godium/"+strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				generichash.Blake2bSum512(data)
+			}
+		})
+		b.Run("minio/"+strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				blake2b.Sum512(data)
+			}
+		})
+	}
+}
+
+func BenchmarkBlake2bKeyed(b *testing.B) {
+	key := make([]byte, generichash.Blake2b_KeyBytes)
+	sum := make([]byte, 0, generichash.Blake2b_Bytes)
+
+	for _, size := range benchSizes {
+		data := make([]byte, size)
+
+		b.Run("godium/"+strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				generichash.Blake2bSum(sum, data, generichash.Blake2b_Bytes, key)
+			}
+		})
+		b.Run("minio/"+strconv.Itoa(size), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				h := blake2b.NewMAC(generichash.Blake2b_Bytes, key)
+				h.Write(data)
+				h.Sum(sum)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+/*
+Package benchmarks compares godium against the third party packages it used to
+depend on. It is a separate module, so the compared packages do not end up in
+the dependencies of godium itself.
+
+The BLAKE2b one-shot functions replaced github.com/minio/blake2b-simd, the
+comparison is run with:
+
+	cd benchmarks && go test -run XXX -bench Blake2b
+*/
+package benchmarks // import "go.artemisc.eu/godium/benchmarks"
@@ -0,0 +1,10 @@
+module go.artemisc.eu/godium/benchmarks
+
+go 1.13
+
+require (
+	github.com/minio/blake2b-simd v0.0.0-20160723061019-3f5f724cb5b1
+	go.artemisc.eu/godium v0.0.0
+)
+
+replace go.artemisc.eu/godium => ../
@@ -0,0 +1,135 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package box
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sync"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/noncecheck"
+)
+
+// testBytes returns size bytes counting up from start.
+func testBytes(start byte, size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = start + byte(i)
+	}
+	return
+}
+
+// testPattern returns size bytes of input, byte i set to i % 251.
+func testPattern(size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = byte(i % 251)
+	}
+	return
+}
+
+// skipNonceReuse skips a test that seals under fixed keys and nonces, which
+// the godium_noncecheck build tag reports as nonce reuse. Tests that seal a
+// fixed key and nonce only once call noncecheck.Reset instead.
+func skipNonceReuse(t *testing.T) {
+	if noncecheck.Enabled {
+		t.Skip("seals under fixed keys and nonces")
+	}
+}
+
+// boxVectors were computed with libsodium's crypto_box_easy and
+// crypto_box_curve25519xchacha20poly1305_easy, sealing testPattern(100) from
+// the secret key 60..7f to the secret key a0..bf, with the nonce 40..57.
+var boxVectors = []struct {
+	name string
+	new  func(private, public []byte) godium.Box
+	box  string
+}{
+	{"curve25519xsalsa20poly1305", NewCurve25519XSalsa20Poly1305,
+		"3a1aee02c291be3c6a5d2443d9ed2293c4b09b7801ece291790da860fb987c33" +
+			"fc5fe777296ab402750d6485a336f73951a4f152112a58c690a717936b9c8815" +
+			"00a737414f33ad6f438c29fcf6f5fe3859792043a69db78a3a2402b93aa591d6" +
+			"e39ab206ecaa12e3570189f190f7468d6b43cbc4"},
+	{"curve25519xchacha20poly1305", NewCurve25519XChacha20Poly1305,
+		"a7e7e7c5f4a7dc2873011ff496ef364cbaa1d288e4a5e884c84dae04bcf62ead" +
+			"f5af0560b855a161d1a4cf0d5b72ffd76106775ad3e7a1a672ae9351f44bb232" +
+			"c9276a90dd0f15c43d5ea3b6b5cde1498c6fe97f8c4e6f0103270e32933927d3" +
+			"f9c21897d87ab1064e6031f82a86011e1f8ed4c6"},
+}
+
+// testBoxes returns the boxes of the sender and the recipient.
+func testBoxes(new func(private, public []byte) godium.Box) (alice, bob godium.Box) {
+	alicePk, _ := hex.DecodeString("675dd574ed7789310b3d2e7681f3790b466c773b1521fecf36577958371ea52f")
+	bobPk, _ := hex.DecodeString("605a725d2a4adfeeb1a29e17edd621c1b7593ee8cdbc44ac6c4ab6e2f805d23c")
+
+	alice = new(testBytes(0x60, 32), alicePk)
+	bob = new(testBytes(0xa0, 32), bobPk)
+	return
+}
+
+func TestBox(t *testing.T) {
+	for _, v := range boxVectors {
+		alice, bob := testBoxes(v.new)
+		nonce := testBytes(0x40, alice.NonceBytes())
+		plain := testPattern(100)
+
+		expect, _ := hex.DecodeString(v.box)
+		got, err := alice.Seal(nil, nonce, plain, bob.PublicKey())
+		if err != nil || !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.name, expect, got, err)
+		}
+
+		got, err = bob.Open(nil, nonce, expect, alice.PublicKey())
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, plain, got, err)
+		}
+
+		expect[len(expect)-1] ^= 1
+		if _, err = bob.Open(nil, nonce, expect, alice.PublicKey()); err != godium.ErrForgedOrCorrupted {
+			t.Error("forged box was accepted", v.name, err)
+		}
+	}
+}
+
+func TestBoxConcurrent(t *testing.T) {
+	skipNonceReuse(t)
+
+	const goroutines, messages = 8, 20
+
+	for _, v := range boxVectors {
+		alice, bob := testBoxes(v.new)
+		sb, _ := alice.BeforeNM(bob.PublicKey())
+
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+
+				nonce := testBytes(byte(g), alice.NonceBytes())
+				for i := 0; i < messages; i++ {
+					nonce[0] = byte(i)
+					plain := testPattern(g*61 + i)
+
+					box, err := alice.Seal(nil, nonce, plain, bob.PublicKey())
+					if err != nil || !bytes.Equal(box, sb.Seal(nil, nonce, plain)) {
+						t.Error("expected result did not match computed", v.name, g, i, err)
+						return
+					}
+					got, err := bob.Open(nil, nonce, box, alice.PublicKey())
+					if err != nil || !bytes.Equal(plain, got) {
+						t.Error("expected result did not match computed", v.name, g, i, err)
+						return
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+	}
+}
@@ -25,21 +25,57 @@ const (
 
 // Curve25519XChacha20Poly1305
 type Curve25519XChacha20Poly1305 struct {
-	godium.PrivateKey
-	godium.PublicKey
+	private godium.PrivateKey
+	public  godium.PublicKey
 }
 
 //
 func NewCurve25519XChacha20Poly1305(private, public []byte) (box godium.Box) {
 	box = &Curve25519XChacha20Poly1305{
-		PrivateKey: internal.Copy(private, Curve25519XChacha20Poly1305_SecretKeyBytes),
-		PublicKey:  internal.Copy(public, Curve25519XChacha20Poly1305_PublicKeyBytes),
+		private: internal.Copy(private, Curve25519XChacha20Poly1305_SecretKeyBytes),
+		public:  internal.Copy(public, Curve25519XChacha20Poly1305_PublicKeyBytes),
+	}
+	return
+}
+
+// KeyPairCurve25519XChacha20Poly1305 generates a new key pair from random
+// bytes, like crypto_box_keypair.
+func KeyPairCurve25519XChacha20Poly1305(random godium.Random) (b *Curve25519XChacha20Poly1305, err error) {
+	private, err := random.KeyGen(Curve25519XChacha20Poly1305_SecretKeyBytes)
+	if err != nil {
+		return
+	}
+
+	b = &Curve25519XChacha20Poly1305{
+		private: private,
+		public:  scalarmult.Curve25519Base(make([]byte, 0, Curve25519XChacha20Poly1305_PublicKeyBytes), private),
+	}
+	return
+}
+
+// KeyPairSeedCurve25519XChacha20Poly1305 deterministically derives a key
+// pair from the seed, like crypto_box_seed_keypair.
+func KeyPairSeedCurve25519XChacha20Poly1305(seed []byte) (b *Curve25519XChacha20Poly1305) {
+	private, public := keyPairSeed(seed)
+	b = &Curve25519XChacha20Poly1305{
+		private: private,
+		public:  public,
 	}
 	return
 }
 
 func (b *Curve25519XChacha20Poly1305) Wipe() {
-	godium.Wipe(b.PrivateKey)
+	godium.Wipe(b.private)
+}
+
+// PublicKey
+func (b *Curve25519XChacha20Poly1305) PublicKey() godium.PublicKey {
+	return internal.Copy(b.public, Curve25519XChacha20Poly1305_PublicKeyBytes)
+}
+
+// SecretKey
+func (b *Curve25519XChacha20Poly1305) SecretKey() godium.PrivateKey {
+	return internal.Copy(b.private, Curve25519XChacha20Poly1305_SecretKeyBytes)
 }
 
 func (b *Curve25519XChacha20Poly1305) SealDetached(dst, dstMac, nonce, plain []byte, remote godium.PublicKey) (cipher, mac []byte, err error) {
@@ -87,13 +123,17 @@ func (b *Curve25519XChacha20Poly1305) BeforeNM(remote godium.PublicKey) (sb godi
 	var key []byte
 	var zero [16]byte
 
-	s, err = scalarmult.Curve25519(make([]byte, 0, 32), b.PrivateKey, remote)
+	s, err = scalarmult.Curve25519(make([]byte, 0, 32), b.private, remote)
 	if err != nil {
 		return
 	}
 	key = core.HChacha20(make([]byte, 0, 32), zero[:], s, core.Salsa20Sigma[:])
 
 	sb = secretbox.NewXChacha20Poly1305(key[:])
+
+	// the SecretBox holds its own copy of the key.
+	godium.Wipe(s)
+	godium.Wipe(key)
 	return
 }
 
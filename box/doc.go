@@ -6,7 +6,11 @@
 
 /*
 
- */
+Package Box implements public key authenticated encryption. A Box derives
+the shared key on every call, and can be shared by many goroutines. The
+SecretBox returned by BeforeNM is safe for concurrent use as well.
+
+*/
 package box // import "go.artemisc.eu/godium/box"
 
 import (
@@ -28,3 +32,19 @@ func New(private, public []byte) (box godium.Box) {
 	box = NewCurve25519XSalsa20Poly1305(private, public)
 	return
 }
+
+// KeyPair
+func KeyPair(random godium.Random) (box godium.Box, err error) {
+	b, err := KeyPairCurve25519XSalsa20Poly1305(random)
+	if err != nil {
+		return
+	}
+	box = b
+	return
+}
+
+// KeyPairSeed
+func KeyPairSeed(seed []byte) (box godium.Box) {
+	box = KeyPairSeedCurve25519XSalsa20Poly1305(seed)
+	return
+}
@@ -0,0 +1,56 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package box
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+)
+
+const (
+	// Easy_Version is the format version written by Encrypt.
+	Easy_Version = internal.EasyVersion
+
+	// Easy_HeaderBytes is the size of the version header, which is followed by
+	// the nonce.
+	Easy_HeaderBytes = internal.EasyHeaderBytes
+)
+
+// Encrypt seals plain for the remote public key with a random nonce read from
+// rnd, and appends the version header, the nonce and the box to dst. The
+// nonce size of the box is used, so the caller never handles nonces. The
+// output must not overlap plain.
+//
+// A Box has no additional data, so only the nonce is bound to the box: the
+// version byte is not authenticated, and is only checked by Decrypt.
+func Encrypt(b godium.Box, rnd godium.Random, dst, plain []byte, remote godium.PublicKey) (cipher []byte, err error) {
+	out, nonce, err := internal.EasyHeader(dst, rnd, b.NonceBytes())
+	if err != nil {
+		return
+	}
+
+	sealed, err := b.Seal(out[len(out):], nonce, plain, remote)
+	if err != nil {
+		return
+	}
+
+	cipher = internal.EasyAppend(out, sealed)
+	return
+}
+
+// Decrypt opens a box created by Encrypt by the remote public key. It
+// returns godium.ErrUnknownVersion if the box was written with an unsupported
+// format version.
+func Decrypt(b godium.Box, dst, cipher []byte, remote godium.PublicKey) (plain []byte, err error) {
+	nonce, sealed, err := internal.EasyParse(cipher, b.NonceBytes(), b.MacBytes())
+	if err != nil {
+		return
+	}
+
+	plain, err = b.Open(dst, nonce, sealed, remote)
+	return
+}
@@ -0,0 +1,87 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package box
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/random"
+	"go.artemisc.eu/godium/utils"
+)
+
+func TestEasy(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range boxVectors {
+		alice, bob := testBoxes(v.new)
+		plain := testPattern(100)
+
+		// the nonce is the first output of the generator.
+		nonce := make([]byte, alice.NonceBytes())
+		random.NewDeterministic(testBytes(0x80, 32)).Buf(nonce)
+
+		rnd := random.NewDeterministic(testBytes(0x80, 32))
+		cipher, err := Encrypt(alice, rnd, nil, plain, bob.PublicKey())
+		if err != nil {
+			t.Fatal(v.name, err)
+		}
+
+		sealed, _ := alice.Seal(nil, nonce, plain, bob.PublicKey())
+		expect := append(append([]byte{Easy_Version}, nonce...), sealed...)
+		if !bytes.Equal(expect, cipher) {
+			t.Error("expected result did not match computed", v.name, expect, cipher)
+		}
+
+		got, err := Decrypt(bob, nil, cipher, alice.PublicKey())
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, plain, got, err)
+		}
+
+		cipher[0] = Easy_Version + 1
+		if _, err = Decrypt(bob, nil, cipher, alice.PublicKey()); err != godium.ErrUnknownVersion {
+			t.Error("unknown version was accepted", v.name, err)
+		}
+		cipher[0] = Easy_Version
+
+		cipher[1] ^= 1
+		if _, err = Decrypt(bob, nil, cipher, alice.PublicKey()); err != godium.ErrForgedOrCorrupted {
+			t.Error("forged nonce was accepted", v.name, err)
+		}
+
+		short := cipher[:Easy_HeaderBytes+alice.NonceBytes()+alice.MacBytes()-1]
+		if _, err = Decrypt(bob, nil, short, alice.PublicKey()); err != godium.ErrCipherTooShort {
+			t.Error("short box was accepted", v.name, err)
+		}
+	}
+}
+
+func TestEasyPadded(t *testing.T) {
+	rnd := random.NewDeterministic(testBytes(0x80, 32))
+
+	for _, v := range boxVectors {
+		alice, bob := testBoxes(v.new)
+		padAlice := NewPadded(alice, utils.NewBlockPadding(64))
+		padBob := NewPadded(bob, utils.NewBlockPadding(64))
+		plain := testPattern(100)
+
+		// the padded length is not known in advance, with and without room
+		// in dst.
+		for _, dst := range [][]byte{nil, make([]byte, 0, 1000)} {
+			cipher, err := Encrypt(padAlice, rnd, dst, plain, bob.PublicKey())
+			if err != nil || len(cipher) != Easy_HeaderBytes+alice.NonceBytes()+alice.MacBytes()+128 {
+				t.Error("unexpected box length", v.name, len(cipher), err)
+			}
+
+			got, err := Decrypt(padBob, nil, cipher, alice.PublicKey())
+			if err != nil || !bytes.Equal(plain, got) {
+				t.Error("expected result did not match computed", v.name, plain, got, err)
+			}
+		}
+	}
+}
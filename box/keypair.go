@@ -0,0 +1,36 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package box
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/hash"
+	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/scalarmult"
+)
+
+// keyPairSeed derives a key pair from the seed in the same way as libsodium's
+// seed_keypair functions for boxes: the secret key is the first 32 bytes of the
+// SHA-512 hash of the seed. It panics if the seed is not SeedBytes long.
+func keyPairSeed(seed []byte) (private, public []byte) {
+	var digest [hash.Sha512_Bytes]byte
+
+	if len(seed) != SeedBytes {
+		panic("box: invalid seed length")
+	}
+
+	seed = internal.Copy(seed, SeedBytes)
+	defer godium.Wipe(seed)
+	defer godium.Wipe(digest[:])
+
+	hash.SumSha512(digest[:0], seed)
+
+	kp := scalarmult.KeyPairSeedCurve25519(digest[:scalarmult.Curve25519_ScalarBytes])
+	private, public = kp.SecretKey(), kp.PublicKey()
+	kp.Wipe()
+	return
+}
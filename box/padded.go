@@ -0,0 +1,89 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package box
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/secretbox"
+)
+
+// padded wraps a Box, padding every message before it is sealed.
+type padded struct {
+	godium.Box
+	padding godium.Padding
+}
+
+// NewPadded wraps the Box, so that Seal and SealDetached pad the message using
+// the padding scheme before encrypting it, and Open and OpenDetached remove the
+// padding after decrypting. The SecretBox returned by BeforeNM is padded in
+// the same way. The length of the ciphertext then only reveals the padded
+// length of the message.
+func NewPadded(b godium.Box, padding godium.Padding) (box godium.Box) {
+	box = &padded{
+		Box:     b,
+		padding: padding,
+	}
+	return
+}
+
+// SealDetached
+func (b *padded) SealDetached(dst, dstMac, nonce, plain []byte, remote godium.PublicKey) (cipher, mac []byte, err error) {
+	buf := internal.Pad(b.padding, plain)
+	defer godium.Wipe(buf)
+
+	cipher, mac, err = b.Box.SealDetached(dst, dstMac, nonce, buf, remote)
+	return
+}
+
+// Seal
+func (b *padded) Seal(dst, nonce, plain []byte, remote godium.PublicKey) (cipher []byte, err error) {
+	buf := internal.Pad(b.padding, plain)
+	defer godium.Wipe(buf)
+
+	cipher, err = b.Box.Seal(dst, nonce, buf, remote)
+	return
+}
+
+// OpenDetached
+func (b *padded) OpenDetached(dst, nonce, cipher, mac []byte, remote godium.PublicKey) (plain []byte, err error) {
+	buf, err := b.Box.OpenDetached(dst, nonce, cipher, mac, remote)
+	if err != nil {
+		return
+	}
+
+	plain, err = b.padding.Unpad(buf)
+	if err != nil {
+		godium.Wipe(buf)
+	}
+	return
+}
+
+// Open
+func (b *padded) Open(dst, nonce, cipher []byte, remote godium.PublicKey) (plain []byte, err error) {
+	buf, err := b.Box.Open(dst, nonce, cipher, remote)
+	if err != nil {
+		return
+	}
+
+	plain, err = b.padding.Unpad(buf)
+	if err != nil {
+		godium.Wipe(buf)
+	}
+	return
+}
+
+// BeforeNM
+func (b *padded) BeforeNM(remote godium.PublicKey) (sb godium.SecretBox, err error) {
+	s, err := b.Box.BeforeNM(remote)
+	if err != nil {
+		return
+	}
+
+	sb = secretbox.NewPadded(s, b.padding)
+	return
+}
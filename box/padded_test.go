@@ -0,0 +1,99 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package box
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/utils"
+)
+
+func TestPadded(t *testing.T) {
+	skipNonceReuse(t)
+
+	padding := utils.NewBlockPadding(16)
+
+	for _, v := range boxVectors {
+		alice, bob := testBoxes(v.new)
+		paddedAlice, paddedBob := NewPadded(alice, padding), NewPadded(bob, padding)
+		nonce := testBytes(0x40, alice.NonceBytes())
+
+		sbAlice, err := paddedAlice.BeforeNM(bob.PublicKey())
+		if err != nil {
+			t.Fatal(v.name, err)
+		}
+		sbBob, err := paddedBob.BeforeNM(alice.PublicKey())
+		if err != nil {
+			t.Fatal(v.name, err)
+		}
+
+		for _, msg := range [][]byte{{}, []byte("hi"), bytes.Repeat([]byte{0x80}, 16), testPattern(100)} {
+			expect := utils.Pad(append([]byte{}, msg...), 16)
+
+			cipher, err := paddedAlice.Seal(nil, nonce, msg, bob.PublicKey())
+			if err != nil || len(cipher) != alice.MacBytes()+len(expect) {
+				t.Error("padded length did not match", v.name, len(msg), len(cipher), err)
+			}
+
+			// the wrapped box sees the padded message
+			plain, err := bob.Open(nil, nonce, cipher, alice.PublicKey())
+			if err != nil || !bytes.Equal(expect, plain) {
+				t.Error("expected result did not match computed", v.name, expect, plain, err)
+			}
+
+			plain, err = paddedBob.Open(nil, nonce, cipher, alice.PublicKey())
+			if err != nil || !bytes.Equal(msg, plain) {
+				t.Error("expected result did not match computed", v.name, msg, plain, err)
+			}
+
+			c, mac, err := paddedAlice.SealDetached(nil, nil, nonce, msg, bob.PublicKey())
+			if err != nil || !bytes.Equal(cipher, append(append([]byte{}, mac...), c...)) {
+				t.Error("expected result did not match computed", v.name, cipher, c, mac, err)
+			}
+
+			plain, err = paddedBob.OpenDetached(nil, nonce, c, mac, alice.PublicKey())
+			if err != nil || !bytes.Equal(msg, plain) {
+				t.Error("expected result did not match computed", v.name, msg, plain, err)
+			}
+
+			// the precomputed boxes pad in the same way
+			if got := sbAlice.Seal(nil, nonce, msg); !bytes.Equal(cipher, got) {
+				t.Error("expected result did not match computed", v.name, cipher, got)
+			}
+
+			plain, err = sbBob.Open(nil, nonce, cipher)
+			if err != nil || !bytes.Equal(msg, plain) {
+				t.Error("expected result did not match computed", v.name, msg, plain, err)
+			}
+		}
+
+		// a message without padding, and a message with more than a block of
+		// padding, are rejected after authentication
+		for _, buf := range [][]byte{make([]byte, 16), utils.Pad([]byte("hi"), 32)} {
+			cipher, _ := alice.Seal(nil, nonce, buf, bob.PublicKey())
+			if _, err := paddedBob.Open(nil, nonce, cipher, alice.PublicKey()); err != godium.ErrInvalidPadding {
+				t.Error("invalid padding was accepted", v.name, buf, err)
+			}
+
+			c, mac, _ := alice.SealDetached(nil, nil, nonce, buf, bob.PublicKey())
+			if _, err := paddedBob.OpenDetached(nil, nonce, c, mac, alice.PublicKey()); err != godium.ErrInvalidPadding {
+				t.Error("invalid padding was accepted", v.name, buf, err)
+			}
+
+			if _, err := sbBob.Open(nil, nonce, cipher); err != godium.ErrInvalidPadding {
+				t.Error("invalid padding was accepted", v.name, buf, err)
+			}
+
+			cipher[len(cipher)-1] ^= 1
+			if _, err := paddedBob.Open(nil, nonce, cipher, alice.PublicKey()); err != godium.ErrForgedOrCorrupted {
+				t.Error("forged box was accepted", v.name, err)
+			}
+		}
+	}
+}
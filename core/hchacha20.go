@@ -7,9 +7,9 @@
 package core
 
 import (
-	"unsafe"
+	"encoding/binary"
+	"math/bits"
 
-	"git.schwanenlied.me/yawning/chacha20.git"
 	"go.artemisc.eu/godium/internal"
 )
 
@@ -20,24 +20,68 @@ const (
 	HChacha20_ConstBytes  = 16
 )
 
-// HChacha20 implements the chacha20 hash function
+// HChacha20 implements the chacha20 hash function, like
+// crypto_core_hchacha20. It derives a key from the first 16 bytes of nonce
+// and the key. If sigma is empty, the standard constant "expand 32-byte k" is
+// used.
 func HChacha20(dst, nonce, key, sigma []byte) (out []byte) {
 	if len(sigma) == 0 {
-		// todo set to default sigma
-	} else {
-		// for now, not supported
-		panic("HChacha20: using own sigma not yet supported")
+		sigma = Salsa20Sigma[:]
+	} else if len(sigma) < HChacha20_ConstBytes {
+		panic("invalid sigma size")
 	}
 
 	if len(nonce) < HChacha20_InputBytes {
 		panic("invalid nonce size")
 	}
+	if len(key) < HChacha20_KeyBytes {
+		panic("invalid key size")
+	}
 
 	out = internal.AllocDst(dst, HChacha20_OutputBytes)
 
-	chacha20.HChaCha(key,
-		(*[HChacha20_InputBytes]byte)(unsafe.Pointer(&nonce[0])),
-		(*[HChacha20_OutputBytes]byte)(unsafe.Pointer(&out[0])))
+	var x [16]uint32
+	for i := 0; i < 4; i++ {
+		x[i] = binary.LittleEndian.Uint32(sigma[4*i:])
+		x[12+i] = binary.LittleEndian.Uint32(nonce[4*i:])
+	}
+	for i := 0; i < 8; i++ {
+		x[4+i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+
+	for i := 0; i < 10; i++ {
+		// column round
+		hchacha20QuarterRound(&x, 0, 4, 8, 12)
+		hchacha20QuarterRound(&x, 1, 5, 9, 13)
+		hchacha20QuarterRound(&x, 2, 6, 10, 14)
+		hchacha20QuarterRound(&x, 3, 7, 11, 15)
+
+		// diagonal round
+		hchacha20QuarterRound(&x, 0, 5, 10, 15)
+		hchacha20QuarterRound(&x, 1, 6, 11, 12)
+		hchacha20QuarterRound(&x, 2, 7, 8, 13)
+		hchacha20QuarterRound(&x, 3, 4, 9, 14)
+	}
+
+	// unlike a ChaCha20 block, the input is not added, and only the first and
+	// last row are output.
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint32(out[4*i:], x[i])
+		binary.LittleEndian.PutUint32(out[16+4*i:], x[12+i])
+	}
 
+	x = [16]uint32{}
 	return
 }
+
+// hchacha20QuarterRound mixes the words a, b, c and d of the state.
+func hchacha20QuarterRound(x *[16]uint32, a, b, c, d int) {
+	x[a] += x[b]
+	x[d] = bits.RotateLeft32(x[d]^x[a], 16)
+	x[c] += x[d]
+	x[b] = bits.RotateLeft32(x[b]^x[c], 12)
+	x[a] += x[b]
+	x[d] = bits.RotateLeft32(x[d]^x[a], 8)
+	x[c] += x[d]
+	x[b] = bits.RotateLeft32(x[b]^x[c], 7)
+}
@@ -7,30 +7,28 @@
 package core
 
 import (
+	"bytes"
+	"encoding/hex"
 	"testing"
 )
 
 // TestHChacha20
 func TestHChacha20(t *testing.T) {
-	return // for now, skip this
-	//var (
-	//	shared = [32]byte{
-	//	}
-	//
-	//	zero = [32]byte{}
-	//
-	//	c = [16]byte{
-	//	}
-	//
-	//	firstKey = make([]byte, 16)
-	//
-	//	expect = []byte{
-	//	}
-	//)
-	//
-	//firstKey = HChacha20(firstKey[:0], zero[:], shared[:], c[:])
-	//
-	//if !bytes.Equal(expect, firstKey) {
-	//	t.Error("expected result did not match computed", expect, firstKey)
-	//}
+	// draft-irtf-cfrg-xchacha-03, section 2.2.1
+	key := make([]byte, HChacha20_KeyBytes)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce, _ := hex.DecodeString("000000090000004a0000000031415927")
+	expect, _ := hex.DecodeString("82413b4227b27bfed30e42508a877d73a0f9e4d58a74a853c12ec41326d3ecdc")
+
+	got := HChacha20(nil, nonce, key, nil)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	got = HChacha20(got[:0], nonce, key, Salsa20Sigma[:])
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
 }
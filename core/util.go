@@ -7,32 +7,28 @@
 package core
 
 import (
-	"crypto/subtle"
+	"go.artemisc.eu/godium/utils"
 )
 
 // IsZero returns true if all bytes in buf are 0.
+//
+// Deprecated: use utils.IsZero.
 func IsZero(buf []byte) (zero bool) {
-	var d uint8
-	for i := range buf {
-		d |= buf[i]
-	}
-	return (1 & ((d - 1) >> 8)) != 1
+	zero = utils.IsZero(buf)
+	return
 }
 
 // Increment adds 1 to the value of buf represented as a number in little endian
 // form.
 //
-// TODO implement in ASM?
+// Deprecated: use utils.Increment.
 func Increment(buf []byte) {
-	var c uint16
-	for i := range buf {
-		c += uint16(buf[i])
-		buf[i] = uint8(c)
-		c >>= 8
-	}
+	utils.Increment(buf)
 }
 
-// Equals TODO
+// Equals returns true if a and b are equal, in constant time.
+//
+// Deprecated: use utils.Equal.
 func Equals(a, b []byte) bool {
-	return subtle.ConstantTimeCompare(a, b) == 1
+	return utils.Equal(a, b)
 }
@@ -7,9 +7,8 @@
 package generichash
 
 import (
-	"hash"
+	"encoding/binary"
 
-	"github.com/minio/blake2b-simd"
 	"go.artemisc.eu/godium"
 	"go.artemisc.eu/godium/internal"
 )
@@ -23,19 +22,49 @@ const (
 	Blake2b_KeyBytes      = 32
 	Blake2b_SaltBytes     = 16
 	Blake2b_PersonalBytes = 16
+	Blake2b_BlockBytes    = 128
 
 	size256 = 32
 	size512 = 64
+
+	// blake2bStateBytes is the size of the serialized state: the chain value,
+	// the counter, the buffer offset, and the buffer.
+	blake2bStateBytes = 8*8 + 2*8 + 1 + Blake2b_BlockBytes
 )
 
-// Blake2b
+// blake2bParams holds the fields of the BLAKE2b parameter block.
+type blake2bParams struct {
+	size        uint8
+	keyLen      uint8
+	fanout      uint8
+	depth       uint8
+	leafLength  uint32
+	nodeOffset  uint64
+	nodeDepth   uint8
+	innerLength uint8
+	salt        [Blake2b_SaltBytes]byte
+	personal    [Blake2b_PersonalBytes]byte
+	lastNode    bool
+}
+
+// Blake2b implements the godium.GenericHash interface for BLAKE2b, with
+// optional key, salt and personalization.
 type Blake2b struct {
-	hash.Hash
+	h        [8]uint64
+	t        [2]uint64
+	buf      [Blake2b_BlockBytes]byte
+	nx       int
+	iv       [8]uint64
+	key      [Blake2b_BlockBytes]byte
+	keyed    bool
+	lastNode bool
+	size     int
 }
 
 // Blake2bSum256
 func Blake2bSum256(data []byte) (sum [32]byte) {
-	b := NewBlake2b256(nil)
+	var b Blake2b
+	b.init(&blake2bParams{size: size256, fanout: 1, depth: 1}, nil)
 	b.Write(data)
 	b.Sum(sum[:0])
 	return
@@ -43,77 +72,294 @@ func Blake2bSum256(data []byte) (sum [32]byte) {
 
 // Blake2bSum512
 func Blake2bSum512(data []byte) (sum [64]byte) {
-	b := NewBlake2b512(nil)
+	var b Blake2b
+	b.init(&blake2bParams{size: size512, fanout: 1, depth: 1}, nil)
 	b.Write(data)
 	b.Sum(sum[:0])
 	return
 }
 
-// NewBlake2b256
-func NewBlake2b256(key []byte) (gh godium.GenericHash) {
-	var h hash.Hash
-	if len(key) == 0 {
-		h = blake2b.New256()
-	} else {
-		h = blake2b.NewMAC(size256, key)
-	}
-	gh = &Blake2b{
-		Hash: h,
+// Blake2bSum appends the size byte hash of data, keyed with the optional key,
+// to dst, like crypto_generichash_blake2b.
+func Blake2bSum(dst, data []byte, size uint32, key []byte) (sum []byte, err error) {
+	sum, err = Blake2bSumSaltPersonal(dst, data, size, key, nil, nil)
+	return
+}
+
+// Blake2bSumSaltPersonal appends the size byte hash of data, keyed with the
+// optional key and using the optional personal and salt, to dst, like
+// crypto_generichash_blake2b_salt_personal. It does not allocate if dst has
+// enough capacity.
+func Blake2bSumSaltPersonal(dst, data []byte, size uint32, key, personal, salt []byte) (sum []byte, err error) {
+	var b Blake2b
+
+	p, err := blake2bSaltPersonalParams(size, key, personal, salt)
+	if err != nil {
+		return
 	}
+
+	b.init(&p, key)
+	b.Write(data)
+	sum = b.Sum(dst)
+	b.Wipe()
+	return
+}
+
+// NewBlake2b256 creates a BLAKE2b hash with a 32 byte output, keyed with the
+// optional key. It panics if the key is longer than Blake2b_KeyBytesMax, use
+// NewBlake2b to get an error instead.
+func NewBlake2b256(key []byte) (gh godium.GenericHash) {
+	gh = newBlake2b(&blake2bParams{size: size256, fanout: 1, depth: 1}, key)
 	return
 }
 
-// NewBlake2b512
+// NewBlake2b512 creates a BLAKE2b hash with a 64 byte output, keyed with the
+// optional key. It panics if the key is longer than Blake2b_KeyBytesMax, use
+// NewBlake2b to get an error instead.
 func NewBlake2b512(key []byte) (gh godium.GenericHash) {
-	var h hash.Hash
-	if len(key) == 0 {
-		h = blake2b.New512()
-	} else {
-		h = blake2b.NewMAC(size512, key)
+	gh = newBlake2b(&blake2bParams{size: size512, fanout: 1, depth: 1}, key)
+	return
+}
+
+// NewBlake2b creates a BLAKE2b hash with an output of size bytes, keyed with
+// the optional key. The size must be between Blake2b_BytesMin and
+// Blake2b_BytesMax, the key can be at most Blake2b_KeyBytesMax bytes.
+func NewBlake2b(size uint32, key []byte) (gh godium.GenericHash, err error) {
+	gh, err = NewBlake2bSaltPersonal(size, key, nil, nil)
+	return
+}
+
+// NewBlake2bSaltPersonal creates a BLAKE2b hash like NewBlake2b, that also
+// uses the optional personal and salt. Both can be at most 16 bytes, shorter
+// values are padded with zeros.
+func NewBlake2bSaltPersonal(size uint32, key, personal, salt []byte) (gh godium.GenericHash, err error) {
+	p, err := blake2bSaltPersonalParams(size, key, personal, salt)
+	if err != nil {
+		return
 	}
-	gh = &Blake2b{
-		Hash: h,
+
+	gh = newBlake2b(&p, key)
+	return
+}
+
+// blake2bSaltPersonalParams validates the arguments of NewBlake2bSaltPersonal,
+// and returns the parameter block for a sequential hash.
+func blake2bSaltPersonalParams(size uint32, key, personal, salt []byte) (p blake2bParams, err error) {
+	switch {
+	case size < Blake2b_BytesMin || size > Blake2b_BytesMax:
+		err = ErrInvalidSize
+	case len(key) > Blake2b_KeyBytesMax:
+		err = ErrInvalidKeySize
+	case len(personal) > Blake2b_PersonalBytes:
+		err = ErrInvalidPersonalSize
+	case len(salt) > Blake2b_SaltBytes:
+		err = ErrInvalidSaltSize
+	}
+	if err != nil {
+		return
 	}
+
+	p = blake2bParams{size: uint8(size), fanout: 1, depth: 1}
+	copy(p.personal[:], personal)
+	copy(p.salt[:], salt)
 	return
 }
 
-// NewBlake2bXOF
-func NewBlake2b(size uint32, key []byte) (gh godium.GenericHash) {
-	h := blake2b.NewMAC(uint8(size), key)
-	gh = &Blake2b{
-		Hash: h,
+// iv returns the initial chain value for the parameter block.
+func (p *blake2bParams) iv() (iv [8]uint64) {
+	var block [64]byte
+
+	block[0] = p.size
+	block[1] = p.keyLen
+	block[2] = p.fanout
+	block[3] = p.depth
+	binary.LittleEndian.PutUint32(block[4:], p.leafLength)
+	binary.LittleEndian.PutUint64(block[8:], p.nodeOffset)
+	block[16] = p.nodeDepth
+	block[17] = p.innerLength
+	copy(block[32:], p.salt[:])
+	copy(block[48:], p.personal[:])
+
+	for i := range iv {
+		iv[i] = blake2bIV[i] ^ binary.LittleEndian.Uint64(block[i*8:])
 	}
 	return
 }
 
-// NewBlake2bSaltPersonal
-func NewBlake2bSaltPersonal(size uint32, key, personal, salt []byte) (b *Blake2b) {
-	c := new(blake2b.Config)
-	c.Size = uint8(size)
-	c.Key = internal.Copy(key, uint64(len(key)))
-	if personal != nil {
+// newBlake2b creates a BLAKE2b state from the parameter block and the key. The
+// parameters must have been validated by the caller.
+func newBlake2b(p *blake2bParams, key []byte) (b *Blake2b) {
+	b = new(Blake2b)
+	b.init(p, key)
+	return
+}
 
-		c.Person = internal.Copy(personal, Blake2b_PersonalBytes)
+// init sets up the state like newBlake2b, so one-shot functions can keep the
+// state on the stack. A key that does not fit the key block panics, instead of
+// being truncated.
+func (b *Blake2b) init(p *blake2bParams, key []byte) {
+	if len(key) > Blake2b_KeyBytesMax {
+		panic("generichash: invalid key length")
 	}
-	if salt != nil {
-		c.Salt = internal.Copy(salt, Blake2b_SaltBytes)
+
+	p.keyLen = uint8(len(key))
+
+	*b = Blake2b{
+		iv:       p.iv(),
+		keyed:    len(key) > 0,
+		lastNode: p.lastNode,
+		size:     int(p.size),
 	}
-	h, _ := blake2b.New(c)
+	copy(b.key[:], key)
 
-	b = &Blake2b{
-		Hash: h,
+	b.Reset()
+}
+
+// Reset restores the state to the one after creation, including the key.
+func (b *Blake2b) Reset() {
+	b.h = b.iv
+	b.t[0], b.t[1] = 0, 0
+	b.nx = 0
+
+	if b.keyed {
+		b.buf = b.key
+		b.nx = Blake2b_BlockBytes
 	}
+}
+
+// Write implements io.Writer. The last block is kept in the buffer, as it
+// needs to be processed with the finalization flag set.
+func (b *Blake2b) Write(p []byte) (n int, err error) {
+	n = len(p)
+
+	if b.nx > 0 {
+		if len(p) <= Blake2b_BlockBytes-b.nx {
+			b.nx += copy(b.buf[b.nx:], p)
+			return
+		}
+
+		c := copy(b.buf[b.nx:], p)
+		blake2bBlocks(&b.h, &b.t, 0, 0, b.buf[:])
+		b.nx = 0
+		p = p[c:]
+	}
+
+	if len(p) > Blake2b_BlockBytes {
+		c := len(p) &^ (Blake2b_BlockBytes - 1)
+		if c == len(p) {
+			c -= Blake2b_BlockBytes
+		}
+		blake2bBlocks(&b.h, &b.t, 0, 0, p[:c])
+		p = p[c:]
+	}
+
+	b.nx += copy(b.buf[:], p)
+	return
+}
+
+// Sum appends the hash to dst, without changing the state.
+func (b *Blake2b) Sum(dst []byte) (sum []byte) {
+	var out [Blake2b_BytesMax]byte
+
+	b.checkSum(&out)
+	sum = append(dst, out[:b.size]...)
 	return
 }
 
-// Wipe
+// checkSum finalizes a copy of the state, and writes the full chain value to
+// out.
+func (b *Blake2b) checkSum(out *[Blake2b_BytesMax]byte) {
+	var block [Blake2b_BlockBytes]byte
+	var f1 uint64
+
+	h, t := b.h, b.t
+	copy(block[:], b.buf[:b.nx])
+
+	rem := uint64(Blake2b_BlockBytes - b.nx)
+	if t[0] < rem {
+		t[1]--
+	}
+	t[0] -= rem
+
+	if b.lastNode {
+		f1 = ^uint64(0)
+	}
+	blake2bBlocks(&h, &t, ^uint64(0), f1, block[:])
+
+	for i, v := range h {
+		binary.LittleEndian.PutUint64(out[i*8:], v)
+	}
+	godium.Wipe(block[:])
+}
+
+// Wipe erases the key and the state.
 func (b *Blake2b) Wipe() {
-	b.Hash.Reset()
+	godium.Wipe(b.key[:])
+	godium.Wipe(b.buf[:])
+	for i := range b.h {
+		b.h[i] = 0
+		b.iv[i] = 0
+	}
+	b.t[0], b.t[1] = 0, 0
+	b.nx = 0
+	b.keyed = false
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The state contains the
+// hash of all data written so far, but not the key, salt and personalization.
+// It must be restored into a hash created with the same parameters, and should
+// be kept as secret as the data when a key is used.
+func (b *Blake2b) MarshalBinary() (state []byte, err error) {
+	payload := make([]byte, 0, blake2bStateBytes)
+	for _, v := range b.h {
+		payload = appendUint64(payload, v)
+	}
+	payload = appendUint64(payload, b.t[0])
+	payload = appendUint64(payload, b.t[1])
+	payload = append(payload, byte(b.nx))
+	payload = append(payload, b.buf[:]...)
+
+	state = internal.MarshalState(Primitive, b.size, payload)
+	godium.Wipe(payload)
+	return
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It returns
+// godium.ErrInvalidState if the state was not created by a BLAKE2b hash with
+// the same output size.
+func (b *Blake2b) UnmarshalBinary(state []byte) (err error) {
+	payload, err := internal.UnmarshalState(state, Primitive, b.size)
+	if err != nil {
+		return
+	}
+
+	if len(payload) != blake2bStateBytes || payload[8*8+2*8] > Blake2b_BlockBytes {
+		err = godium.ErrInvalidState
+		return
+	}
+
+	for i := range b.h {
+		b.h[i] = binary.LittleEndian.Uint64(payload[i*8:])
+	}
+	b.t[0] = binary.LittleEndian.Uint64(payload[8*8:])
+	b.t[1] = binary.LittleEndian.Uint64(payload[9*8:])
+	b.nx = int(payload[10*8])
+	copy(b.buf[:], payload[10*8+1:])
+	return
+}
+
+// appendUint64 appends v to b in little endian order.
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
 }
 
+func (b *Blake2b) Size() int          { return b.size }
+func (b *Blake2b) BlockSize() int     { return Blake2b_BlockBytes }
 func (b *Blake2b) BytesMin() int      { return Blake2b_BytesMin }
 func (b *Blake2b) BytesMax() int      { return Blake2b_BytesMax }
-func (b *Blake2b) Bytes() int         { return Blake2b_Bytes }
+func (b *Blake2b) Bytes() int         { return b.size }
 func (b *Blake2b) KeyBytesMin() int   { return Blake2b_KeyBytesMin }
 func (b *Blake2b) KeyBytesMax() int   { return Blake2b_KeyBytesMax }
 func (b *Blake2b) KeyBytes() int      { return Blake2b_KeyBytes }
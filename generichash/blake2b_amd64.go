@@ -0,0 +1,47 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+package generichash
+
+import (
+	"go.artemisc.eu/godium/internal/cpu"
+)
+
+var (
+	useAVX2 = cpu.X86.HasAVX2
+	useAVX  = cpu.X86.HasAVX
+	useSSE4 = cpu.X86.HasSSE41 && cpu.X86.HasSSSE3
+)
+
+//go:noescape
+func blake2bBlocksAVX2(h *[8]uint64, c *[2]uint64, flag uint64, blocks []byte)
+
+//go:noescape
+func blake2bBlocksAVX(h *[8]uint64, c *[2]uint64, flag uint64, blocks []byte)
+
+//go:noescape
+func blake2bBlocksSSE4(h *[8]uint64, c *[2]uint64, flag uint64, blocks []byte)
+
+// blake2bBlocks compresses the blocks into the state h, see
+// blake2bBlocksGeneric. The assembly implementations do not support the last
+// node flag, which is only set for the final block of the last node in a tree
+// level.
+func blake2bBlocks(h *[8]uint64, c *[2]uint64, f0, f1 uint64, blocks []byte) {
+	switch {
+	case f1 != 0:
+		blake2bBlocksGeneric(h, c, f0, f1, blocks)
+	case useAVX2:
+		blake2bBlocksAVX2(h, c, f0, blocks)
+	case useAVX:
+		blake2bBlocksAVX(h, c, f0, blocks)
+	case useSSE4:
+		blake2bBlocksSSE4(h, c, f0, blocks)
+	default:
+		blake2bBlocksGeneric(h, c, f0, f1, blocks)
+	}
+}
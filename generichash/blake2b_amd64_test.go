@@ -0,0 +1,98 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+package generichash
+
+import (
+	"bytes"
+	"testing"
+)
+
+// blake2bKernels lists the assembly implementations that are supported by the
+// processor, with a function that enables only that implementation.
+func blake2bKernels() (names []string, enable []func()) {
+	avx2, avx, sse4 := useAVX2, useAVX, useSSE4
+
+	set := func(a, b, c bool) func() {
+		return func() { useAVX2, useAVX, useSSE4 = a, b, c }
+	}
+
+	names = append(names, "generic")
+	enable = append(enable, set(false, false, false))
+	if sse4 {
+		names = append(names, "SSE4")
+		enable = append(enable, set(false, false, true))
+	}
+	if avx {
+		names = append(names, "AVX")
+		enable = append(enable, set(false, true, false))
+	}
+	if avx2 {
+		names = append(names, "AVX2")
+		enable = append(enable, set(true, false, false))
+	}
+	names = append(names, "default")
+	enable = append(enable, set(avx2, avx, sse4))
+	return
+}
+
+func TestBlake2bKernels(t *testing.T) {
+	names, enable := blake2bKernels()
+	defer enable[len(enable)-1]()
+
+	data := testPattern(8192 + 63)
+	var expect [][]byte
+
+	enable[0]()
+	for n := 0; n <= len(data); n += 509 {
+		sum, _ := Blake2bSum(nil, data[:n], Blake2b_BytesMax, data[:n%65])
+		expect = append(expect, sum)
+	}
+
+	for i, name := range names {
+		enable[i]()
+		t.Run(name, TestBlake2bKAT)
+		t.Run(name, TestBlake2bpKAT)
+
+		for j, n := 0, 0; n <= len(data); j, n = j+1, n+509 {
+			got, _ := Blake2bSum(nil, data[:n], Blake2b_BytesMax, data[:n%65])
+			if !bytes.Equal(expect[j], got) {
+				t.Error("expected result did not match computed", name, n, expect[j], got)
+			}
+		}
+	}
+}
+
+func BenchmarkBlake2bKernels(b *testing.B) {
+	names, enable := blake2bKernels()
+	defer enable[len(enable)-1]()
+
+	for i, name := range names {
+		enable[i]()
+		for _, size := range []int{64, 1024, 8192} {
+			data := testPattern(size)
+			b.Run(name+"/"+itoa(size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				for i := 0; i < b.N; i++ {
+					Blake2bSum512(data)
+				}
+			})
+		}
+	}
+}
+
+// itoa formats small positive numbers for benchmark names.
+func itoa(n int) string {
+	var buf [20]byte
+	i := len(buf)
+	for ; n > 0 || i == len(buf); n /= 10 {
+		i--
+		buf[i] = byte('0' + n%10)
+	}
+	return string(buf[i:])
+}
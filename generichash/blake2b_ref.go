@@ -0,0 +1,15 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build !amd64 !gc purego
+
+package generichash
+
+// blake2bBlocks compresses the blocks into the state h, see
+// blake2bBlocksGeneric.
+func blake2bBlocks(h *[8]uint64, c *[2]uint64, f0, f1 uint64, blocks []byte) {
+	blake2bBlocksGeneric(h, c, f0, f1, blocks)
+}
@@ -0,0 +1,67 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"bytes"
+	"encoding"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+func TestBlake2bState(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	msg := bytes.Repeat([]byte("godium"), 100)
+
+	for _, split := range []int{0, 1, 127, 128, 129, 256, len(msg)} {
+		h, _ := NewBlake2b(48, key)
+		h.Write(msg[:split])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resumed, _ := NewBlake2b(48, key)
+		if err = resumed.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+		resumed.Write(msg[split:])
+
+		expect, _ := Blake2bSum(nil, msg, 48, key)
+		got := resumed.Sum(nil)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", split, expect, got)
+		}
+	}
+
+	state, _ := NewBlake2b256(nil).(encoding.BinaryMarshaler).MarshalBinary()
+	err := NewBlake2b512(nil).(encoding.BinaryUnmarshaler).UnmarshalBinary(state)
+	if err != godium.ErrInvalidState {
+		t.Error("state was restored into a hash with a different size")
+	}
+
+	err = NewBlake2b256(nil).(encoding.BinaryUnmarshaler).UnmarshalBinary(state[:len(state)-1])
+	if err != godium.ErrInvalidState {
+		t.Error("truncated state was restored")
+	}
+}
+
+func TestBlake2bReset(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	expect, _ := Blake2bSum(nil, []byte("abc"), 32, key)
+
+	h, _ := NewBlake2b(32, key)
+	h.Write([]byte("something else"))
+	h.Reset()
+	h.Write([]byte("abc"))
+
+	got := h.Sum(nil)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
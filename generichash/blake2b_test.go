@@ -9,7 +9,7 @@ import (
 // Regression test for https://github.com/ArteMisc/libgodium/issues/2
 func TestNewBlake2bSaltPersonal(t *testing.T) {
 	const (
-		hashSz = 1
+		hashSz = 16
 		testSliceSz = 5
 	)
 	sliceGen := func(b byte) []byte {
@@ -31,8 +31,17 @@ func TestNewBlake2bSaltPersonal(t *testing.T) {
 		salt2 = sliceGen(222)
 	)
 
-	bs1 := NewBlake2bSaltPersonal(hashSz, key1, personal1, salt1).Hash.Sum(nil)
-	bs2 := NewBlake2bSaltPersonal(hashSz, key2, personal2, salt2).Hash.Sum(nil)
+	h1, err := NewBlake2bSaltPersonal(hashSz, key1, personal1, salt1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := NewBlake2bSaltPersonal(hashSz, key2, personal2, salt2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bs1 := h1.Sum(nil)
+	bs2 := h2.Sum(nil)
 
 	if reflect.DeepEqual(bs1, bs2) {
 		t.Error("Keys derived from two different sets of key/personal/salt data should not be equal")
@@ -0,0 +1,209 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"runtime"
+	"sync"
+
+	"go.artemisc.eu/godium"
+)
+
+const (
+	Blake2bp_Parallelism = 4
+	Blake2bp_BlockBytes  = Blake2bp_Parallelism * Blake2b_BlockBytes
+
+	// blake2bpParallelBytes is the minimum number of stripes in a single Write
+	// for which the leaves are hashed by separate goroutines.
+	blake2bpParallelBytes = 64 * Blake2bp_BlockBytes
+)
+
+// Blake2bp implements the godium.GenericHash interface for BLAKE2bp, the
+// 4-way parallel variant of BLAKE2b. The input is divided in stripes of 4
+// blocks, the i-th block of every stripe is hashed by the i-th leaf. The root
+// hashes the leaf hashes into the final output. Large writes hash the leaves
+// concurrently when GOMAXPROCS allows it.
+type Blake2bp struct {
+	leaves [Blake2bp_Parallelism]Blake2b
+	root   blake2bParams
+	buf    [Blake2bp_BlockBytes]byte
+	nx     int
+	size   int
+}
+
+// Blake2bpSum appends the size byte BLAKE2bp hash of data, keyed with the
+// optional key, to dst.
+func Blake2bpSum(dst, data []byte, size uint32, key []byte) (sum []byte, err error) {
+	h, err := NewBlake2bp(size, key)
+	if err != nil {
+		return
+	}
+
+	h.Write(data)
+	sum = h.Sum(dst)
+	h.Wipe()
+	return
+}
+
+// NewBlake2bp creates a BLAKE2bp hash with an output of size bytes, keyed with
+// the optional key. The limits on size and key are the same as for
+// NewBlake2b.
+func NewBlake2bp(size uint32, key []byte) (gh godium.GenericHash, err error) {
+	switch {
+	case size < Blake2b_BytesMin || size > Blake2b_BytesMax:
+		err = ErrInvalidSize
+	case len(key) > Blake2b_KeyBytesMax:
+		err = ErrInvalidKeySize
+	}
+	if err != nil {
+		return
+	}
+
+	b := &Blake2bp{
+		root: blake2bParams{
+			size:        uint8(size),
+			keyLen:      uint8(len(key)),
+			fanout:      Blake2bp_Parallelism,
+			depth:       2,
+			nodeDepth:   1,
+			innerLength: Blake2b_BytesMax,
+			lastNode:    true,
+		},
+		size: int(size),
+	}
+
+	for i := range b.leaves {
+		leaf := b.root
+		leaf.nodeOffset = uint64(i)
+		leaf.nodeDepth = 0
+		leaf.lastNode = i == Blake2bp_Parallelism-1
+		b.leaves[i] = *newBlake2b(&leaf, key)
+	}
+
+	gh = b
+	return
+}
+
+// Reset restores the state to the one after creation, including the key.
+func (b *Blake2bp) Reset() {
+	for i := range b.leaves {
+		b.leaves[i].Reset()
+	}
+	b.nx = 0
+}
+
+// Write implements io.Writer. Complete stripes are passed to the leaves, the
+// remainder is buffered.
+func (b *Blake2bp) Write(p []byte) (n int, err error) {
+	n = len(p)
+
+	if b.nx > 0 {
+		c := copy(b.buf[b.nx:], p)
+		b.nx += c
+		p = p[c:]
+
+		if b.nx < Blake2bp_BlockBytes {
+			return
+		}
+
+		b.writeStripes(b.buf[:])
+		b.nx = 0
+	}
+
+	c := len(p) &^ (Blake2bp_BlockBytes - 1)
+	b.writeStripes(p[:c])
+	b.nx = copy(b.buf[:], p[c:])
+	return
+}
+
+// writeStripes passes complete stripes to the leaves, using a goroutine per
+// leaf for large inputs.
+func (b *Blake2bp) writeStripes(p []byte) {
+	if len(p) < blake2bpParallelBytes || runtime.GOMAXPROCS(0) == 1 {
+		for i := range b.leaves {
+			b.writeLeaf(i, p)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(Blake2bp_Parallelism)
+	for i := range b.leaves {
+		go func(i int) {
+			b.writeLeaf(i, p)
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+}
+
+// writeLeaf writes the i-th block of every stripe in p to leaf i.
+func (b *Blake2bp) writeLeaf(i int, p []byte) {
+	leaf := &b.leaves[i]
+	for off := i * Blake2b_BlockBytes; off < len(p); off += Blake2bp_BlockBytes {
+		leaf.Write(p[off : off+Blake2b_BlockBytes])
+	}
+}
+
+// Sum appends the hash to dst, without changing the state.
+func (b *Blake2bp) Sum(dst []byte) (sum []byte) {
+	var out [Blake2b_BytesMax]byte
+
+	root := newBlake2bInner(&b.root)
+	for i := range b.leaves {
+		leaf := b.leaves[i]
+
+		if off := i * Blake2b_BlockBytes; off < b.nx {
+			end := off + Blake2b_BlockBytes
+			if end > b.nx {
+				end = b.nx
+			}
+			leaf.Write(b.buf[off:end])
+		}
+
+		leaf.checkSum(&out)
+		root.Write(out[:])
+		leaf.Wipe()
+	}
+
+	sum = root.Sum(dst)
+	godium.Wipe(out[:])
+	root.Wipe()
+	return
+}
+
+// Wipe erases the key and the state.
+func (b *Blake2bp) Wipe() {
+	for i := range b.leaves {
+		b.leaves[i].Wipe()
+	}
+	godium.Wipe(b.buf[:])
+	b.nx = 0
+}
+
+// newBlake2bInner creates the BLAKE2b state of an inner node of a tree. Only
+// the leaves absorb the key, but the key length in the parameter block of inner
+// nodes is left as set by the caller.
+func newBlake2bInner(p *blake2bParams) (b *Blake2b) {
+	b = &Blake2b{
+		iv:       p.iv(),
+		lastNode: p.lastNode,
+		size:     int(p.size),
+	}
+
+	b.Reset()
+	return
+}
+
+func (b *Blake2bp) Size() int        { return b.size }
+func (b *Blake2bp) BlockSize() int   { return Blake2bp_BlockBytes }
+func (b *Blake2bp) BytesMin() int    { return Blake2b_BytesMin }
+func (b *Blake2bp) BytesMax() int    { return Blake2b_BytesMax }
+func (b *Blake2bp) Bytes() int       { return b.size }
+func (b *Blake2bp) KeyBytesMin() int { return Blake2b_KeyBytesMin }
+func (b *Blake2bp) KeyBytesMax() int { return Blake2b_KeyBytesMax }
+func (b *Blake2bp) KeyBytes() int    { return Blake2b_KeyBytes }
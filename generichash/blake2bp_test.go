@@ -0,0 +1,104 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// blake2bpKAT holds keyed BLAKE2bp known answers for the input bytes
+// i % 251 for i in 0, ..., size-1, keyed with the bytes 0x00, ..., 0x3f. The
+// entries up to 255 bytes match blake2bp-kat.txt from the reference
+// implementation.
+var blake2bpKAT = []struct {
+	size   int
+	expect string
+}{
+	{0, "9d9461073e4eb640a255357b839f394b838c6ff57c9b686a3f76107c1066728f" +
+		"3c9956bd785cbc3bf79dc2ab578c5a0c063b9d9c405848de1dbe821cd05c940a"},
+	{1, "ff8e90a37b94623932c59f7559f26035029c376732cb14d41602001cbb73adb7" +
+		"9293a2dbda5f60703025144d158e2735529596251c73c0345ca6fccb1fb1e97e"},
+	{127, "7926708859e6e2ab68f604da69a9fb5087bb33f4e8d895730e301ab2d7df748b" +
+		"67df0b6b8622e52dd57d8d3ad87d5820d4ecfd24178b2d2b78d64f4fbd387582"},
+	{128, "9280f4d1157032ab315c100d636283fbf4fba2fbad0f8bc020721d76bc1c8973" +
+		"ced28871cc907dab60e59756987b0e0f867fa2fe9d9041f2c9618074e44fe5e9"},
+	{129, "5530c2d59f144872e987e4e258a7d8c38ce844e2cc2eed940ffc683b498815e5" +
+		"3adb1faaf568946122805ac3b8e2fed435fed6162e76f564e586ba464424e885"},
+	{511, "6fef2a9d6651694dc496a1e75bc3d21c3472a5043a339dafd1879f14b1fbe353" +
+		"cbabecd97de35c05bcf6a6d43861449afacfbac3f9ecd4daf968fcd9841ec39a"},
+	{512, "86dfba5b50da48a602446246ac0a16c2a5e2f8e396072065b9e7991ed9c0f436" +
+		"ae5b3b61607c15c4b251d2679e3c846024ed1833b4d7594a34a68631bb5c0b49"},
+	{513, "a55cf608515924ac36c056e9e8576d8e85def53d168912f770ad68bbd5d61973" +
+		"a188bb14f497c2585075fca439c6160abf4695fd631e527d759c18803c2dbcfc"},
+	{1000, "7783948da8fd47a8bf448ed1ba0baa7d898a6b353b9231696ca0f7bb4594cc81" +
+		"9ee8bc0253307634dbd6561035b3a5446e02aaffa4527e0eaa7f6cced9610330"},
+}
+
+// testPattern returns size bytes of i % 251.
+func testPattern(size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = byte(i % 251)
+	}
+	return
+}
+
+func TestBlake2bpKAT(t *testing.T) {
+	key := testPattern(64)
+
+	for _, test := range blake2bpKAT {
+		expect, _ := hex.DecodeString(test.expect)
+		data := testPattern(test.size)
+
+		got, err := Blake2bpSum(nil, data, 64, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", test.size, expect, got)
+		}
+
+		// byte by byte, to test the stripe buffer
+		h, _ := NewBlake2bp(64, key)
+		for i := range data {
+			h.Write(data[i : i+1])
+		}
+		if got = h.Sum(nil); !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", test.size, expect, got)
+		}
+	}
+}
+
+func TestBlake2bpParallel(t *testing.T) {
+	expect, _ := hex.DecodeString("ccd3d02bfacf9da8234027dff73a5c12deb23568143ea0c45080334b64f9205f")
+	data := testPattern(100000)
+
+	for _, chunk := range []int{len(data), 4096, 1000} {
+		h, _ := NewBlake2bp(32, nil)
+		for p := data; len(p) > 0; {
+			c := chunk
+			if c > len(p) {
+				c = len(p)
+			}
+			h.Write(p[:c])
+			p = p[c:]
+		}
+
+		if got := h.Sum(nil); !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", chunk, expect, got)
+		}
+	}
+}
+
+func BenchmarkBlake2bp1M(b *testing.B) {
+	benchmarkHash(b, 1<<20, func() hashWriter {
+		h, _ := NewBlake2bp(64, nil)
+		return h
+	})
+}
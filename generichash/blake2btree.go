@@ -0,0 +1,478 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"go.artemisc.eu/godium"
+)
+
+const (
+	// Blake2bTree_FanoutUnlimited lets every inner node have any number of
+	// children, which results in a tree with all leaves directly below the
+	// root.
+	Blake2bTree_FanoutUnlimited = 0
+	Blake2bTree_DepthMin        = 2
+	Blake2bTree_DepthMax        = 255
+	Blake2bTree_InnerBytes      = Blake2b_BytesMax
+
+	// blake2bTreeBatchLeaves is the maximum number of leaves hashed
+	// concurrently before their hashes are passed up the tree.
+	blake2bTreeBatchLeaves = 1024
+
+	// blake2bTreeBufferBytes is the amount of input buffered per worker by
+	// ReadFrom, unless a buffer of a leaf per worker fits in
+	// blake2bTreeBufferMax.
+	blake2bTreeBufferBytes = 1 << 20
+	blake2bTreeBufferMax   = 64 << 20
+)
+
+// Blake2bTree implements the godium.GenericHash interface for BLAKE2b in tree
+// hashing mode, as described in section 2.10 of the BLAKE2 specification.
+//
+// The input is divided in consecutive leaves of leafLength bytes, which are
+// hashed independently, so large inputs can be hashed by multiple goroutines.
+// Every fanout consecutive hashes at one level of the tree are hashed by the
+// parent node at the next level, until a single root node remains. Once the
+// tree reaches depth levels, the root at the last level hashes all remaining
+// nodes. The leaves absorb the key, and every inner node outputs the full 64
+// bytes of the BLAKE2b state.
+//
+// The number of goroutines is GOMAXPROCS at the time of creation. They are
+// used when a single Write covers more than two leaves, and by ReadFrom and
+// ReadFromAt, which io.Copy uses automatically.
+type Blake2bTree struct {
+	params  blake2bParams
+	key     []byte
+	size    int
+	workers int
+
+	leaf     Blake2b
+	leafOpen bool
+	leafFill uint64
+	leaves   uint64
+
+	levels []blake2bTreeLevel
+}
+
+// blake2bTreeLevel holds the open node at one level of the tree above the
+// leaves.
+type blake2bTreeLevel struct {
+	node     Blake2b
+	offset   uint64
+	children uint64
+}
+
+// Blake2bTreeSum appends the size byte hash of data in tree hashing mode to
+// dst. The parameters are the same as for NewBlake2bTree.
+func Blake2bTreeSum(dst, data []byte, size uint32, key []byte, fanout, depth uint8, leafLength uint32) (sum []byte, err error) {
+	h, err := NewBlake2bTree(size, key, fanout, depth, leafLength)
+	if err != nil {
+		return
+	}
+
+	h.Write(data)
+	sum = h.Sum(dst)
+	h.Wipe()
+	return
+}
+
+// NewBlake2bTree creates a BLAKE2b hash in tree hashing mode with an output of
+// size bytes, keyed with the optional key. The fanout is the maximum number of
+// children of an inner node, or Blake2bTree_FanoutUnlimited, and must not be 1.
+// The depth is the maximum number of levels in the tree, including the leaves
+// and the root. The leafLength is the number of input bytes per leaf, and must
+// not be 0. Using a depth of 2 and a leafLength of a megabyte or more works
+// well for hashing large files.
+func NewBlake2bTree(size uint32, key []byte, fanout, depth uint8, leafLength uint32) (t *Blake2bTree, err error) {
+	switch {
+	case size < Blake2b_BytesMin || size > Blake2b_BytesMax:
+		err = ErrInvalidSize
+	case len(key) > Blake2b_KeyBytesMax:
+		err = ErrInvalidKeySize
+	case fanout == 1 || depth < Blake2bTree_DepthMin || leafLength == 0:
+		err = ErrInvalidTreeParams
+	}
+	if err != nil {
+		return
+	}
+
+	t = &Blake2bTree{
+		params: blake2bParams{
+			size:        uint8(size),
+			keyLen:      uint8(len(key)),
+			fanout:      fanout,
+			depth:       depth,
+			leafLength:  leafLength,
+			innerLength: Blake2bTree_InnerBytes,
+		},
+		size:    int(size),
+		workers: runtime.GOMAXPROCS(0),
+	}
+	if len(key) > 0 {
+		t.key = append([]byte(nil), key...)
+	}
+	return
+}
+
+// Reset restores the state to the one after creation, including the key.
+func (t *Blake2bTree) Reset() {
+	t.leaf.Wipe()
+	t.leafOpen = false
+	t.leafFill = 0
+	t.leaves = 0
+
+	for i := range t.levels {
+		t.levels[i].node.Wipe()
+	}
+	t.levels = t.levels[:0]
+}
+
+// Write implements io.Writer. Whole leaves within p are hashed concurrently.
+// The last leaf is kept open, as it has to be finalized as the last node.
+func (t *Blake2bTree) Write(p []byte) (n int, err error) {
+	n = len(p)
+	leafLength := uint64(t.params.leafLength)
+
+	for len(p) > 0 {
+		switch {
+		case t.leafOpen && t.leafFill == leafLength:
+			t.closeLeaf()
+
+		case t.leafOpen:
+			c := leafLength - t.leafFill
+			if c > uint64(len(p)) {
+				c = uint64(len(p))
+			}
+			t.leaf.Write(p[:c])
+			t.leafFill += c
+			p = p[c:]
+
+		case uint64(len(p)) > leafLength:
+			c := (uint64(len(p)) - 1) / leafLength
+			if c > blake2bTreeBatchLeaves {
+				c = blake2bTreeBatchLeaves
+			}
+			t.writeLeaves(p[:c*leafLength], int(c))
+			p = p[c*leafLength:]
+
+		default:
+			t.openLeaf()
+		}
+	}
+	return
+}
+
+// ReadFrom implements io.ReaderFrom. It reads r until io.EOF in chunks that
+// span multiple leaves, so the leaves are hashed concurrently.
+func (t *Blake2bTree) ReadFrom(r io.Reader) (n int64, err error) {
+	size := uint64(t.workers) * blake2bTreeBufferBytes
+	if leaves := uint64(t.workers+1) * uint64(t.params.leafLength); leaves > size && leaves <= blake2bTreeBufferMax {
+		size = leaves
+	}
+	buf := make([]byte, size)
+
+	for {
+		var c int
+		c, err = io.ReadFull(r, buf)
+		t.Write(buf[:c])
+		n += int64(c)
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = nil
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ReadFromAt hashes size bytes of r, starting at off. Every goroutine reads
+// and hashes whole leaves on its own, which is the fastest way to hash a file.
+// It returns io.ErrUnexpectedEOF if r holds fewer than size bytes after off.
+// On error, n is the number of bytes that were added to the hash.
+func (t *Blake2bTree) ReadFromAt(r io.ReaderAt, off, size int64) (n int64, err error) {
+	leafLength := int64(t.params.leafLength)
+
+	// fill up the open leaf, so the rest starts at a leaf boundary.
+	if t.leafOpen && t.leafFill < uint64(leafLength) {
+		head := leafLength - int64(t.leafFill)
+		if head > size {
+			head = size
+		}
+
+		n, err = t.readFull(io.NewSectionReader(r, off, head), head)
+		if err != nil {
+			return
+		}
+	}
+
+	for size-n > leafLength {
+		c := (size - n - 1) / leafLength
+		if limit := int64(blake2bTreeBatchLeaves); c > limit {
+			c = limit
+		}
+
+		err = t.readLeavesAt(r, off+n, int(c))
+		if err != nil {
+			return
+		}
+		n += c * leafLength
+	}
+
+	tail, err := t.readFull(io.NewSectionReader(r, off+n, size-n), size-n)
+	n += tail
+	return
+}
+
+// readFull hashes exactly size bytes from r, which is at most a leaf.
+func (t *Blake2bTree) readFull(r io.Reader, size int64) (n int64, err error) {
+	bufSize := int64(blake2bTreeBufferBytes)
+	if size < bufSize {
+		bufSize = size
+	}
+	buf := make([]byte, bufSize)
+
+	for n < size && err == nil {
+		c := bufSize
+		if size-n < c {
+			c = size - n
+		}
+
+		var m int
+		m, err = io.ReadFull(r, buf[:c])
+		t.Write(buf[:m])
+		n += int64(m)
+	}
+
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return
+}
+
+// Sum appends the hash to dst, without changing the state.
+func (t *Blake2bTree) Sum(dst []byte) (sum []byte) {
+	var out [Blake2b_BytesMax]byte
+
+	s := *t
+	s.levels = append([]blake2bTreeLevel(nil), t.levels...)
+	if !s.leafOpen {
+		s.openLeaf()
+	}
+
+	s.leaf.lastNode = true
+	s.leaf.checkSum(&out)
+	s.push(0, &out)
+
+	for i := 0; ; i++ {
+		level := &s.levels[i]
+		level.node.lastNode = true
+
+		if i == len(s.levels)-1 && level.offset == 0 {
+			sum = level.node.Sum(dst)
+			break
+		}
+
+		level.node.checkSum(&out)
+		s.push(i+1, &out)
+	}
+
+	s.Reset()
+	godium.Wipe(out[:])
+	return
+}
+
+// Wipe erases the key and the state.
+func (t *Blake2bTree) Wipe() {
+	t.Reset()
+	godium.Wipe(t.key)
+	t.key = nil
+}
+
+// openLeaf starts the next leaf.
+func (t *Blake2bTree) openLeaf() {
+	p := t.params
+	p.nodeOffset = t.leaves
+	t.leaves++
+
+	t.leaf = *newBlake2b(&p, t.key)
+	t.leafOpen = true
+	t.leafFill = 0
+}
+
+// closeLeaf finalizes the open leaf, which is not the last one, and passes its
+// hash up the tree.
+func (t *Blake2bTree) closeLeaf() {
+	var out [Blake2b_BytesMax]byte
+
+	t.leaf.checkSum(&out)
+	t.push(0, &out)
+	t.leafOpen = false
+}
+
+// writeLeaves hashes count whole leaves from p, none of which is the last one.
+func (t *Blake2bTree) writeLeaves(p []byte, count int) {
+	leafLength := int(t.params.leafLength)
+
+	t.hashLeaves(count, func(i int, leaf *Blake2b) (err error) {
+		leaf.Write(p[i*leafLength : (i+1)*leafLength])
+		return
+	})
+}
+
+// readLeavesAt hashes count whole leaves from r, starting at off. None of the
+// leaves is the last one.
+func (t *Blake2bTree) readLeavesAt(r io.ReaderAt, off int64, count int) (err error) {
+	leafLength := int64(t.params.leafLength)
+	bufSize := int64(blake2bTreeBufferBytes)
+	if leafLength < bufSize {
+		bufSize = leafLength
+	}
+
+	pool := sync.Pool{New: func() interface{} {
+		return make([]byte, bufSize)
+	}}
+
+	err = t.hashLeaves(count, func(i int, leaf *Blake2b) (err error) {
+		buf := pool.Get().([]byte)
+		defer pool.Put(buf)
+
+		section := io.NewSectionReader(r, off+int64(i)*leafLength, leafLength)
+		n, err := io.CopyBuffer(leaf, section, buf)
+		if err == nil && n < leafLength {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	})
+	return
+}
+
+// hashLeaves hashes count leaves, starting at the next leaf offset, using up
+// to t.workers goroutines. The write function writes the input of the i-th of
+// them. If all writes succeed, the hashes are passed up the tree in order.
+func (t *Blake2bTree) hashLeaves(count int, write func(i int, leaf *Blake2b) error) (err error) {
+	var next int64 = -1
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+
+	sums := make([][Blake2b_BytesMax]byte, count)
+	first := t.leaves
+
+	work := func() {
+		defer wg.Done()
+		for {
+			i := int(atomic.AddInt64(&next, 1))
+			if i >= count {
+				return
+			}
+
+			p := t.params
+			p.nodeOffset = first + uint64(i)
+			leaf := newBlake2b(&p, t.key)
+
+			if werr := write(i, leaf); werr != nil {
+				errOnce.Do(func() { err = werr })
+				return
+			}
+			leaf.checkSum(&sums[i])
+			leaf.Wipe()
+		}
+	}
+
+	workers := t.workers
+	if workers > count {
+		workers = count
+	}
+	wg.Add(workers)
+	for i := 1; i < workers; i++ {
+		go work()
+	}
+	work()
+	wg.Wait()
+
+	if err == nil {
+		if t.leafOpen {
+			t.closeLeaf()
+		}
+
+		t.leaves += uint64(count)
+		for i := range sums {
+			t.push(0, &sums[i])
+		}
+	}
+
+	for i := range sums {
+		godium.Wipe(sums[i][:])
+	}
+	return
+}
+
+// push writes the hash of a node to its parent at the given level above the
+// leaves. When the parent already has fanout children, it is finalized and
+// its own hash is pushed to the next level.
+func (t *Blake2bTree) push(level int, sum *[Blake2b_BytesMax]byte) {
+	var parent, out [Blake2b_BytesMax]byte
+
+	for {
+		if level == len(t.levels) {
+			t.levels = append(t.levels, blake2bTreeLevel{
+				node: *t.newInner(level, 0),
+			})
+		}
+
+		l := &t.levels[level]
+		if !t.full(level, l.children) {
+			l.node.Write(sum[:])
+			l.children++
+			return
+		}
+
+		l.node.checkSum(&parent)
+		l.offset++
+		l.node = *t.newInner(level, l.offset)
+		l.node.Write(sum[:])
+		l.children = 1
+
+		out = parent
+		sum = &out
+		level++
+	}
+}
+
+// full returns whether a node at the given level cannot take more children.
+// The node at the deepest level allowed is the root, which is never full.
+func (t *Blake2bTree) full(level int, children uint64) bool {
+	return t.params.fanout != Blake2bTree_FanoutUnlimited &&
+		children == uint64(t.params.fanout) &&
+		level+2 < int(t.params.depth)
+}
+
+// newInner creates the inner node at offset of the given level above the
+// leaves.
+func (t *Blake2bTree) newInner(level int, offset uint64) (b *Blake2b) {
+	p := t.params
+	p.nodeOffset = offset
+	p.nodeDepth = uint8(level + 1)
+
+	b = newBlake2bInner(&p)
+	return
+}
+
+func (t *Blake2bTree) Size() int        { return t.size }
+func (t *Blake2bTree) BlockSize() int   { return Blake2b_BlockBytes }
+func (t *Blake2bTree) BytesMin() int    { return Blake2b_BytesMin }
+func (t *Blake2bTree) BytesMax() int    { return Blake2b_BytesMax }
+func (t *Blake2bTree) Bytes() int       { return t.size }
+func (t *Blake2bTree) KeyBytesMin() int { return Blake2b_KeyBytesMin }
+func (t *Blake2bTree) KeyBytesMax() int { return Blake2b_KeyBytesMax }
+func (t *Blake2bTree) KeyBytes() int    { return Blake2b_KeyBytes }
@@ -0,0 +1,177 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+)
+
+// blake2bTreeKAT holds tree hashing known answers for the input bytes
+// i % 251 for i in 0, ..., length-1. Keyed entries use the key bytes 0x00,
+// ..., 0x3f. The first entry matches the tree hashing example of Python's
+// hashlib.
+var blake2bTreeKAT = []struct {
+	length     int
+	size       uint32
+	keyed      bool
+	fanout     uint8
+	depth      uint8
+	leafLength uint32
+	expect     string
+}{
+	{8000, 64, false, 2, 2, 4096,
+		"8726c6d51f681e529712633e0de978bb8a971e09570a8f4c5dc0588c0493225e" +
+			"a4e5568ad3c6d81b1a5ae7ab5a58e63a06c2d145976bea97cc04411fc7ae98d4"},
+	{1500, 64, false, 2, 255, 128,
+		"5d91faeb592dcfbed12d5d0ee3b62c72510b631ce12811752e091e3501fe17b5" +
+			"48db5c0f9447323755ec1e6d86ab44b4d91f5c80f33977a50a65b0bd5d4bb786"},
+	{1000, 32, false, 0, 2, 100,
+		"0b2017bfa24b2c9a77c3fca05ae3463bed1b4e8d316265281b4ca3af4e5d09c1"},
+	{5000, 64, true, 4, 3, 64,
+		"7d6e5a2d71d6164cb668193e7ea98f1f07f99e7e2037c5fdb558668e2defd345" +
+			"f4786c940bf133f09f825e1dcb92b4107875926e4964ec1813eb588683677cd9"},
+	{0, 64, true, 2, 255, 64,
+		"c6ef415b8cab0a567a57ab2f81a41bf004e2271ee20222865b422e7751232c32" +
+			"12f58686d4d7a51348312a8b09334050938311ca16bc58def172d5b2b73b620f"},
+	{64, 64, false, 2, 255, 64,
+		"0b07798e977530cc3865041f69691ef6aea15d4e515f489bd8279fda327a1a81" +
+			"6301c93eb3e7279593b7d4f0b9bb80e901bbe5a21d2ed7a62a2b522a7954e512"},
+	{300000, 64, true, 0, 2, 1024,
+		"552e4e3fe26313fd29a47ac76fdf21be1643fd21b46ae50f762a9a46ab72aff0" +
+			"d4d8fdd95a4b95b19409c651707f9b6c3e4872f3d212ad38f905f22fe08848e9"},
+	{300000, 48, false, 3, 255, 4096,
+		"8ea3ed7dc18b2c5c2e103a86e3f44210f6cecff3fb8ddf4a4a44caa5559579a3" +
+			"5def6df73e6ebb051cb7cc6cb5bdc1a4"},
+}
+
+func TestBlake2bTreeKAT(t *testing.T) {
+	for _, test := range blake2bTreeKAT {
+		var key []byte
+		if test.keyed {
+			key = testPattern(64)
+		}
+		expect, _ := hex.DecodeString(test.expect)
+		data := testPattern(test.length)
+
+		newTree := func() *Blake2bTree {
+			h, err := NewBlake2bTree(test.size, key, test.fanout, test.depth, test.leafLength)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return h
+		}
+
+		got, _ := Blake2bTreeSum(nil, data, test.size, key, test.fanout, test.depth, test.leafLength)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", test.length, expect, got)
+		}
+
+		// chunks that do not line up with the leaves
+		h := newTree()
+		for p := data; len(p) > 0; {
+			c := 999
+			if c > len(p) {
+				c = len(p)
+			}
+			h.Write(p[:c])
+			p = p[c:]
+		}
+		if got = h.Sum(nil); !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed by chunks", test.length, expect, got)
+		}
+
+		h.Reset()
+		if _, err := io.Copy(h, bytes.NewBuffer(data)); err != nil {
+			t.Fatal(err)
+		}
+		if got = h.Sum(nil); !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed by ReadFrom", test.length, expect, got)
+		}
+
+		// ReadFromAt after a write that leaves a partial leaf open
+		head := len(data) / 3
+		h.Reset()
+		h.Write(data[:head])
+		n, err := h.ReadFromAt(bytes.NewReader(data), int64(head), int64(len(data)-head))
+		if err != nil || n != int64(len(data)-head) {
+			t.Fatal(n, err)
+		}
+		if got = h.Sum(nil); !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed by ReadFromAt", test.length, expect, got)
+		}
+	}
+}
+
+func TestBlake2bTreeShortReaderAt(t *testing.T) {
+	data := testPattern(10000)
+
+	h, _ := NewBlake2bTree(64, nil, 2, 255, 128)
+	n, err := h.ReadFromAt(bytes.NewReader(data), 0, int64(len(data)+1))
+	if err != io.ErrUnexpectedEOF {
+		t.Error("expected io.ErrUnexpectedEOF", n, err)
+	}
+}
+
+func TestBlake2bTreeParams(t *testing.T) {
+	for _, test := range []struct {
+		fanout, depth uint8
+		leafLength    uint32
+	}{
+		{1, 2, 64},
+		{2, 1, 64},
+		{2, 2, 0},
+	} {
+		if _, err := NewBlake2bTree(64, nil, test.fanout, test.depth, test.leafLength); err != ErrInvalidTreeParams {
+			t.Error("invalid parameters were accepted", test)
+		}
+	}
+}
+
+// hashWriter is the part of hash.Hash used by benchmarkHash.
+type hashWriter interface {
+	io.Writer
+	Sum(dst []byte) []byte
+}
+
+// benchmarkHash hashes size bytes per iteration with a new hash. Run with
+// -cpu 1,2,4,8 to see the scaling with GOMAXPROCS.
+func benchmarkHash(b *testing.B, size int, newHash func() hashWriter) {
+	data := testPattern(size)
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		h := newHash()
+		h.Write(data)
+		h.Sum(nil)
+	}
+}
+
+func BenchmarkBlake2b1M(b *testing.B) {
+	benchmarkHash(b, 1<<20, func() hashWriter { return NewBlake2b512(nil) })
+}
+
+func BenchmarkBlake2bTree16M(b *testing.B) {
+	benchmarkHash(b, 16<<20, func() hashWriter {
+		h, _ := NewBlake2bTree(64, nil, Blake2bTree_FanoutUnlimited, 2, 1<<20)
+		return h
+	})
+}
+
+func BenchmarkBlake2bTreeReaderAt16M(b *testing.B) {
+	data := bytes.NewReader(testPattern(16 << 20))
+	b.SetBytes(16 << 20)
+
+	for i := 0; i < b.N; i++ {
+		h, _ := NewBlake2bTree(64, nil, Blake2bTree_FanoutUnlimited, 2, 1<<20)
+		h.ReadFromAt(data, 0, 16<<20)
+		h.Sum(nil)
+	}
+}
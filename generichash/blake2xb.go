@@ -0,0 +1,210 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"encoding/binary"
+	"io"
+
+	"go.artemisc.eu/godium"
+)
+
+const (
+	// Blake2xb_BytesUnknown can be used as the size of a Blake2xb to indicate
+	// that the length of the output is not known in advance. At most
+	// Blake2xb_BytesUnknownMax bytes can then be read.
+	Blake2xb_BytesUnknown    = 0
+	Blake2xb_BytesUnknownMax = (1 << 32) * Blake2b_BytesMax
+	Blake2xb_BytesMax        = 1<<32 - 2
+	Blake2xb_KeyBytesMax     = Blake2b_KeyBytesMax
+
+	// blake2xbLengthUnknown is the XOF length stored in the parameter block
+	// when the output length is not known in advance.
+	blake2xbLengthUnknown = 1<<32 - 1
+)
+
+// Blake2xb implements the godium.XOF interface for BLAKE2Xb, as specified in
+// "BLAKE2X" by Aumasson, Neves, Wilcox-O'Hearn and Winnerlein. The input is
+// hashed into a root hash, which is then expanded by hashing it once for every
+// 64 bytes of output.
+type Blake2xb struct {
+	root      *Blake2b
+	params    blake2bParams
+	length    uint32
+	remaining uint64
+	rootHash  [Blake2b_BytesMax]byte
+	block     [Blake2b_BytesMax]byte
+	offset    int
+	node      uint32
+	reading   bool
+}
+
+// Blake2xbSum appends size bytes of BLAKE2Xb output for data, keyed with the
+// optional key, to dst.
+func Blake2xbSum(dst, data []byte, size uint32, key []byte) (sum []byte, err error) {
+	x, err := NewBlake2xb(size, key)
+	if err != nil {
+		return
+	}
+
+	x.Write(data)
+	sum = append(dst, make([]byte, size)...)
+	_, err = io.ReadFull(x, sum[len(dst):])
+	x.Wipe()
+	return
+}
+
+// NewBlake2xb creates a BLAKE2Xb XOF that produces size bytes of output,
+// keyed with the optional key. The size can be Blake2xb_BytesUnknown, or at
+// most Blake2xb_BytesMax. Different sizes produce unrelated output.
+func NewBlake2xb(size uint32, key []byte) (x *Blake2xb, err error) {
+	x, err = NewBlake2xbSaltPersonal(size, key, nil, nil)
+	return
+}
+
+// NewBlake2xbSaltPersonal creates a BLAKE2Xb XOF like NewBlake2xb, that also
+// uses the optional personal and salt. Both can be at most 16 bytes, shorter
+// values are padded with zeros.
+func NewBlake2xbSaltPersonal(size uint32, key, personal, salt []byte) (x *Blake2xb, err error) {
+	switch {
+	case size > Blake2xb_BytesMax:
+		err = ErrInvalidSize
+	case len(key) > Blake2xb_KeyBytesMax:
+		err = ErrInvalidKeySize
+	case len(personal) > Blake2b_PersonalBytes:
+		err = ErrInvalidPersonalSize
+	case len(salt) > Blake2b_SaltBytes:
+		err = ErrInvalidSaltSize
+	}
+	if err != nil {
+		return
+	}
+
+	x = &Blake2xb{
+		length: size,
+	}
+	if size == Blake2xb_BytesUnknown {
+		x.length = blake2xbLengthUnknown
+	}
+
+	copy(x.params.salt[:], salt)
+	copy(x.params.personal[:], personal)
+
+	root := x.params
+	root.size = Blake2b_BytesMax
+	root.fanout = 1
+	root.depth = 1
+	root.nodeOffset = uint64(x.length) << 32
+	x.root = newBlake2b(&root, key)
+
+	x.params.leafLength = Blake2b_BytesMax
+	x.params.innerLength = Blake2b_BytesMax
+
+	x.Reset()
+	return
+}
+
+// Write absorbs more data into the root hash. It panics if called after Read.
+func (x *Blake2xb) Write(p []byte) (n int, err error) {
+	if x.reading {
+		panic("generichash: write to BLAKE2Xb after read")
+	}
+
+	n, err = x.root.Write(p)
+	return
+}
+
+// Read reads more output from the XOF. It returns io.EOF once all output has
+// been read.
+func (x *Blake2xb) Read(p []byte) (n int, err error) {
+	if !x.reading {
+		x.root.checkSum(&x.rootHash)
+		x.reading = true
+	}
+
+	if x.remaining == 0 {
+		err = io.EOF
+		return
+	}
+
+	if uint64(len(p)) > x.remaining {
+		p = p[:x.remaining]
+	}
+
+	for len(p) > 0 {
+		if x.offset == len(x.block) {
+			x.nextBlock()
+		}
+
+		c := copy(p, x.block[x.offset:])
+		x.offset += c
+		x.remaining -= uint64(c)
+		n += c
+		p = p[c:]
+	}
+	return
+}
+
+// nextBlock computes the next output node from the root hash.
+func (x *Blake2xb) nextBlock() {
+	var buf [Blake2b_BlockBytes]byte
+	var t [2]uint64
+
+	size := uint64(Blake2b_BytesMax)
+	if x.length != blake2xbLengthUnknown && x.remaining < size {
+		size = x.remaining
+	}
+
+	p := x.params
+	p.size = uint8(size)
+	p.nodeOffset = uint64(x.node) | uint64(x.length)<<32
+	x.node++
+
+	h := p.iv()
+	copy(buf[:], x.rootHash[:])
+
+	// the root hash is a single, partial block; rewind the counter that
+	// blake2bBlocks increments by a full block, like checkSum does.
+	t[0] -= Blake2b_BlockBytes - Blake2b_BytesMax
+	t[1]--
+	blake2bBlocks(&h, &t, ^uint64(0), 0, buf[:])
+
+	for i, v := range h {
+		binary.LittleEndian.PutUint64(x.block[i*8:], v)
+	}
+
+	// only the first size bytes are output, shift them to the end so that
+	// offset marks the unused part of the block.
+	x.offset = len(x.block) - int(size)
+	copy(x.block[x.offset:], x.block[:size])
+}
+
+// Reset restores the XOF to the state after creation, including the key.
+func (x *Blake2xb) Reset() {
+	x.root.Reset()
+
+	x.remaining = uint64(x.length)
+	if x.length == blake2xbLengthUnknown {
+		x.remaining = Blake2xb_BytesUnknownMax
+	}
+
+	x.offset = len(x.block)
+	x.node = 0
+	x.reading = false
+}
+
+// Wipe erases the key, the root hash and the buffered output.
+func (x *Blake2xb) Wipe() {
+	x.root.Wipe()
+	godium.Wipe(x.rootHash[:])
+	godium.Wipe(x.block[:])
+	x.remaining = 0
+}
+
+func (x *Blake2xb) KeyBytesMax() int   { return Blake2xb_KeyBytesMax }
+func (x *Blake2xb) PersonalBytes() int { return Blake2b_PersonalBytes }
+func (x *Blake2xb) SaltBytes() int     { return Blake2b_SaltBytes }
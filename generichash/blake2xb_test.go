@@ -0,0 +1,186 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+var _ godium.XOF = (*Blake2xb)(nil)
+
+// blake2xbKAT holds a selection of the keyed BLAKE2Xb known answers from the
+// BLAKE2 reference test vectors. Every entry is the output of the given size
+// for the bytes 0x00, 0x01, ..., 0xff, keyed with the bytes 0x00, ..., 0x3f.
+var blake2xbKAT = []struct {
+	size   uint32
+	expect string
+}{
+	{1, "64"},
+	{2, "f457"},
+	{16, "f5ebf68e7ebed6ad445ffc0c47e82650"},
+	{32, "29f6bb55de7f8868e053176c878c9fe6c2055c4c5413b51ab0386c277fdbac75"},
+	{63, "e101f43179d8e8546e5ce6a96d7556b7e6b9d4a7d00e7aade5579d085d527ce3" +
+		"4a9329551ebcaf6ba946949bbe38e30a62ae344c1950b4bde55306b3bac432"},
+	{64, "4324561d76c370ef35ac36a4adf8f3773a50d86504bd284f71f7ce9e2bc4c1f1" +
+		"d34a7fb2d67561d101955d448b67577eb30dfee96a95c7f921ef53e20be8bc44"},
+	{65, "78f0ed6e220b3da3cc9381563b2f72c8dc830cb0f39a48c6ae479a6a78dcfa94" +
+		"002631dec467e9e9b47cc8f0887eb680e340aec3ec009d4a33d241533c76c8ca" +
+		"8c"},
+	{100, "cb859b35dc70e264efaad2a809fea1e71cd4a3f924be3b5a13f8687a1166b538" +
+		"c40b2ad51d5c3e47b0de482497382673140f547068ff0b3b0fb7501209e1bf36" +
+		"082509ae85f60bb98fd02ac50d883a1a8daa704952d83c1f6da60c9624bc7c99" +
+		"912930bf"},
+	{127, "f7f4d328ba108b7b1de4443e889a985ed52f485f3ca4e0c246aa5526590cbed3" +
+		"44e9f4fe53e4eea0e761c82324649206ca8c2b45152157d4115e68c818644b03" +
+		"b65bb47ad79f94d37cb03c1d953b74c2b8adfa0e1c418bda9c518ddcd7050e0f" +
+		"149044740a2b16479413b63fc13c36144f80c73687513dca761ba8642a8ae0"},
+	{128, "2d7dc80c19a1d12d5fe3963569547a5d1d3e821e6f06c5d5e2c09401f946c9f7" +
+		"e13cd019f2f9a878b62dd850453b6294b99ccaa068e542993524b0f63832d48e" +
+		"865be31e8ec1ee103c718340c904b32efb69170b67f038d50a3252794b1b4076" +
+		"c0620621ab3d91215d55ffea99f23d54e161a90d8d4902fda5931d9f6a27146a"},
+	{129, "77dff4c7ad30c954338c4b23639dae4b275086cbe654d401a2343528065e4c9f" +
+		"1f2eca22aa025d49ca823e76fdbb35df78b1e5075ff2c82b680bca385c6d57f7" +
+		"ea7d1030bb392527b25dd73e9eeff97bea397cf3b9dda0c817a9c870ed12c006" +
+		"cc054968c64000e0da874e9b7d7d621b0679866912243ea096c7b38a1344e98f" +
+		"74"},
+	{255, "6e85c2f8e1fdc3aaeb969da1258cb504bbf0070cd03d23b3fb5ee08feea5ee2e" +
+		"0ee1c71a5d0f4f701b351f4e4b4d74cb1e2ae6184814f77b62d2f08134b7236e" +
+		"bf6b67d8a6c9f01b4248b30667c555f5d8646dbfe291151b23c9c9857e33a4d5" +
+		"c847be29a5ee7b402e03bac02d1a4319acc0dd8f25e9c7a266f5e5c896cc11b5" +
+		"b238df96a0963ae806cb277abc515c298a3e61a3036b177acf87a56ca4478c4c" +
+		"6d0d468913de602ec891318bbaf52c97a77c35c5b7d164816cf24e4c4b0b5f45" +
+		"853882f716d61eb947a45ce2efa78f1c70a918512af1ad536cbe6148083385b3" +
+		"4e207f5f690d7a954021e4b5f4258a385fd8a87809a481f34202af4caccb82"},
+	{256, "1e9b2c454e9de3a2d723d850331037dbf54133dbe27488ff757dd255833a27d8" +
+		"eb8a128ad12d0978b6884e25737086a704fb289aaaccf930d5b582ab4df1f55f" +
+		"0c429b6875edec3fe45464fa74164be056a55e243c4222c586bec5b18f39036a" +
+		"a903d98180f24f83d09a454dfa1e03a60e6a3ba4613e99c35f874d790174ee48" +
+		"a557f4f021ade4d1b278d7997ef094569b37b3db0505951e9ee8400adaea275c" +
+		"6db51b325ee730c69df97745b556ae41cd98741e28aa3a49544541eeb3da1b1e" +
+		"8fa4e8e9100d66dd0c7f5e2c271b1ecc077de79c462b9fe4c273543ecd82a5be" +
+		"a63c5acc01eca5fb780c7d7c8c9fe208ae8bd50cad1769693d92c6c8649d20d8"},
+}
+
+// blake2xbUnknownKAT holds the first 64 bytes of output for the same input and
+// key as blake2xbKAT, when the output length is unknown.
+const blake2xbUnknownKAT = "3dbba8516da76bf7330055c66ea36cf1005e92714262b24d9710f51d9e126406" +
+	"e1bcd6497059f9331f1091c3634b695428d475ed432f987040575520a1c29f5e"
+
+func blake2xbTestInput() (key, data []byte) {
+	key = make([]byte, 64)
+	data = make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	copy(key, data)
+	return
+}
+
+func TestBlake2xbKAT(t *testing.T) {
+	key, data := blake2xbTestInput()
+
+	for _, test := range blake2xbKAT {
+		expect, _ := hex.DecodeString(test.expect)
+
+		got, err := Blake2xbSum(nil, data, test.size, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", test.size, expect, got)
+		}
+	}
+}
+
+func TestBlake2xbIncremental(t *testing.T) {
+	key, data := blake2xbTestInput()
+	test := blake2xbKAT[len(blake2xbKAT)-1]
+	expect, _ := hex.DecodeString(test.expect)
+
+	x, err := NewBlake2xb(test.size, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range data {
+		x.Write(data[i : i+1])
+	}
+
+	got := make([]byte, 0, len(expect))
+	var b [1]byte
+	for {
+		n, err := x.Read(b[:])
+		if err == io.EOF {
+			break
+		}
+		got = append(got, b[:n]...)
+	}
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	x.Reset()
+	x.Write(data)
+	got, err = ioutil.ReadAll(x)
+	if err != nil || !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed after reset", expect, got)
+	}
+}
+
+func TestBlake2xbUnknownLength(t *testing.T) {
+	key, data := blake2xbTestInput()
+	expect, _ := hex.DecodeString(blake2xbUnknownKAT)
+
+	x, err := NewBlake2xb(Blake2xb_BytesUnknown, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x.Write(data)
+	got := make([]byte, len(expect))
+	if _, err = io.ReadFull(x, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestBlake2xbSaltPersonal(t *testing.T) {
+	key, data := blake2xbTestInput()
+
+	a, _ := NewBlake2xbSaltPersonal(64, key, nil, nil)
+	b, _ := NewBlake2xbSaltPersonal(64, key, []byte("personal"), nil)
+	c, _ := NewBlake2xbSaltPersonal(64, key, nil, []byte("salt"))
+
+	var outs [3][64]byte
+	for i, x := range []*Blake2xb{a, b, c} {
+		x.Write(data)
+		io.ReadFull(x, outs[i][:])
+	}
+
+	if outs[0] == outs[1] || outs[0] == outs[2] || outs[1] == outs[2] {
+		t.Error("salt and personal did not change the output")
+	}
+
+	for _, err := range []error{
+		func() (err error) { _, err = NewBlake2xb(Blake2xb_BytesMax+1, nil); return }(),
+		func() (err error) { _, err = NewBlake2xb(64, make([]byte, 65)); return }(),
+		func() (err error) { _, err = NewBlake2xbSaltPersonal(64, nil, make([]byte, 17), nil); return }(),
+		func() (err error) { _, err = NewBlake2xbSaltPersonal(64, nil, nil, make([]byte, 17)); return }(),
+	} {
+		if err == nil {
+			t.Error("invalid parameters were accepted")
+		}
+	}
+}
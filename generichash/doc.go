@@ -10,6 +10,8 @@
 package generichash // import "go.artemisc.eu/godium/generichash"
 
 import (
+	"errors"
+
 	"go.artemisc.eu/godium"
 )
 
@@ -23,9 +25,32 @@ const (
 	KeyBytes    = Blake2b_KeyBytes
 )
 
+var (
+	// ErrInvalidSize is returned when the requested output size is outside of
+	// the range from BytesMin to BytesMax.
+	ErrInvalidSize = errors.New("generichash: invalid output size")
+
+	// ErrInvalidKeySize is returned when the key is longer than KeyBytesMax.
+	ErrInvalidKeySize = errors.New("generichash: invalid key size")
+
+	// ErrInvalidSaltSize is returned when the salt is too long.
+	ErrInvalidSaltSize = errors.New("generichash: invalid salt size")
+
+	// ErrInvalidPersonalSize is returned when the personalization is too long.
+	ErrInvalidPersonalSize = errors.New("generichash: invalid personal size")
+
+	// ErrInvalidTreeParams is returned when the fanout, depth or leaf length of
+	// a tree hashing mode are not supported.
+	ErrInvalidTreeParams = errors.New("generichash: invalid tree parameters")
+
+	// ErrInvalidLabel is returned when a tuple hash is created without a
+	// domain separation label.
+	ErrInvalidLabel = errors.New("generichash: missing label")
+)
+
 // New
-func New(size uint32, key []byte) (gh godium.GenericHash) {
-	gh = NewBlake2b(size, key)
+func New(size uint32, key []byte) (gh godium.GenericHash, err error) {
+	gh, err = NewBlake2b(size, key)
 	return
 }
 
@@ -56,3 +81,16 @@ func Sum512(data []byte) (sum [64]byte) {
 	b.Sum(sum[:0])
 	return
 }
+
+// TupleSum appends the size byte hash of the tuple of elements under the label
+// to dst, see Blake2bTupleSum.
+func TupleSum(dst []byte, size uint32, key []byte, label string, elements ...[]byte) (sum []byte, err error) {
+	sum, err = Blake2bTupleSum(dst, size, key, label, elements...)
+	return
+}
+
+// Sum
+func Sum(dst, data []byte, size uint32, key []byte) (sum []byte, err error) {
+	sum, err = Blake2bSum(dst, data, size, key)
+	return
+}
@@ -0,0 +1,119 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"encoding/binary"
+)
+
+// blake2bTuplePersonal separates tuple hashes from every other use of
+// BLAKE2b with the same key.
+var blake2bTuplePersonal = []byte("godium-tuplehash")
+
+// Blake2bTuple hashes a tuple of byte strings, similar to TupleHash from NIST
+// SP 800-185. The label and every element are prefixed with their length as a
+// 64 bit little endian integer, so different tuples never produce the same
+// input to BLAKE2b, and tuples hashed with different labels are unrelated.
+// The output is suitable as key material, like the master key of a KDF.
+type Blake2bTuple struct {
+	b     Blake2b
+	start Blake2b
+}
+
+// Blake2bTupleSum appends the size byte tuple hash of elements under the label
+// to dst, keyed with the optional key. The limits on size and key are the
+// same as for NewBlake2b, and the label must not be empty. It does not
+// allocate if dst has enough capacity.
+func Blake2bTupleSum(dst []byte, size uint32, key []byte, label string, elements ...[]byte) (sum []byte, err error) {
+	var t Blake2bTuple
+
+	err = t.init(size, key, label)
+	if err != nil {
+		return
+	}
+
+	for _, e := range elements {
+		t.Add(e)
+	}
+	sum = t.Sum(dst)
+	t.Wipe()
+	return
+}
+
+// NewBlake2bTuple creates a tuple hash with an output of size bytes under the
+// label, keyed with the optional key. Elements are added with Add. The limits
+// on size and key are the same as for NewBlake2b, and the label must not be
+// empty.
+func NewBlake2bTuple(size uint32, key []byte, label string) (t *Blake2bTuple, err error) {
+	t = new(Blake2bTuple)
+
+	err = t.init(size, key, label)
+	if err != nil {
+		t = nil
+	}
+	return
+}
+
+// init validates the parameters and absorbs the label.
+func (t *Blake2bTuple) init(size uint32, key []byte, label string) (err error) {
+	if len(label) == 0 {
+		err = ErrInvalidLabel
+		return
+	}
+
+	p, err := blake2bSaltPersonalParams(size, key, blake2bTuplePersonal, nil)
+	if err != nil {
+		return
+	}
+
+	t.b.init(&p, key)
+	t.writeLength(len(label))
+
+	// copy the label in blocks, as converting it could allocate.
+	var buf [Blake2b_BlockBytes]byte
+	for len(label) > 0 {
+		n := copy(buf[:], label)
+		t.b.Write(buf[:n])
+		label = label[n:]
+	}
+
+	t.start = t.b
+	return
+}
+
+// Add appends an element to the tuple.
+func (t *Blake2bTuple) Add(element []byte) {
+	t.writeLength(len(element))
+	t.b.Write(element)
+}
+
+// writeLength writes the length prefix of a label or element.
+func (t *Blake2bTuple) writeLength(n int) {
+	var buf [8]byte
+
+	binary.LittleEndian.PutUint64(buf[:], uint64(n))
+	t.b.Write(buf[:])
+}
+
+// Sum appends the hash of the tuple to dst, without changing the state.
+func (t *Blake2bTuple) Sum(dst []byte) (sum []byte) {
+	sum = t.b.Sum(dst)
+	return
+}
+
+// Reset removes all elements, but keeps the key and label.
+func (t *Blake2bTuple) Reset() {
+	t.b = t.start
+}
+
+// Wipe erases the key, the label and the state.
+func (t *Blake2bTuple) Wipe() {
+	t.b.Wipe()
+	t.start.Wipe()
+}
+
+func (t *Blake2bTuple) Size() int { return t.b.size }
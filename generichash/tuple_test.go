@@ -0,0 +1,99 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package generichash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func repeat(s string, n int) []byte {
+	return bytes.Repeat([]byte(s), n)
+}
+
+var blake2bTupleTests = []struct {
+	size     uint32
+	key      []byte
+	label    string
+	elements [][]byte
+	expect   string
+}{
+	{32, nil, "kx session", [][]byte{repeat("q", 32), repeat("client", 5), repeat("server", 5)},
+		"69474a668a0e890ccbb4bbd0b39a7c800f96388f14eceaed6d2ac3384db573c0"},
+	{64, testPattern(32), "kx session", [][]byte{repeat("q", 32), repeat("client", 5), repeat("server", 5)},
+		"5f36c48f27627f6b4139e2cf540538beef51d18efabc384ace5d80f9bb6f5dd5" +
+			"f1fc3a313a098a13e022ea3ca395ede0b4dc5fae7bc6590ed4ff1db04afd67fb"},
+	{32, nil, "empty elements", [][]byte{{}, {}},
+		"8d48d7da1cc35bba2c561ce8c3a012d71238686cf7278434b3b36be470a1f751"},
+	{32, nil, string(repeat("L", 200)), [][]byte{repeat("x", 300)},
+		"ff82e4143ff88b879070a1cf236f3fe8c29f5e68a584413407f366076ecf8c1e"},
+}
+
+func TestBlake2bTuple(t *testing.T) {
+	for _, test := range blake2bTupleTests {
+		expect, _ := hex.DecodeString(test.expect)
+
+		got, err := Blake2bTupleSum(nil, test.size, test.key, test.label, test.elements...)
+		if err != nil || !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", expect, got, err)
+		}
+
+		h, err := NewBlake2bTuple(test.size, test.key, test.label)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Add([]byte("discarded by reset"))
+		h.Reset()
+		for _, e := range test.elements {
+			h.Add(e)
+		}
+		if got = h.Sum(nil); !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed after reset", expect, got)
+		}
+	}
+}
+
+func TestBlake2bTupleUnambiguous(t *testing.T) {
+	sums := make(map[string]bool)
+	for _, tuple := range [][][]byte{
+		{[]byte("ab"), []byte("c")},
+		{[]byte("a"), []byte("bc")},
+		{[]byte("abc")},
+		{[]byte("abc"), {}},
+		{{}, []byte("abc")},
+	} {
+		sum, _ := Blake2bTupleSum(nil, 32, nil, "label", tuple...)
+		if sums[string(sum)] {
+			t.Error("different tuples produced the same hash", tuple)
+		}
+		sums[string(sum)] = true
+	}
+
+	a, _ := Blake2bTupleSum(nil, 32, nil, "label a", []byte("x"))
+	b, _ := Blake2bTupleSum(nil, 32, nil, "label b", []byte("x"))
+	if bytes.Equal(a, b) {
+		t.Error("different labels produced the same hash")
+	}
+}
+
+func TestBlake2bTupleParams(t *testing.T) {
+	if _, err := NewBlake2bTuple(32, nil, ""); err != ErrInvalidLabel {
+		t.Error("expected result did not match computed", ErrInvalidLabel, err)
+	}
+	if _, err := Blake2bTupleSum(nil, 8, nil, "label"); err != ErrInvalidSize {
+		t.Error("expected result did not match computed", ErrInvalidSize, err)
+	}
+
+	dst := make([]byte, 0, 32)
+	allocs := testing.AllocsPerRun(10, func() {
+		Blake2bTupleSum(dst, 32, nil, "label", dst, dst)
+	})
+	if allocs != 0 {
+		t.Error("Blake2bTupleSum allocated", allocs)
+	}
+}
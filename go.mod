@@ -3,10 +3,6 @@ module go.artemisc.eu/godium
 go 1.13
 
 require (
-	git.schwanenlied.me/yawning/chacha20.git v0.0.0-20170904085104-e3b1f968fc63
-	github.com/Yawning/chacha20 v0.0.0-20170904085104-e3b1f968fc63
-	github.com/Yawning/poly1305 v0.0.0-20151107134637-dfc796fe731c
 	github.com/dchest/siphash v1.2.2
-	github.com/minio/blake2b-simd v0.0.0-20160723061019-3f5f724cb5b1
 	golang.org/x/crypto v0.0.0-20201012173705-84dcc777aaee
 )
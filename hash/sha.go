@@ -12,23 +12,35 @@ package hash // import "go.artemisc.eu/godium/hash"
 import (
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding"
+	"errors"
 	"hash"
 
 	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
 )
 
 const (
 	Sha256_Bytes = 32
 
+	Sha384_Bytes = 48
+
 	Sha512_Bytes = 64
 
+	Sha512_256_Bytes = 32
+
 	Primitive = "sha512"
 	Bytes     = Sha512_Bytes
 )
 
+// ErrStateUnsupported is returned by MarshalBinary when the underlying hash
+// implementation cannot serialize its state.
+var ErrStateUnsupported = errors.New("hash: state serialization not supported")
+
 // shaImpl
 type shaImpl struct {
 	hash.Hash
+	primitive string
 }
 
 // New
@@ -40,15 +52,36 @@ func New() (h godium.Hash) {
 // NewSha256
 func NewSha256() (h godium.Hash) {
 	h = &shaImpl{
-		Hash: sha256.New(),
+		Hash:      sha256.New(),
+		primitive: "sha256",
 	}
 	return
 }
 
-// NewSha256
+// NewSha512
 func NewSha512() (h godium.Hash) {
 	h = &shaImpl{
-		Hash: sha512.New(),
+		Hash:      sha512.New(),
+		primitive: "sha512",
+	}
+	return
+}
+
+// NewSha384
+func NewSha384() (h godium.Hash) {
+	h = &shaImpl{
+		Hash:      sha512.New384(),
+		primitive: "sha384",
+	}
+	return
+}
+
+// NewSha512_256 creates a SHA-512/256 hash, which is SHA-512 with a different
+// initial value, truncated to 32 bytes.
+func NewSha512_256() (h godium.Hash) {
+	h = &shaImpl{
+		Hash:      sha512.New512_256(),
+		primitive: "sha512256",
 	}
 	return
 }
@@ -66,6 +99,13 @@ func SumSha256(dst, data []byte) (sum []byte) {
 	return
 }
 
+// SumSha384
+func SumSha384(dst, data []byte) (sum []byte) {
+	sha := sha512.Sum384(data)
+	sum = append(dst, sha[:]...)
+	return
+}
+
 // SumSha512
 func SumSha512(dst, data []byte) (sum []byte) {
 	sha := sha512.Sum512(data)
@@ -73,4 +113,49 @@ func SumSha512(dst, data []byte) (sum []byte) {
 	return
 }
 
+// SumSha512_256
+func SumSha512_256(dst, data []byte) (sum []byte) {
+	sha := sha512.Sum512_256(data)
+	sum = append(dst, sha[:]...)
+	return
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, the state can be
+// restored into a hash of the same primitive to continue hashing.
+func (s *shaImpl) MarshalBinary() (state []byte, err error) {
+	m, ok := s.Hash.(encoding.BinaryMarshaler)
+	if !ok {
+		err = ErrStateUnsupported
+		return
+	}
+
+	payload, err := m.MarshalBinary()
+	if err != nil {
+		return
+	}
+
+	state = internal.MarshalState(s.primitive, s.Size(), payload)
+	return
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It returns
+// godium.ErrInvalidState if the state was created by a different primitive.
+func (s *shaImpl) UnmarshalBinary(state []byte) (err error) {
+	payload, err := internal.UnmarshalState(state, s.primitive, s.Size())
+	if err != nil {
+		return
+	}
+
+	u, ok := s.Hash.(encoding.BinaryUnmarshaler)
+	if !ok {
+		err = ErrStateUnsupported
+		return
+	}
+
+	if u.UnmarshalBinary(payload) != nil {
+		err = godium.ErrInvalidState
+	}
+	return
+}
+
 func (s *shaImpl) Bytes() int { return s.Hash.Size() }
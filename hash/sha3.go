@@ -0,0 +1,126 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package hash
+
+import (
+	"golang.org/x/crypto/sha3"
+
+	"go.artemisc.eu/godium"
+)
+
+const (
+	Sha3_256_Bytes = 32
+
+	Sha3_512_Bytes = 64
+
+	// Shake128_Bytes and Shake256_Bytes are the output sizes that give the
+	// full security level of the SHAKE functions, and are used by Sum.
+	Shake128_Bytes      = 32
+	Shake128_BlockBytes = 168
+
+	Shake256_Bytes      = 64
+	Shake256_BlockBytes = 136
+)
+
+// Shake implements both the godium.Hash and the godium.XOF interfaces for
+// SHAKE128 and SHAKE256. As a godium.Hash, Sum appends Size bytes of output
+// without changing the state. As a godium.XOF, any amount of output can be
+// read after writing the input.
+type Shake struct {
+	sha3.ShakeHash
+	size      int
+	blockSize int
+}
+
+// NewSha3_256
+func NewSha3_256() (h godium.Hash) {
+	h = &shaImpl{
+		Hash:      sha3.New256(),
+		primitive: "sha3256",
+	}
+	return
+}
+
+// NewSha3_512
+func NewSha3_512() (h godium.Hash) {
+	h = &shaImpl{
+		Hash:      sha3.New512(),
+		primitive: "sha3512",
+	}
+	return
+}
+
+// NewShake128 creates a SHAKE128 state, whose Sum appends size bytes.
+func NewShake128(size int) (s *Shake) {
+	s = &Shake{
+		ShakeHash: sha3.NewShake128(),
+		size:      size,
+		blockSize: Shake128_BlockBytes,
+	}
+	return
+}
+
+// NewShake256 creates a SHAKE256 state, whose Sum appends size bytes.
+func NewShake256(size int) (s *Shake) {
+	s = &Shake{
+		ShakeHash: sha3.NewShake256(),
+		size:      size,
+		blockSize: Shake256_BlockBytes,
+	}
+	return
+}
+
+// SumSha3_256
+func SumSha3_256(dst, data []byte) (sum []byte) {
+	sha := sha3.Sum256(data)
+	sum = append(dst, sha[:]...)
+	return
+}
+
+// SumSha3_512
+func SumSha3_512(dst, data []byte) (sum []byte) {
+	sha := sha3.Sum512(data)
+	sum = append(dst, sha[:]...)
+	return
+}
+
+// SumShake128 appends size bytes of SHAKE128 output for data to dst.
+func SumShake128(dst, data []byte, size int) (sum []byte) {
+	sum = sumShake(dst, sha3.NewShake128(), data, size)
+	return
+}
+
+// SumShake256 appends size bytes of SHAKE256 output for data to dst.
+func SumShake256(dst, data []byte, size int) (sum []byte) {
+	sum = sumShake(dst, sha3.NewShake256(), data, size)
+	return
+}
+
+// sumShake appends size bytes of output of h for data to dst.
+func sumShake(dst []byte, h sha3.ShakeHash, data []byte, size int) (sum []byte) {
+	sum = append(dst, make([]byte, size)...)
+
+	h.Write(data)
+	h.Read(sum[len(dst):])
+	h.Reset()
+	return
+}
+
+// Sum appends Size bytes of output to dst, without changing the state.
+func (s *Shake) Sum(dst []byte) (sum []byte) {
+	sum = sumShake(dst, s.ShakeHash.Clone(), nil, s.size)
+	return
+}
+
+// Wipe erases the state, by resetting it.
+func (s *Shake) Wipe() {
+	s.ShakeHash.Reset()
+}
+
+func (s *Shake) Size() int      { return s.size }
+func (s *Shake) BlockSize() int { return s.blockSize }
+func (s *Shake) Bytes() int     { return s.size }
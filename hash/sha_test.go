@@ -0,0 +1,133 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package hash
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+func TestShaState(t *testing.T) {
+	msg := bytes.Repeat([]byte("godium"), 100)
+
+	for _, newHash := range []func() godium.Hash{NewSha256, NewSha384, NewSha512, NewSha512_256} {
+		h := newHash()
+		h.Write(msg[:333])
+		state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resumed := newHash()
+		if err = resumed.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+		resumed.Write(msg[333:])
+
+		expect := newHash()
+		expect.Write(msg)
+		if !bytes.Equal(expect.Sum(nil), resumed.Sum(nil)) {
+			t.Error("expected result did not match computed")
+		}
+	}
+
+	state, _ := NewSha256().(encoding.BinaryMarshaler).MarshalBinary()
+	err := NewSha512().(encoding.BinaryUnmarshaler).UnmarshalBinary(state)
+	if err != godium.ErrInvalidState {
+		t.Error("state was restored into a different primitive")
+	}
+}
+
+var (
+	_ godium.Hash = (*Shake)(nil)
+	_ godium.XOF  = (*Shake)(nil)
+)
+
+var shaTests = []struct {
+	newHash func() godium.Hash
+	sum     func(dst, data []byte) []byte
+	empty   string
+	abc     string
+}{
+	{NewSha384, SumSha384,
+		"38b060a751ac96384cd9327eb1b1e36a21fdb71114be07434c0cc7bf63f6e1da274edebfe76f65fbd51ad2f14898b95b",
+		"cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7"},
+	{NewSha512_256, SumSha512_256,
+		"c672b8d1ef56ed28ab87c3622c5114069bdd3ad7b8f9737498d0c01ecef0967a",
+		"53048e2681941ef99b2e29b76b4c7dabe4c2d0c634fc6d46e0e2f13107e7af23"},
+	{NewSha3_256, SumSha3_256,
+		"a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a",
+		"3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532"},
+	{NewSha3_512, SumSha3_512,
+		"a69f73cca23a9ac5c8b567dc185a756e97c982164fe25859e0d1dcc1475c80a6" +
+			"15b2123af1f5f94c11e3e9402c3ac558f500199d95b6d3e301758586281dcd26",
+		"b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e" +
+			"10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0"},
+	{func() godium.Hash { return NewShake128(Shake128_Bytes) },
+		func(dst, data []byte) []byte { return SumShake128(dst, data, Shake128_Bytes) },
+		"7f9c2ba4e88f827d616045507605853ed73b8093f6efbc88eb1a6eacfa66ef26",
+		"5881092dd818bf5cf8a3ddb793fbcba74097d5c526a6d35f97b83351940f2cc8"},
+	{func() godium.Hash { return NewShake256(Shake256_Bytes) },
+		func(dst, data []byte) []byte { return SumShake256(dst, data, Shake256_Bytes) },
+		"46b9dd2b0ba88d13233b3feb743eeb243fcd52ea62b81b82b50c27646ed5762f" +
+			"d75dc4ddd8c0f200cb05019d67b592f6fc821c49479ab48640292eacb3b7c4be",
+		"483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739" +
+			"d5a15bef186a5386c75744c0527e1faa9f8726e462a12a4feb06bd8801e751e4"},
+}
+
+func TestSha(t *testing.T) {
+	for _, test := range shaTests {
+		for _, kat := range []struct{ msg, expect string }{{"", test.empty}, {"abc", test.abc}} {
+			expect, _ := hex.DecodeString(kat.expect)
+
+			got := test.sum([]byte("prefix"), []byte(kat.msg))
+			if !bytes.Equal(append([]byte("prefix"), expect...), got) {
+				t.Error("expected result did not match computed", expect, got)
+			}
+
+			h := test.newHash()
+			for i := range kat.msg {
+				h.Write([]byte(kat.msg[i : i+1]))
+			}
+			if got = h.Sum(nil); !bytes.Equal(expect, got) || h.Bytes() != len(expect) {
+				t.Error("expected result did not match computed", expect, got)
+			}
+		}
+	}
+}
+
+func TestShakeXOF(t *testing.T) {
+	expect := SumShake256(nil, []byte("abc"), 1000)
+
+	s := NewShake256(Shake256_Bytes)
+	s.Write([]byte("abc"))
+	if !bytes.Equal(expect[:Shake256_Bytes], s.Sum(nil)) {
+		t.Error("Sum did not match the start of the output")
+	}
+
+	got := make([]byte, len(expect))
+	for i := 0; i < len(got); i += 7 {
+		end := i + 7
+		if end > len(got) {
+			end = len(got)
+		}
+		s.Read(got[i:end])
+	}
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+
+	s.Reset()
+	s.Write([]byte("abc"))
+	if !bytes.Equal(expect[:Shake256_Bytes], s.Sum(nil)) {
+		t.Error("expected result did not match computed after reset")
+	}
+}
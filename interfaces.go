@@ -36,6 +36,19 @@ var (
 	// that should at least contain a certain amount of bytes to hold a full
 	// piece of data for an algorithm.
 	ErrBufferTooShort = errors.New("buffer shorter than expected size")
+
+	// ErrInvalidPadding is returned when the padding of a message can not be
+	// removed, because it is not formatted as expected by the Padding scheme.
+	ErrInvalidPadding = errors.New("message padding is invalid")
+
+	// ErrInvalidState is returned when a serialized state can not be restored,
+	// because it is malformed, or was created by a different primitive or for
+	// a different output size.
+	ErrInvalidState = errors.New("serialized state is invalid or does not match the primitive")
+
+	// ErrUnknownVersion is returned when a message starts with a format version
+	// that is not supported, for example one written by a newer release.
+	ErrUnknownVersion = errors.New("message format version is unknown or unsupported")
 )
 
 // Wipe will override the contents of the buffer p with 0's.
@@ -62,6 +75,23 @@ type PrivateKey []byte
 // PublicKey
 type PublicKey []byte
 
+// KeyPair is implemented by every primitive that is built around an asymmetric
+// key pair, such as Box, Kx and Sign. It allows key management code to handle
+// all types of key pairs in the same way.
+type KeyPair interface {
+	Wiper
+
+	// PublicKey returns a copy of the public key of the pair.
+	PublicKey() (pk PublicKey)
+
+	// SecretKey returns a copy of the secret key of the pair.
+	SecretKey() (sk PrivateKey)
+
+	PublicKeyBytes() (c int)
+	SecretKeyBytes() (c int)
+	SeedBytes() (c int)
+}
+
 // AEAD
 type AEAD interface {
 	cipher.AEAD
@@ -91,7 +121,7 @@ type Auth interface {
 
 // Box
 type Box interface {
-	Wiper
+	KeyPair
 
 	SealDetached(dst, dstMac, nonce, plain []byte, remote PublicKey) (cipher, mac []byte, err error)
 
@@ -103,11 +133,8 @@ type Box interface {
 
 	BeforeNM(remote PublicKey) (sb SecretBox, err error)
 
-	PublicKeyBytes() (c int)
-	SecretKeyBytes() (c int)
 	MacBytes() (c int)
 	NonceBytes() (c int)
-	SeedBytes() (c int)
 	BeforeNmBytes() (c int)
 }
 
@@ -145,7 +172,7 @@ type Kdf interface {
 
 // Kx
 type Kx interface {
-	Wiper
+	KeyPair
 
 	// ServerSessionKeys
 	ServerSessionKeys(dstRx, dstTx []byte, remote PublicKey) (rx, tx Key, err error)
@@ -153,11 +180,6 @@ type Kx interface {
 	// ServerSessionKeys
 	ClientSessionKeys(dstRx, dstTx []byte, remote PublicKey) (rx, tx Key, err error)
 
-	PublicKey() (pk PublicKey)
-
-	PublicKeyBytes() (c int)
-	SecretKeyBytes() (c int)
-	SeedBytes() (c int)
 	SessionKeyBytes() (c int)
 }
 
@@ -171,6 +193,22 @@ type OneTimeAuth interface {
 	ReKey(key []byte)
 }
 
+// Padding hides the exact length of a message by extending it to the next
+// bucket boundary of the scheme. The padding is self-describing, so the
+// original message can be recovered without knowing its length.
+type Padding interface {
+	// Pad appends the padding for buf to buf, and returns the result.
+	Pad(buf []byte) (padded []byte)
+
+	// Unpad returns the message that was padded to form padded, without
+	// copying it. The work done does not depend on the length of the padding.
+	Unpad(padded []byte) (buf []byte, err error)
+
+	// PaddedBytes returns the length of the padded form of a message of
+	// length n.
+	PaddedBytes(n int) (c int)
+}
+
 // PwHash implements a password hashing and password based key derivation
 // algorithm. These algorithms are meant to be hard on memory and slow to
 // compute.
@@ -266,7 +304,7 @@ type ShortHash128 interface {
 
 // Sign
 type Sign interface {
-	Wiper
+	KeyPair
 
 	// Detached signs the message data in unsigned, and returns a message with
 	// the signature
@@ -288,13 +326,7 @@ type Sign interface {
 	// This operation will fail if Write has not been called before.
 	Final(dst []byte) (signature []byte)
 
-	// PublicKey
-	PublicKey() (p PublicKey)
-
-	PublicKeyBytes() (c int)
-	SecretKeyBytes() (c int)
 	Bytes() (c int)
-	SeedBytes() (c int)
 }
 
 // SignVerifier
@@ -349,6 +381,18 @@ type Stream interface {
 	BlockBytes() (c int)
 }
 
+// XOF describes an extendable-output function. Data is absorbed with Write,
+// after which output of arbitrary length is squeezed out with Read. Writing
+// after the first Read is not allowed.
+type XOF interface {
+	io.Writer
+	io.Reader
+	Wiper
+
+	// Reset restores the XOF to its initial state, so it can absorb new data.
+	Reset()
+}
+
 // Codec implements a constant-time encoding algorithm to convert between binary
 // data a printable text representation.
 type Codec interface {
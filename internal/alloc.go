@@ -25,3 +25,13 @@ func Copy(buf []byte, n uint64) (cpy []byte) {
 	copy(cpy, buf)
 	return
 }
+
+// CopyOut copies out, the result of a Seal or Open implementation that was
+// given dst[:0], to dst. Implementations from other packages may allocate even
+// when dst has enough room, so out is only trusted to be in place if it starts
+// at dst.
+func CopyOut(dst, out []byte) {
+	if len(out) > 0 && &dst[0] != &out[0] {
+		copy(dst, out)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package cpu reports the instruction set extensions of the processor, which
+// are used to select assembly implementations at run time. All features are
+// reported as missing on other architectures, or when built with the purego
+// tag.
+package cpu // import "go.artemisc.eu/godium/internal/cpu"
+
+// X86 holds the features of x86 processors used by godium.
+var X86 struct {
+	HasSSSE3 bool
+	HasSSE41 bool
+	HasAVX   bool
+	HasAVX2  bool
+	HasBMI2  bool
+}
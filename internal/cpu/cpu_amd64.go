@@ -0,0 +1,47 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+package cpu
+
+// cpuid is implemented in cpu_amd64.s.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv is implemented in cpu_amd64.s, and reads the XCR0 register.
+func xgetbv() (eax, edx uint32)
+
+func init() {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 1 {
+		return
+	}
+
+	_, _, ecx1, _ := cpuid(1, 0)
+	X86.HasSSSE3 = isSet(ecx1, 9)
+	X86.HasSSE41 = isSet(ecx1, 19)
+
+	// the OS must save the XMM and YMM registers for AVX to be usable.
+	osSupportsAVX := false
+	if isSet(ecx1, 27) {
+		eax, _ := xgetbv()
+		osSupportsAVX = isSet(eax, 1) && isSet(eax, 2)
+	}
+	X86.HasAVX = isSet(ecx1, 28) && osSupportsAVX
+
+	if maxID < 7 {
+		return
+	}
+
+	_, ebx7, _, _ := cpuid(7, 0)
+	X86.HasAVX2 = isSet(ebx7, 5) && osSupportsAVX
+	X86.HasBMI2 = isSet(ebx7, 8)
+}
+
+// isSet returns whether bit is set in v.
+func isSet(v uint32, bit uint) bool {
+	return v&(1<<bit) != 0
+}
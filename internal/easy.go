@@ -0,0 +1,74 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+import (
+	"go.artemisc.eu/godium"
+)
+
+const (
+	// EasyVersion is the format version of the messages of the Encrypt
+	// functions: the version byte, followed by the nonce and the sealed
+	// message.
+	EasyVersion = 1
+
+	EasyHeaderBytes = 1
+)
+
+// EasyHeader writes the version byte and a random nonce of nonceBytes bytes
+// to dst. The sealed message can be written to the free capacity of out, and
+// added with EasyAppend.
+func EasyHeader(dst []byte, rnd godium.Random, nonceBytes int) (out, nonce []byte, err error) {
+	out = AllocDst(dst, uint64(EasyHeaderBytes+nonceBytes))
+	out[0] = EasyVersion
+	nonce = out[EasyHeaderBytes:]
+
+	err = rnd.Buf(nonce)
+	return
+}
+
+// EasyAppend adds sealed to out. If sealed was already written to the free
+// capacity of out, like AllocDst does, out is only extended. Otherwise sealed
+// is copied, as Seal implementations from other packages may allocate even
+// when dst has enough room.
+func EasyAppend(out, sealed []byte) (framed []byte) {
+	n := len(out) + len(sealed)
+	if len(sealed) > 0 && n <= cap(out) && &out[:n][len(out)] == &sealed[0] {
+		framed = out[:n]
+		return
+	}
+
+	framed = append(out, sealed...)
+	return
+}
+
+// EasyAD returns the additional data an AEAD message is sealed with: the
+// header, which is the version byte and the nonce, followed by ad. Binding the
+// header keeps a message from being accepted under a different version that
+// reads the rest of it in the same way.
+func EasyAD(header, ad []byte) (bound []byte) {
+	bound = make([]byte, 0, len(header)+len(ad))
+	bound = append(bound, header...)
+	bound = append(bound, ad...)
+	return
+}
+
+// EasyParse checks the version byte of framed, and splits the nonce of
+// nonceBytes bytes from the sealed message, which is at least overhead bytes
+// long.
+func EasyParse(framed []byte, nonceBytes, overhead int) (nonce, sealed []byte, err error) {
+	hdr := EasyHeaderBytes + nonceBytes
+	switch {
+	case len(framed) < hdr+overhead:
+		err = godium.ErrCipherTooShort
+	case framed[0] != EasyVersion:
+		err = godium.ErrUnknownVersion
+	default:
+		nonce, sealed = framed[EasyHeaderBytes:hdr], framed[hdr:]
+	}
+	return
+}
@@ -0,0 +1,20 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+import (
+	"go.artemisc.eu/godium"
+)
+
+// Pad copies plain into a buffer of exactly the padded size, and pads it. The
+// caller should wipe the result after use.
+func Pad(p godium.Padding, plain []byte) (padded []byte) {
+	padded = make([]byte, len(plain), p.PaddedBytes(len(plain)))
+	copy(padded, plain)
+	padded = p.Pad(padded)
+	return
+}
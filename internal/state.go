@@ -0,0 +1,56 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+import (
+	"bytes"
+
+	"go.artemisc.eu/godium"
+)
+
+const (
+	// stateMagic starts every serialized state.
+	stateMagic = "godium"
+
+	// stateVersion is the version of the serialization format. It must be
+	// incremented when the layout of the header or any payload changes.
+	stateVersion = 1
+)
+
+// MarshalState serializes the payload of a primitive's state, prefixed with a
+// header that identifies the format version, primitive and output size:
+//
+//	"godium" || version || len(primitive) || primitive || size || payload
+func MarshalState(primitive string, size int, payload []byte) (state []byte) {
+	state = make([]byte, 0, len(stateMagic)+3+len(primitive)+len(payload))
+	state = append(state, stateMagic...)
+	state = append(state, stateVersion, byte(len(primitive)))
+	state = append(state, primitive...)
+	state = append(state, byte(size))
+	state = append(state, payload...)
+	return
+}
+
+// UnmarshalState checks the header of a state serialized by MarshalState, and
+// returns the payload. It returns godium.ErrInvalidState if the version,
+// primitive or output size do not match.
+func UnmarshalState(state []byte, primitive string, size int) (payload []byte, err error) {
+	var header []byte
+
+	header = append(header, stateMagic...)
+	header = append(header, stateVersion, byte(len(primitive)))
+	header = append(header, primitive...)
+	header = append(header, byte(size))
+
+	if !bytes.HasPrefix(state, header) {
+		err = godium.ErrInvalidState
+		return
+	}
+
+	payload = state[len(header):]
+	return
+}
@@ -0,0 +1,81 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package internal
+
+// Vec walks a list of segments as if they were one contiguous buffer. A copy
+// of a Vec keeps its own position, so it can be used to come back to a part
+// of the buffer later.
+type Vec struct {
+	segs [][]byte
+	off  int
+	left int
+}
+
+// NewVec
+func NewVec(segs [][]byte) (v Vec) {
+	v.segs = segs
+	for _, s := range segs {
+		v.left += len(s)
+	}
+	return
+}
+
+// Len returns the number of bytes left.
+func (v *Vec) Len() int {
+	return v.left
+}
+
+// Next returns the next piece of the buffer, at most max bytes long, without
+// crossing the end of a segment. It returns an empty slice at the end.
+func (v *Vec) Next(max int) (p []byte) {
+	for len(v.segs) > 0 && v.off == len(v.segs[0]) {
+		v.segs, v.off = v.segs[1:], 0
+	}
+	if len(v.segs) == 0 {
+		return
+	}
+
+	p = v.segs[0][v.off:]
+	if len(p) > max {
+		p = p[:max]
+	}
+	v.off += len(p)
+	v.left -= len(p)
+	return
+}
+
+// Skip advances n bytes.
+func (v *Vec) Skip(n int) {
+	for n > 0 && v.left > 0 {
+		n -= len(v.Next(n))
+	}
+}
+
+// Read copies the next bytes of the buffer to dst.
+func (v *Vec) Read(dst []byte) (n int) {
+	for n < len(dst) && v.left > 0 {
+		n += copy(dst[n:], v.Next(len(dst)-n))
+	}
+	return
+}
+
+// Write copies src to the next bytes of the buffer.
+func (v *Vec) Write(src []byte) (n int) {
+	for n < len(src) && v.left > 0 {
+		n += copy(v.Next(len(src)-n), src[n:])
+	}
+	return
+}
+
+// Gather appends the concatenation of the segments to dst.
+func Gather(dst []byte, segs [][]byte) (out []byte) {
+	out = dst
+	for _, s := range segs {
+		out = append(out, s...)
+	}
+	return
+}
@@ -49,17 +49,14 @@ func (k *Blake2b) Derive(dst []byte, length, id uint64) (subKey []byte) {
 
 	subKey = internal.AllocDst(dst, length)
 	copy(context[:8], k.Context[:])
-	for i := range context[8:] {
-		context[i] = 0x00
-	}
-
 	binary.LittleEndian.PutUint64(salt[:8], id)
-	for i := range salt[8:] {
-		context[i] = 0x00
-	}
 
-	h := generichash.NewBlake2bSaltPersonal(uint32(length), k.Key, context[:], salt[:])
+	h, err := generichash.NewBlake2bSaltPersonal(uint32(length), k.Key, context[:], salt[:])
+	if err != nil {
+		panic("kdf: invalid sub key length")
+	}
 	h.Sum(subKey[:0])
+	h.Wipe()
 
 	return
 }
@@ -0,0 +1,33 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kdf
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlake2bDerive(t *testing.T) {
+	// crypto_kdf_derive_from_key(subkey, 32, 0x1234, "KDF test", key), with
+	// the key 0x00, 0x01, ..., 0x1f.
+	expect := []byte{
+		0xa7, 0x11, 0xf0, 0x5b, 0xc2, 0xc4, 0xce, 0x8d,
+		0x77, 0x15, 0x54, 0xdb, 0xf8, 0xe0, 0x9f, 0x95,
+		0x79, 0x70, 0xaf, 0x4e, 0xa9, 0x40, 0x5a, 0x6e,
+		0x4b, 0xb3, 0x05, 0x47, 0x5c, 0xdf, 0xdc, 0x9f,
+	}
+
+	key := make([]byte, Blake2b_KeyBytes)
+	for i := range key {
+		key[i] = byte(i)
+	}
+
+	got := NewBlake2b(key, []byte("KDF test")).Derive(nil, 32, 0x1234)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
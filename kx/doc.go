@@ -27,8 +27,26 @@ func New(public godium.PublicKey, private godium.PrivateKey) (kx godium.Kx) {
 	return
 }
 
+// KeyPair
+func KeyPair(random godium.Random) (kx godium.Kx, err error) {
+	x, err := KeyPairX25519Blake2b(random)
+	if err != nil {
+		return
+	}
+	kx = x
+	return
+}
+
+// KeyPairSeed
+func KeyPairSeed(seed []byte) (kx godium.Kx) {
+	kx = KeyPairSeedX25519Blake2b(seed)
+	return
+}
+
 // KeyGen
+//
+// Deprecated: use KeyPair, which has the same behaviour.
 func KeyGen(random godium.Random) (kx godium.Kx, err error) {
-	kx, err = KeyGenX25519Blake2b(random)
+	kx, err = KeyPair(random)
 	return
 }
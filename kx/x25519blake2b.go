@@ -35,20 +35,52 @@ func NewX25519Blake2b(public godium.PublicKey, private godium.PrivateKey) (kx *X
 	return
 }
 
-// KeyGenX25519Blake2b
-func KeyGenX25519Blake2b(random godium.Random) (kx *X25519Blake2b, err error) {
+// KeyPairX25519Blake2b generates a new key pair from random bytes, like
+// crypto_kx_keypair.
+func KeyPairX25519Blake2b(random godium.Random) (kx *X25519Blake2b, err error) {
 	private, err := random.KeyGen(X25519Blake2b_SecretKeyBytes)
 	if err != nil {
 		return
 	}
 
-	public := make([]byte, scalarmult.Curve25519_ScalarBytes)
-	scalarmult.Curve25519Base(public[:0], private)
+	kx = &X25519Blake2b{
+		public:     scalarmult.Curve25519Base(make([]byte, 0, X25519Blake2b_PublicKeyBytes), private),
+		PrivateKey: private,
+	}
+	return
+}
+
+// KeyPairSeedX25519Blake2b deterministically derives a key pair from the
+// seed, like crypto_kx_seed_keypair: the secret key is the 32 byte BLAKE2b hash
+// of the seed. It panics if the seed is not X25519Blake2b_SeedBytes long.
+func KeyPairSeedX25519Blake2b(seed []byte) (kx *X25519Blake2b) {
+	if len(seed) != X25519Blake2b_SeedBytes {
+		panic("kx: invalid X25519-BLAKE2b seed length")
+	}
+
+	seed = internal.Copy(seed, X25519Blake2b_SeedBytes)
+	defer godium.Wipe(seed)
 
+	private := make([]byte, 0, X25519Blake2b_SecretKeyBytes)
+	h := generichash.NewBlake2b256(nil)
+	h.Write(seed)
+	private = h.Sum(private)
+	h.Wipe()
+
+	kp := scalarmult.KeyPairSeedCurve25519(private)
 	kx = &X25519Blake2b{
-		public:     public,
+		public:     kp.PublicKey(),
 		PrivateKey: private,
 	}
+	kp.Wipe()
+	return
+}
+
+// KeyGenX25519Blake2b
+//
+// Deprecated: use KeyPairX25519Blake2b, which has the same behaviour.
+func KeyGenX25519Blake2b(random godium.Random) (kx *X25519Blake2b, err error) {
+	kx, err = KeyPairX25519Blake2b(random)
 	return
 }
 
@@ -119,6 +151,11 @@ func (kx *X25519Blake2b) PublicKey() godium.PublicKey {
 	return internal.Copy(kx.public, X25519Blake2b_PublicKeyBytes)
 }
 
+// SecretKey
+func (kx *X25519Blake2b) SecretKey() godium.PrivateKey {
+	return internal.Copy(kx.PrivateKey, X25519Blake2b_SecretKeyBytes)
+}
+
 func (kx *X25519Blake2b) PublicKeyBytes() int  { return X25519Blake2b_PublicKeyBytes }
 func (kx *X25519Blake2b) SecretKeyBytes() int  { return X25519Blake2b_SecretKeyBytes }
 func (kx *X25519Blake2b) SeedBytes() int       { return X25519Blake2b_SeedBytes }
@@ -0,0 +1,63 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package kx
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+var _ godium.KeyPair = (*X25519Blake2b)(nil)
+
+// TestKeyPairSeed uses the seed_keypair test vector from libsodium.
+func TestKeyPairSeed(t *testing.T) {
+	var (
+		seed [SeedBytes]byte
+
+		expectPk = []byte{
+			0x0e, 0x02, 0x16, 0x22, 0x3f, 0x14, 0x71, 0x43,
+			0xd3, 0x26, 0x15, 0xa9, 0x11, 0x89, 0xc2, 0x88,
+			0xc1, 0x72, 0x8c, 0xba, 0x3c, 0xc5, 0xf9, 0xf6,
+			0x21, 0xb1, 0x02, 0x6e, 0x03, 0xd8, 0x31, 0x29,
+		}
+
+		expectSk = []byte{
+			0xcb, 0x2f, 0x51, 0x60, 0xfc, 0x1f, 0x7e, 0x05,
+			0xa5, 0x5e, 0xf4, 0x9d, 0x34, 0x0b, 0x48, 0xda,
+			0x2e, 0x5a, 0x78, 0x09, 0x9d, 0x53, 0x39, 0x33,
+			0x51, 0xcd, 0x57, 0x9d, 0xd4, 0x25, 0x03, 0xd6,
+		}
+	)
+
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	kp := KeyPairSeed(seed[:])
+
+	if !bytes.Equal(expectPk, kp.PublicKey()) {
+		t.Error("expected public key did not match computed", expectPk, kp.PublicKey())
+	}
+	if !bytes.Equal(expectSk, kp.SecretKey()) {
+		t.Error("expected secret key did not match computed", expectSk, kp.SecretKey())
+	}
+}
+
+func TestKeyPairSeedLength(t *testing.T) {
+	for _, n := range []int{0, SeedBytes - 1, SeedBytes + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("seed of invalid length was accepted", n)
+				}
+			}()
+			KeyPairSeed(make([]byte, n))
+		}()
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+/*
+
+Package noncecheck detects nonce reuse while testing. When a program or its
+tests are built with the godium_noncecheck build tag, a fingerprint of the
+key and nonce of every Seal of the aead, secretbox and box packages, and of
+every stream created or rekeyed outside the library, is recorded. Using the
+same key and nonce a second time panics, and the panic shows where the pair
+was used first:
+
+	go test -tags godium_noncecheck ./...
+
+The nonce misuse-resistant AEADs of the aead package are not checked, and
+opening a message is never recorded. A stream can not tell encryption from
+decryption, so a stream created again to decrypt is reported as well. Without
+the build tag, nothing is recorded and the checks cost nothing.
+
+Only fingerprints are kept, but they are computed from the keys, so the tag
+is meant for tests and never for production builds.
+
+*/
+package noncecheck // import "go.artemisc.eu/godium/noncecheck"
@@ -0,0 +1,114 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build godium_noncecheck
+
+package noncecheck
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Enabled reports whether the library was built with the godium_noncecheck
+// build tag.
+const Enabled = true
+
+// libraryPrefix identifies the functions of the library in stack traces.
+const libraryPrefix = "go.artemisc.eu/godium/"
+
+var (
+	mu   sync.Mutex
+	seen = make(map[[sha256.Size]byte][]uintptr)
+)
+
+// Record panics if the key and nonce were recorded before under the label,
+// which names the primitive.
+func Record(label string, key, nonce []byte) {
+	record(label, key, nonce)
+}
+
+// RecordStream is Record for the stream package. Streams created by other
+// packages of the library are not recorded, as they are also used to
+// decrypt, and the packages record their own seals.
+func RecordStream(label string, key, nonce []byte) {
+	var pcs [8]uintptr
+
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, libraryPrefix+"stream.") {
+			if strings.HasPrefix(f.Function, libraryPrefix) && !strings.HasSuffix(f.File, "_test.go") {
+				return
+			}
+			break
+		}
+		if !more {
+			break
+		}
+	}
+
+	record(label, key, nonce)
+}
+
+// Reset forgets every recorded key and nonce, for example between tests that
+// use fixed keys.
+func Reset() {
+	mu.Lock()
+	seen = make(map[[sha256.Size]byte][]uintptr)
+	mu.Unlock()
+}
+
+// record keeps the fingerprint of the label, key and nonce, with the stack of
+// the caller.
+func record(label string, key, nonce []byte) {
+	var n [8]byte
+
+	h := sha256.New()
+	for _, v := range [][]byte{[]byte(label), key, nonce} {
+		binary.LittleEndian.PutUint64(n[:], uint64(len(v)))
+		h.Write(n[:])
+		h.Write(v)
+	}
+
+	var fp [sha256.Size]byte
+	h.Sum(fp[:0])
+
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+
+	mu.Lock()
+	first, reused := seen[fp]
+	if !reused {
+		seen[fp] = pcs
+	}
+	mu.Unlock()
+
+	if reused {
+		panic(fmt.Sprintf("noncecheck: nonce reused with the same key by %s, first used at:\n%s",
+			label, formatStack(first)))
+	}
+}
+
+// formatStack formats the stack like a panic does.
+func formatStack(pcs []uintptr) string {
+	var b strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,22 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build !godium_noncecheck
+
+package noncecheck
+
+// Enabled reports whether the library was built with the godium_noncecheck
+// build tag.
+const Enabled = false
+
+// Record does nothing without the godium_noncecheck build tag.
+func Record(label string, key, nonce []byte) {}
+
+// RecordStream does nothing without the godium_noncecheck build tag.
+func RecordStream(label string, key, nonce []byte) {}
+
+// Reset does nothing without the godium_noncecheck build tag.
+func Reset() {}
@@ -0,0 +1,27 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build !godium_noncecheck
+
+package noncecheck_test
+
+import (
+	"testing"
+
+	"go.artemisc.eu/godium/aead"
+	"go.artemisc.eu/godium/noncecheck"
+)
+
+func TestDisabled(t *testing.T) {
+	if noncecheck.Enabled {
+		t.Error("nonce checks are enabled without the build tag")
+	}
+
+	a := aead.NewChacha20Poly1305Ietf(make([]byte, aead.Chacha20Poly1305Ietf_KeyBytes))
+	nonce := make([]byte, a.NPubBytes())
+	a.Seal(nil, nonce, nil, nil)
+	a.Seal(nil, nonce, nil, nil)
+}
@@ -0,0 +1,192 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build godium_noncecheck
+
+package noncecheck_test
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/aead"
+	"go.artemisc.eu/godium/box"
+	"go.artemisc.eu/godium/noncecheck"
+	"go.artemisc.eu/godium/secretbox"
+	"go.artemisc.eu/godium/stream"
+)
+
+// testBytes returns size bytes counting up from start.
+func testBytes(start byte, size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = start + byte(i)
+	}
+	return
+}
+
+// expectReuse fails the test if f does not panic with a report that shows
+// where the nonce was used first.
+func expectReuse(t *testing.T, name string, f func()) {
+	defer func() {
+		r := recover()
+		msg := fmt.Sprint(r)
+		if r == nil || !strings.HasPrefix(msg, "noncecheck: nonce reused") ||
+			!strings.Contains(msg, "noncecheck_test.go") {
+			t.Error("expected nonce reuse panic", name, r)
+		}
+	}()
+	f()
+}
+
+func TestEnabled(t *testing.T) {
+	if !noncecheck.Enabled {
+		t.Error("nonce checks are disabled with the build tag")
+	}
+}
+
+func TestAEAD(t *testing.T) {
+	aeads := []struct {
+		name string
+		new  func(key []byte) godium.AEAD
+	}{
+		{"chacha20poly1305", aead.NewChacha20Poly1305},
+		{"chacha20poly1305ietf", aead.NewChacha20Poly1305Ietf},
+		{"xchacha20poly1305ietf", aead.NewXChacha20Poly1305Ietf},
+		{"aes256gcm", aead.NewAes256Gcm},
+		{"committing", func(key []byte) godium.AEAD {
+			return aead.NewCommitting(key, aead.NewChacha20Poly1305Ietf)
+		}},
+	}
+
+	noncecheck.Reset()
+	for _, v := range aeads {
+		a := v.new(testBytes(0x00, 32))
+		nonce := testBytes(0x40, a.NPubBytes())
+		plain := []byte("message")
+
+		cipher := a.Seal(nil, nonce, plain, nil)
+		if _, err := a.Open(nil, nonce, cipher, nil); err != nil {
+			t.Error("message did not open", v.name, err)
+		}
+		a.Seal(nil, testBytes(0x80, a.NPubBytes()), plain, nil)
+		v.new(testBytes(0x01, 32)).Seal(nil, nonce, plain, nil)
+
+		expectReuse(t, v.name+" Seal", func() { a.Seal(nil, nonce, plain, []byte("ad")) })
+		expectReuse(t, v.name+" SealDetached", func() { a.SealDetached(nil, nil, nonce, plain, nil) })
+		expectReuse(t, v.name+" SealVec", func() {
+			aead.SealVec(a, [][]byte{make([]byte, len(cipher))}, nonce, [][]byte{plain}, nil)
+		})
+	}
+}
+
+func TestSealer(t *testing.T) {
+	sealers := []struct {
+		name      string
+		new       func(key []byte) godium.AEAD
+		newSealer func(w io.Writer, key, nonce []byte) *aead.Sealer
+	}{
+		{"chacha20poly1305", aead.NewChacha20Poly1305, aead.NewChacha20Poly1305Sealer},
+		{"chacha20poly1305ietf", aead.NewChacha20Poly1305Ietf, aead.NewChacha20Poly1305IetfSealer},
+		{"xchacha20poly1305ietf", aead.NewXChacha20Poly1305Ietf, aead.NewXChacha20Poly1305IetfSealer},
+	}
+
+	noncecheck.Reset()
+	for _, v := range sealers {
+		key := testBytes(0x00, 32)
+		a := v.new(key)
+		nonce := testBytes(0x40, a.NPubBytes())
+
+		a.Seal(nil, nonce, []byte("message"), nil)
+		expectReuse(t, v.name, func() { v.newSealer(ioutil.Discard, key, nonce) })
+	}
+}
+
+func TestSecretBox(t *testing.T) {
+	boxes := []struct {
+		name string
+		new  func(key []byte) godium.SecretBox
+	}{
+		{"xsalsa20poly1305", secretbox.NewXSalsa20Poly1305},
+		{"xchacha20poly1305", secretbox.NewXChacha20Poly1305},
+	}
+
+	for _, v := range boxes {
+		noncecheck.Reset()
+		b := v.new(testBytes(0x00, 32))
+		nonce := testBytes(0x40, b.NonceBytes())
+		plain := []byte("message")
+
+		cipher := b.Seal(nil, nonce, plain)
+		if _, err := b.Open(nil, nonce, cipher); err != nil {
+			t.Error("message did not open", v.name, err)
+		}
+		secretbox.OpenVec(b, [][]byte{make([]byte, len(cipher))}, nonce, [][]byte{cipher})
+
+		expectReuse(t, v.name+" Seal", func() { b.Seal(nil, nonce, plain) })
+		expectReuse(t, v.name+" SealDetached", func() { b.SealDetached(nil, nil, nonce, plain) })
+		expectReuse(t, v.name+" SealVec", func() {
+			secretbox.SealVec(b, [][]byte{make([]byte, len(cipher))}, nonce, [][]byte{plain})
+		})
+	}
+}
+
+func TestBox(t *testing.T) {
+	noncecheck.Reset()
+	alice := box.KeyPairSeed(testBytes(0x00, 32))
+	bob := box.KeyPairSeed(testBytes(0x20, 32))
+	nonce := testBytes(0x40, alice.NonceBytes())
+	plain := []byte("message")
+
+	cipher, _ := alice.Seal(nil, nonce, plain, bob.PublicKey())
+	if _, err := bob.Open(nil, nonce, cipher, alice.PublicKey()); err != nil {
+		t.Error("message did not open", err)
+	}
+
+	// both directions share the key.
+	expectReuse(t, "reply", func() { bob.Seal(nil, nonce, plain, alice.PublicKey()) })
+}
+
+func TestStream(t *testing.T) {
+	streams := []struct {
+		name string
+		new  func(key, nonce []byte) godium.Stream
+	}{
+		{"salsa20", stream.NewSalsa20},
+		{"xsalsa20", stream.NewXSalsa20},
+		{"chacha20", stream.NewChacha20},
+		{"chacha20ietf", stream.NewChacha20Ietf},
+		{"xchacha20", stream.NewXChacha20},
+	}
+
+	for _, v := range streams {
+		noncecheck.Reset()
+		key := testBytes(0x00, 32)
+		nonce := testBytes(0x40, 24)
+
+		s := v.new(key, testBytes(0x80, 24))
+		s.ReKey(key, nonce[:s.NonceBytes()])
+
+		expectReuse(t, v.name+" New", func() { v.new(key, nonce) })
+		expectReuse(t, v.name+" ReKey", func() { s.ReKey(key, nonce[:s.NonceBytes()]) })
+	}
+}
+
+func TestReset(t *testing.T) {
+	a := aead.NewXChacha20Poly1305Ietf(testBytes(0x00, 32))
+	nonce := testBytes(0x40, a.NPubBytes())
+
+	noncecheck.Reset()
+	a.Seal(nil, nonce, nil, nil)
+	noncecheck.Reset()
+	a.Seal(nil, nonce, nil, nil)
+
+	expectReuse(t, "after Reset", func() { a.Seal(nil, nonce, nil, nil) })
+}
@@ -7,7 +7,9 @@
 /*
 
 Package OneTimeAuth implements primitives for secret key based one-time
-authentication codes.
+authentication codes. A one-time key must only be used to authenticate a
+single message, the states in this package refuse to compute a second tag
+until they are given a new key.
 
 */
 package onetimeauth // import "go.artemisc.eu/godium/onetimeauth"
@@ -27,3 +29,17 @@ func New(key godium.Key) (a godium.OneTimeAuth) {
 	a = NewPoly1305(key)
 	return
 }
+
+// Sum appends the authentication tag of data under the one-time key to dst,
+// like crypto_onetimeauth.
+func Sum(dst, data, key []byte) (tag []byte) {
+	tag = SumPoly1305(dst, data, key)
+	return
+}
+
+// Verify checks the authentication tag of data under the one-time key in
+// constant time, like crypto_onetimeauth_verify.
+func Verify(tag, data, key []byte) (valid bool) {
+	valid = VerifyPoly1305(tag, data, key)
+	return
+}
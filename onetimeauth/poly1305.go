@@ -7,9 +7,8 @@
 package onetimeauth
 
 import (
-	"crypto/hmac"
+	"crypto/subtle"
 
-	"github.com/Yawning/poly1305"
 	"go.artemisc.eu/godium"
 )
 
@@ -18,34 +17,139 @@ const (
 	Poly1305_KeyBytes = 32
 )
 
+// Poly1305 implements the godium.OneTimeAuth interface. A key may only be used
+// to authenticate a single message, so the key is erased as soon as Sum or
+// Verify has computed a tag. Computing another tag panics until ReKey has set
+// a new key. The zero value has no key, and can be used after calling ReKey.
 type Poly1305 struct {
-	*poly1305.Poly1305
+	state poly1305State
+	buf   [Poly1305_Bytes]byte
+	nx    int
+	keyed bool
 }
 
-// NewPoly1305
+// SumPoly1305 appends the Poly1305 tag of data under the one-time key to dst,
+// like crypto_onetimeauth_poly1305. It does not allocate if dst has enough
+// capacity.
+func SumPoly1305(dst, data, key []byte) (tag []byte) {
+	var p Poly1305
+
+	p.ReKey(key)
+	p.Write(data)
+	tag = p.Sum(dst)
+	return
+}
+
+// VerifyPoly1305 checks the Poly1305 tag of data under the one-time key in
+// constant time, like crypto_onetimeauth_poly1305_verify.
+func VerifyPoly1305(tag, data, key []byte) (valid bool) {
+	var p Poly1305
+
+	p.ReKey(key)
+	p.Write(data)
+	valid = p.Verify(tag)
+	return
+}
+
+// NewPoly1305 creates a Poly1305 state for the one-time key, like
+// crypto_onetimeauth_poly1305_init. It panics if the key is not
+// Poly1305_KeyBytes long.
 func NewPoly1305(key godium.Key) (a *Poly1305) {
-	h, _ := poly1305.New(key)
-	a = &Poly1305{
-		Poly1305: h,
-	}
+	a = new(Poly1305)
+	a.ReKey(key)
 	return
 }
 
-// Wipe
+// ReKey discards the data written so far and sets a new one-time key. It
+// panics if the key is not Poly1305_KeyBytes long.
+func (p *Poly1305) ReKey(key []byte) {
+	if len(key) != Poly1305_KeyBytes {
+		panic("onetimeauth: invalid Poly1305 key length")
+	}
+
+	p.state.init(key)
+	p.Reset()
+	p.keyed = true
+}
+
+// Reset discards the data written so far. The key is kept, so a key that has
+// already produced a tag still can not be used again.
+func (p *Poly1305) Reset() {
+	p.state.h = [3]uint64{}
+	godium.Wipe(p.buf[:])
+	p.nx = 0
+}
+
+// Wipe erases the key and the state.
 func (p *Poly1305) Wipe() {
-	p.Poly1305.Clear()
+	p.state = poly1305State{}
+	godium.Wipe(p.buf[:])
+	p.nx = 0
+	p.keyed = false
 }
 
-//
-func (p *Poly1305) ReKey(key []byte) {
-	p.Poly1305.Init(key)
+// Write implements io.Writer. Complete blocks are processed directly, the
+// remainder is buffered.
+func (p *Poly1305) Write(b []byte) (n int, err error) {
+	n = len(b)
+
+	if p.nx > 0 {
+		c := copy(p.buf[p.nx:], b)
+		p.nx += c
+		b = b[c:]
+
+		if p.nx < Poly1305_Bytes {
+			return
+		}
+
+		update(&p.state, p.buf[:])
+		p.nx = 0
+	}
+
+	if c := len(b) &^ (Poly1305_Bytes - 1); c > 0 {
+		update(&p.state, b[:c])
+		b = b[c:]
+	}
+	p.nx = copy(p.buf[:], b)
+	return
 }
 
-// Verify
+// Sum appends the tag to dst, and erases the key. Unlike other hashes, Sum can
+// only be called once per key, see ReKey.
+func (p *Poly1305) Sum(dst []byte) (sum []byte) {
+	var tag [Poly1305_Bytes]byte
+
+	p.tag(&tag)
+	sum = append(dst, tag[:]...)
+	return
+}
+
+// Verify checks the tag in constant time, and erases the key. Like Sum, it can
+// only be called once per key.
 func (p *Poly1305) Verify(tag []byte) (valid bool) {
-	valid = hmac.Equal(p.Sum(nil), tag)
+	var sum [Poly1305_Bytes]byte
+
+	p.tag(&sum)
+	valid = subtle.ConstantTimeCompare(sum[:], tag) == 1
+	godium.Wipe(sum[:])
 	return
 }
 
-func (p *Poly1305) Bytes() int    { return Poly1305_Bytes }
-func (p *Poly1305) KeyBytes() int { return Poly1305_KeyBytes }
+// tag computes the tag of the data written so far, and erases the key. It
+// panics if the key was used for a tag before.
+func (p *Poly1305) tag(out *[Poly1305_Bytes]byte) {
+	if !p.keyed {
+		panic("onetimeauth: Poly1305 key reused, call ReKey first")
+	}
+
+	if p.nx > 0 {
+		update(&p.state, p.buf[:p.nx])
+	}
+	p.state.finalize(out)
+	p.Wipe()
+}
+
+func (p *Poly1305) Size() int      { return Poly1305_Bytes }
+func (p *Poly1305) BlockSize() int { return Poly1305_Bytes }
+func (p *Poly1305) Bytes() int     { return Poly1305_Bytes }
+func (p *Poly1305) KeyBytes() int  { return Poly1305_KeyBytes }
@@ -0,0 +1,15 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+package onetimeauth
+
+// update absorbs msg into the state, see updateGeneric. It only needs the
+// baseline amd64 instruction set.
+//
+//go:noescape
+func update(st *poly1305State, msg []byte)
@@ -0,0 +1,166 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Ported from golang.org/x/crypto/internal/poly1305.
+
+package onetimeauth
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	poly1305RMask0 = 0x0FFFFFFC0FFFFFFF
+	poly1305RMask1 = 0x0FFFFFFC0FFFFFFC
+
+	poly1305MaskLow2Bits    uint64 = 0x0000000000000003
+	poly1305MaskNotLow2Bits uint64 = ^poly1305MaskLow2Bits
+
+	// p = 2^130 - 5, as three 64 bit limbs.
+	poly1305P0 = 0xFFFFFFFFFFFFFFFB
+	poly1305P1 = 0xFFFFFFFFFFFFFFFF
+	poly1305P2 = 0x0000000000000003
+)
+
+// poly1305State holds the accumulator h and the key halves r and s, all as 64
+// bit limbs. The layout is shared with the assembly implementation.
+type poly1305State struct {
+	h [3]uint64
+	r [2]uint64
+	s [2]uint64
+}
+
+// init loads the key, clamping r.
+func (st *poly1305State) init(key []byte) {
+	st.h = [3]uint64{}
+	st.r[0] = binary.LittleEndian.Uint64(key[0:8]) & poly1305RMask0
+	st.r[1] = binary.LittleEndian.Uint64(key[8:16]) & poly1305RMask1
+	st.s[0] = binary.LittleEndian.Uint64(key[16:24])
+	st.s[1] = binary.LittleEndian.Uint64(key[24:32])
+}
+
+// uint128 holds a 128 bit product.
+type uint128 struct {
+	lo, hi uint64
+}
+
+func mul64(a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	return uint128{lo, hi}
+}
+
+func add128(a, b uint128) uint128 {
+	lo, c := bits.Add64(a.lo, b.lo, 0)
+	hi, c := bits.Add64(a.hi, b.hi, c)
+	if c != 0 {
+		panic("onetimeauth: unexpected Poly1305 overflow")
+	}
+	return uint128{lo, hi}
+}
+
+func shiftRightBy2(a uint128) uint128 {
+	a.lo = a.lo>>2 | (a.hi&3)<<62
+	a.hi = a.hi >> 2
+	return a
+}
+
+// updateGeneric absorbs msg into the state, as h = (h + m) * r mod 2^130 - 5
+// for every 16 byte block m. A final partial block is padded with a one byte
+// and zeros, and so it must only be passed as the last block of a message.
+func updateGeneric(st *poly1305State, msg []byte) {
+	h0, h1, h2 := st.h[0], st.h[1], st.h[2]
+	r0, r1 := st.r[0], st.r[1]
+
+	for len(msg) > 0 {
+		var c uint64
+
+		// h += m, with the 2^128 bit set for full blocks, and a one byte
+		// appended to partial blocks.
+		if len(msg) >= Poly1305_Bytes {
+			h0, c = bits.Add64(h0, binary.LittleEndian.Uint64(msg[0:8]), 0)
+			h1, c = bits.Add64(h1, binary.LittleEndian.Uint64(msg[8:16]), c)
+			h2 += c + 1
+
+			msg = msg[Poly1305_Bytes:]
+		} else {
+			var buf [Poly1305_Bytes]byte
+			copy(buf[:], msg)
+			buf[len(msg)] = 1
+
+			h0, c = bits.Add64(h0, binary.LittleEndian.Uint64(buf[0:8]), 0)
+			h1, c = bits.Add64(h1, binary.LittleEndian.Uint64(buf[8:16]), c)
+			h2 += c
+
+			msg = nil
+		}
+
+		// h *= r. h2 is at most 7, and the top 4 bits of r0 and r1 are
+		// clamped, so the products of the high limbs fit in 64 bits and the
+		// sums of the middle products do not overflow.
+		h0r0 := mul64(h0, r0)
+		h1r0 := mul64(h1, r0)
+		h2r0 := mul64(h2, r0)
+		h0r1 := mul64(h0, r1)
+		h1r1 := mul64(h1, r1)
+		h2r1 := mul64(h2, r1)
+
+		if h2r0.hi != 0 || h2r1.hi != 0 {
+			panic("onetimeauth: unexpected Poly1305 overflow")
+		}
+
+		m0 := h0r0
+		m1 := add128(h1r0, h0r1)
+		m2 := add128(h2r0, h1r1)
+		m3 := h2r1
+
+		t0 := m0.lo
+		t1, c := bits.Add64(m1.lo, m0.hi, 0)
+		t2, c := bits.Add64(m2.lo, m1.hi, c)
+		t3, _ := bits.Add64(m3.lo, m2.hi, c)
+
+		// partial reduction modulo 2^130 - 5: the bits above 2^130 are
+		// multiplied by 5 (as 4 + 1) and added back to the lower 130 bits.
+		h0, h1, h2 = t0, t1, t2&poly1305MaskLow2Bits
+		cc := uint128{t2 & poly1305MaskNotLow2Bits, t3}
+
+		h0, c = bits.Add64(h0, cc.lo, 0)
+		h1, c = bits.Add64(h1, cc.hi, c)
+		h2 += c
+
+		cc = shiftRightBy2(cc)
+
+		h0, c = bits.Add64(h0, cc.lo, 0)
+		h1, c = bits.Add64(h1, cc.hi, c)
+		h2 += c
+	}
+
+	st.h[0], st.h[1], st.h[2] = h0, h1, h2
+}
+
+// select64 returns x if v == 1 and y if v == 0, in constant time.
+func select64(v, x, y uint64) uint64 { return ^(v-1)&x | (v-1)&y }
+
+// finalize fully reduces h, adds s and writes the tag to out.
+func (st *poly1305State) finalize(out *[Poly1305_Bytes]byte) {
+	h0, h1, h2 := st.h[0], st.h[1], st.h[2]
+
+	// h is at most 2 * (2^130 - 5), so subtracting p once, unless it
+	// underflows, reduces it completely.
+	hMinusP0, b := bits.Sub64(h0, poly1305P0, 0)
+	hMinusP1, b := bits.Sub64(h1, poly1305P1, b)
+	_, b = bits.Sub64(h2, poly1305P2, b)
+
+	h0 = select64(b, h0, hMinusP0)
+	h1 = select64(b, h1, hMinusP1)
+
+	// tag = h + s mod 2^128
+	h0, c := bits.Add64(h0, st.s[0], 0)
+	h1, _ = bits.Add64(h1, st.s[1], c)
+
+	binary.LittleEndian.PutUint64(out[0:8], h0)
+	binary.LittleEndian.PutUint64(out[8:16], h1)
+}
@@ -0,0 +1,204 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package onetimeauth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// testPattern returns size bytes of input, byte i set to i % 251.
+func testPattern(size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = byte(i % 251)
+	}
+	return
+}
+
+func testKey() (key []byte) {
+	key = make([]byte, Poly1305_KeyBytes)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return
+}
+
+func TestPoly1305RFC8439(t *testing.T) {
+	// RFC 8439, section 2.5.2
+	key, _ := hex.DecodeString("85d6be7857556d337f4452fe42d506a80103808afb0db2fd4abff6af4149f51b")
+	expect, _ := hex.DecodeString("a8061dc1305136c6c22b8baf0c0127a9")
+	msg := []byte("Cryptographic Forum Research Group")
+
+	got := Sum(nil, msg, key)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+	if !Verify(expect, msg, key) {
+		t.Error("valid tag was rejected")
+	}
+}
+
+func TestPoly1305(t *testing.T) {
+	// computed with libsodium's crypto_onetimeauth
+	vectors := []struct {
+		size int
+		tag  string
+	}{
+		{0, "101112131415161718191a1b1c1d1e1f"},
+		{1, "1f11131517191b1d1f21232527292b2d"},
+		{15, "5305236ca07fc93d9ca416b23664fa50"},
+		{16, "a2291a363def0b53845fa4126a6ad364"},
+		{17, "f735c97f7308fd79222447fe76a96872"},
+		{63, "61abe275b6d2ccf0911fe932877a6643"},
+		{64, "ec478e3080abb4e797340d66c9cbc65a"},
+		{100, "2c48db4b08964d7e67950fbd89760c4d"},
+		{1000, "6e9c2f823e9a252acd5b8e324b17d738"},
+	}
+
+	key := testKey()
+	for _, v := range vectors {
+		expect, _ := hex.DecodeString(v.tag)
+		msg := testPattern(v.size)
+
+		got := Sum(nil, msg, key)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.size, expect, got)
+		}
+
+		for _, chunk := range []int{1, 7, 16, 33} {
+			p := NewPoly1305(key)
+			for i := 0; i < len(msg); i += chunk {
+				end := i + chunk
+				if end > len(msg) {
+					end = len(msg)
+				}
+				p.Write(msg[i:end])
+			}
+			got = p.Sum(nil)
+			if !bytes.Equal(expect, got) {
+				t.Error("expected result did not match computed", v.size, chunk, expect, got)
+			}
+		}
+
+		if !Verify(expect, msg, key) {
+			t.Error("valid tag was rejected", v.size)
+		}
+		expect[v.size%Poly1305_Bytes] ^= 1
+		if Verify(expect, msg, key) {
+			t.Error("forged tag was accepted", v.size)
+		}
+		if Verify(expect[:Poly1305_Bytes-1], msg, key) {
+			t.Error("truncated tag was accepted", v.size)
+		}
+	}
+}
+
+func TestPoly1305Reduction(t *testing.T) {
+	// an all ones key and message keep h close to 2^130 - 5, computed with
+	// libsodium's crypto_onetimeauth.
+	expect, _ := hex.DecodeString("c30c8c6a3af35fc6645a7e3a51df3f04")
+	key := bytes.Repeat([]byte{0xff}, Poly1305_KeyBytes)
+	msg := bytes.Repeat([]byte{0xff}, 256)
+
+	got := SumPoly1305(nil, msg, key)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestPoly1305Update(t *testing.T) {
+	key := testKey()
+	data := testPattern(1024 + 15)
+
+	for n := 0; n <= len(data); n += 37 {
+		var expect, got poly1305State
+		expect.init(key)
+		got.init(key)
+
+		updateGeneric(&expect, data[:n])
+		update(&got, data[:n])
+		if expect != got {
+			t.Error("expected result did not match computed", n, expect, got)
+		}
+	}
+}
+
+// expectPanic fails the test if f does not panic.
+func expectPanic(t *testing.T, name string, f func()) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic", name)
+		}
+	}()
+	f()
+}
+
+func TestPoly1305KeyReuse(t *testing.T) {
+	key := testKey()
+	msg := testPattern(100)
+
+	p := NewPoly1305(key)
+	p.Write(msg)
+	tag := p.Sum(nil)
+
+	expectPanic(t, "Sum", func() { p.Sum(nil) })
+	expectPanic(t, "Verify", func() { p.Verify(tag) })
+
+	p.Reset()
+	expectPanic(t, "Sum after Reset", func() { p.Sum(nil) })
+
+	p.ReKey(key)
+	p.Write(msg)
+	if !p.Verify(tag) {
+		t.Error("valid tag was rejected after ReKey")
+	}
+	expectPanic(t, "Sum after Verify", func() { p.Sum(nil) })
+
+	var zero Poly1305
+	expectPanic(t, "Sum without key", func() { zero.Sum(nil) })
+
+	p.ReKey(key)
+	p.Wipe()
+	expectPanic(t, "Sum after Wipe", func() { p.Sum(nil) })
+
+	expectPanic(t, "ReKey with short key", func() { p.ReKey(key[:16]) })
+}
+
+func TestPoly1305Allocs(t *testing.T) {
+	key := testKey()
+	msg := testPattern(100)
+	dst := make([]byte, 0, Poly1305_Bytes)
+	tag := Sum(nil, msg, key)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		Sum(dst, msg, key)
+		if !Verify(tag, msg, key) {
+			t.Error("valid tag was rejected")
+		}
+	})
+	if allocs != 0 {
+		t.Error("Sum and Verify allocated", allocs)
+	}
+}
+
+func benchmarkPoly1305(b *testing.B, size int) {
+	key := testKey()
+	msg := testPattern(size)
+	dst := make([]byte, 0, Poly1305_Bytes)
+
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Sum(dst, msg, key)
+	}
+}
+
+func BenchmarkPoly1305_64(b *testing.B) { benchmarkPoly1305(b, 64) }
+func BenchmarkPoly1305_1K(b *testing.B) { benchmarkPoly1305(b, 1024) }
+func BenchmarkPoly1305_8K(b *testing.B) { benchmarkPoly1305(b, 8192) }
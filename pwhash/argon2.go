@@ -0,0 +1,184 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package pwhash
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"fmt"
+
+	"go.artemisc.eu/godium"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// argon2Version is the only version of the Argon2 algorithm supported by
+	// libsodium, version 1.3 (0x13).
+	argon2Version = argon2.Version
+
+	// argon2StrHashBytes is the size of the hash encoded in the output of Str.
+	argon2StrHashBytes = 32
+
+	// argon2SaltBytesMin is the minimal salt size accepted by Argon2.
+	argon2SaltBytesMin = 8
+)
+
+// argon2Limits holds the parameter boundaries for one of the Argon2 variants.
+// The bounds are uint64, as the libsodium limits do not fit an int on 32-bit
+// platforms.
+type argon2Limits struct {
+	// alg is the variant identifier, and prefix the start of its hash strings.
+	alg    int
+	prefix string
+
+	// bytesMin and bytesMax bound the output length in bytes.
+	bytesMin uint64
+	bytesMax uint64
+
+	// opsLimitMin and opsLimitMax bound the number of passes.
+	opsLimitMin uint64
+	opsLimitMax uint64
+
+	// memLimitMin and memLimitMax bound the memory usage in bytes.
+	memLimitMin uint64
+	memLimitMax uint64
+
+	// saltBytes is the salt length required by Hash.
+	saltBytes int
+}
+
+// check validates the output length and cost parameters against the limits.
+func (l *argon2Limits) check(out, opslimit, memlimit uint64) (err error) {
+	if out < l.bytesMin || out > l.bytesMax ||
+		opslimit < l.opsLimitMin || opslimit > l.opsLimitMax ||
+		memlimit < l.memLimitMin || memlimit > l.memLimitMax {
+		err = ErrInvalidParams
+	}
+	return
+}
+
+// argon2Hash derives out bytes from the password and salt using the Argon2
+// variant described by the limits. The opslimit is used as the number of
+// passes, the memlimit is expressed in bytes.
+func argon2Hash(l *argon2Limits, dst, pw, salt []byte, out, opslimit, memlimit uint64, threads uint8) (h []byte, err error) {
+	var res []byte
+
+	if threads < 1 || len(salt) != l.saltBytes {
+		err = ErrInvalidParams
+		return
+	}
+
+	err = l.check(out, opslimit, memlimit)
+	if err != nil {
+		return
+	}
+
+	switch l.alg {
+	case Argon2i_Alg:
+		res = argon2.Key(pw, salt, uint32(opslimit), uint32(memlimit/1024), threads, uint32(out))
+	case Argon2id_Alg:
+		res = argon2.IDKey(pw, salt, uint32(opslimit), uint32(memlimit/1024), threads, uint32(out))
+	default:
+		err = ErrWrongAlg
+		return
+	}
+
+	h = append(dst, res...)
+	godium.Wipe(res)
+	return
+}
+
+// argon2Str generates a random salt, hashes the password and encodes the
+// parameters, salt and hash in the PHC string format used by libsodium:
+//
+//	$argon2id$v=19$m=<memory>,t=<passes>,p=<threads>$<salt>$<hash>
+func argon2Str(l *argon2Limits, dst, pw []byte, opslimit, memlimit uint64, threads uint8) (h []byte, err error) {
+	salt := make([]byte, l.saltBytes)
+
+	err = reader.Buf(salt)
+	if err != nil {
+		return
+	}
+
+	sum, err := argon2Hash(l, nil, pw, salt, argon2StrHashBytes, opslimit, memlimit, threads)
+	if err != nil {
+		return
+	}
+	defer godium.Wipe(sum)
+
+	h = append(dst, l.prefix...)
+	h = append(h, fmt.Sprintf("v=%d$m=%d,t=%d,p=%d$",
+		argon2Version, memlimit/1024, opslimit, threads)...)
+	h = encodeBase64(h, salt)
+	h = append(h, '$')
+	h = encodeBase64(h, sum)
+	return
+}
+
+// argon2StrVerify parses the parameters from a string produced by argon2Str,
+// rehashes the password with them, and compares the results in constant time.
+func argon2StrVerify(l *argon2Limits, pw, stored []byte) (err error) {
+	var version, memory, passes, threads uint64
+
+	if !bytes.HasPrefix(stored, []byte(l.prefix)) {
+		err = ErrWrongAlg
+		return
+	}
+
+	fields := bytes.Split(stored[len(l.prefix):], []byte("$"))
+	if len(fields) != 4 {
+		err = ErrInvalidParams
+		return
+	}
+
+	_, err = fmt.Sscanf(string(fields[0]), "v=%d", &version)
+	if err != nil || version != argon2Version {
+		err = ErrInvalidParams
+		return
+	}
+
+	_, err = fmt.Sscanf(string(fields[1]), "m=%d,t=%d,p=%d", &memory, &passes, &threads)
+	if err != nil || threads < 1 || threads > 255 {
+		err = ErrInvalidParams
+		return
+	}
+
+	salt, err := decodeBase64(fields[2])
+	if err != nil {
+		return
+	}
+
+	expect, err := decodeBase64(fields[3])
+	if err != nil {
+		return
+	}
+
+	sum, err := argon2HashSalt(l, pw, salt, uint64(len(expect)), passes, memory*1024, uint8(threads))
+	if err != nil {
+		return
+	}
+	defer godium.Wipe(sum)
+
+	if subtle.ConstantTimeCompare(sum, expect) != 1 {
+		err = ErrWrongPassword
+	}
+	return
+}
+
+// argon2HashSalt is like argon2Hash, but accepts any salt length, as hash
+// strings produced by other implementations are not bound to SaltBytes.
+func argon2HashSalt(l *argon2Limits, pw, salt []byte, out, opslimit, memlimit uint64, threads uint8) (h []byte, err error) {
+	if len(salt) < argon2SaltBytesMin {
+		err = ErrInvalidParams
+		return
+	}
+
+	limits := *l
+	limits.saltBytes = len(salt)
+	h, err = argon2Hash(&limits, nil, pw, salt, out, opslimit, memlimit, threads)
+	return
+}
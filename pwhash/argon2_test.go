@@ -0,0 +1,201 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package pwhash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+// argon2Variants are the constructors of the Argon2 variants, with the access
+// to their parallel functions.
+var argon2Variants = []struct {
+	name   string
+	limits *argon2Limits
+	new    func(pw []byte) argon2PwHash
+}{
+	{"argon2i", &argon2iLimits, func(pw []byte) argon2PwHash { return NewArgon2i(pw) }},
+	{"argon2id", &argon2idLimits, func(pw []byte) argon2PwHash { return NewArgon2id(pw) }},
+}
+
+// argon2PwHash is implemented by both Argon2 variants.
+type argon2PwHash interface {
+	godium.PwHash
+	HashParallel(dst, salt []byte, out, opslimit, memlimit uint64, threads uint8) (h []byte, err error)
+	StrParallel(dst []byte, opslimit, memlimit uint64, threads uint8) (h []byte, err error)
+}
+
+func TestArgon2Hash(t *testing.T) {
+	// computed with libsodium's crypto_pwhash
+	vectors := []struct {
+		alg      int
+		pw       string
+		salt     []byte
+		opslimit uint64
+		memlimit uint64
+		hash     string
+	}{
+		{Argon2i_Alg, "password", testBytes(0, 16), 3, 65536,
+			"05627f8127ace33f4d76fa5cd17fb002b861fcacfbe2945a68a966d9fda6399b"},
+		{Argon2i_Alg, "", testBytes(16, 16), 3, 8192,
+			"79acef1fe66e1b75aa91dcb6c3fff584"},
+		{Argon2i_Alg, "correct horse battery staple", testBytes(0, 16), 4, 1048576,
+			"cf150c049143363f8682c175739a9e4e7007490b389aebe916d8abfb686b1714" +
+				"5d634df1df8ffd875534550574828ff8ae5cdc6a93383bf68ee1086f81f66190"},
+		{Argon2id_Alg, "password", testBytes(0, 16), 3, 65536,
+			"b06bcf7d2d3ae0a0e060f75fc78cc0082e6196baab3e422cbe3cc0c807f76c0d"},
+		{Argon2id_Alg, "", testBytes(16, 16), 3, 8192,
+			"9afe8f6d6c7574dbb486d3d3b3b4fbf2"},
+		{Argon2id_Alg, "correct horse battery staple", testBytes(0, 16), 4, 1048576,
+			"ca3dd33512f134af4ca5befc7f0a80ff080913ce688a5520a3ae77123635ed9b" +
+				"456d912a0d1dece651b8a2d3e52a840fc041fabdae450e2611ebff75ffed80ab"},
+	}
+
+	for _, v := range vectors {
+		var ph godium.PwHash
+		if v.alg == Argon2i_Alg {
+			ph = NewArgon2i([]byte(v.pw))
+		} else {
+			ph = NewArgon2id([]byte(v.pw))
+		}
+		expect, _ := hex.DecodeString(v.hash)
+
+		got, err := ph.Hash(nil, v.salt, uint64(len(expect)), v.opslimit, v.memlimit)
+		if err != nil || !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.alg, v.pw, expect, got, err)
+		}
+	}
+}
+
+func TestArgon2StrVerify(t *testing.T) {
+	// computed with libsodium's crypto_pwhash_argon2i_str and
+	// crypto_pwhash_argon2id_str
+	vectors := []struct {
+		pw  string
+		str string
+	}{
+		{"password", "$argon2i$v=19$m=64,t=3,p=1$jCBOU1mRpElyzGXbcc7+EQ$TmJz8cilx5NDWRWLQy0T/ZqfHr89W04zvXlkQo50N0I"},
+		{"password", "$argon2id$v=19$m=64,t=2,p=1$O27rKhc8TCk9Ex0cPI0NEw$s3QSiiQZ6EFMltJod/I2tfWuQ660Dz42aAvNPjl4nh4"},
+		{"", "$argon2id$v=19$m=8,t=1,p=1$owK/d+RaH8em1qfRFpxDlg$t4cQyyO5gjXyx8zOfSp9ny3V/8508t4W4nHa8cgnxYU"},
+	}
+
+	for _, v := range vectors {
+		for _, variant := range argon2Variants {
+			if !strings.HasPrefix(v.str, variant.limits.prefix) {
+				continue
+			}
+
+			if err := variant.new([]byte(v.pw)).StrVerify([]byte(v.str)); err != nil {
+				t.Error("valid hash string was rejected", v.str, err)
+			}
+			if err := variant.new([]byte(v.pw + "x")).StrVerify([]byte(v.str)); err != ErrWrongPassword {
+				t.Error("wrong password was accepted", v.str, err)
+			}
+		}
+	}
+}
+
+func TestArgon2Str(t *testing.T) {
+	for _, v := range argon2Variants {
+		pw := v.new([]byte("password"))
+
+		for _, threads := range []uint8{1, 4} {
+			str, err := pw.StrParallel(nil, v.limits.opsLimitMin, v.limits.memLimitMin, threads)
+			if err != nil {
+				t.Fatal(v.name, err)
+			}
+			if len(str) >= pw.StrBytes() || !bytes.HasPrefix(str, []byte(pw.StrPrefix())) {
+				t.Error("unexpected hash string", v.name, string(str))
+			}
+
+			if err = pw.StrVerify(str); err != nil {
+				t.Error("hash string did not verify", v.name, threads, err)
+			}
+			if err = v.new([]byte("Password")).StrVerify(str); err != ErrWrongPassword {
+				t.Error("wrong password was accepted", v.name, threads, err)
+			}
+		}
+
+		str, _ := pw.Str(nil, v.limits.opsLimitMin, v.limits.memLimitMin)
+		other, _ := pw.Str(nil, v.limits.opsLimitMin, v.limits.memLimitMin)
+		if bytes.Equal(str, other) {
+			t.Error("hash strings share a salt", v.name)
+		}
+	}
+}
+
+func TestArgon2HashParallel(t *testing.T) {
+	for _, v := range argon2Variants {
+		pw := v.new([]byte("password"))
+		salt := testBytes(0, v.limits.saltBytes)
+
+		one, _ := pw.HashParallel(nil, salt, 32, v.limits.opsLimitMin, v.limits.memLimitMin, 1)
+		four, err := pw.HashParallel(nil, salt, 32, v.limits.opsLimitMin, v.limits.memLimitMin, 4)
+		if err != nil || len(four) != 32 || bytes.Equal(one, four) {
+			t.Error("threads did not change the hash", v.name, one, four, err)
+		}
+	}
+}
+
+func TestArgon2Invalid(t *testing.T) {
+	for _, v := range argon2Variants {
+		pw := v.new([]byte("password"))
+		salt := testBytes(0, v.limits.saltBytes)
+		ops, mem := v.limits.opsLimitMin, v.limits.memLimitMin
+
+		invalid := []struct {
+			name    string
+			salt    []byte
+			out     uint64
+			ops     uint64
+			mem     uint64
+			threads uint8
+		}{
+			{"short salt", salt[:8], 32, ops, mem, 1},
+			{"short output", salt, v.limits.bytesMin - 1, ops, mem, 1},
+			{"opslimit", salt, 32, ops - 1, mem, 1},
+			{"memlimit", salt, 32, ops, mem - 1, 1},
+			{"threads", salt, 32, ops, mem, 0},
+		}
+		for _, c := range invalid {
+			if _, err := pw.HashParallel(nil, c.salt, c.out, c.ops, c.mem, c.threads); err != ErrInvalidParams {
+				t.Error("invalid parameters were accepted", v.name, c.name, err)
+			}
+		}
+
+		if _, err := pw.StrParallel(nil, ops, mem, 0); err != ErrInvalidParams {
+			t.Error("invalid parameters were accepted", v.name, "StrParallel threads", err)
+		}
+		if _, err := pw.Str(nil, ops-1, mem); err != ErrInvalidParams {
+			t.Error("invalid parameters were accepted", v.name, "Str opslimit", err)
+		}
+
+		str, _ := pw.Str(nil, ops, mem)
+		fields := strings.Split(string(str), "$")
+		strs := []struct {
+			name string
+			str  string
+			err  error
+		}{
+			{"scrypt", "$7$C6..../....", ErrWrongAlg},
+			{"fields", strings.Join(fields[:len(fields)-1], "$"), ErrInvalidParams},
+			{"version", strings.Replace(string(str), "v=19", "v=16", 1), ErrInvalidParams},
+			{"threads", strings.Replace(string(str), "p=1", "p=0", 1), ErrInvalidParams},
+			{"memory", strings.Replace(string(str), "m=8,", "m=1,", 1), ErrInvalidParams},
+			{"salt", strings.Replace(string(str), fields[4], "!!", 1), ErrInvalidParams},
+		}
+		for _, c := range strs {
+			if err := pw.StrVerify([]byte(c.str)); err != c.err {
+				t.Error("invalid hash string was accepted", v.name, c.name, err)
+			}
+		}
+	}
+}
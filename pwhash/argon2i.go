@@ -10,37 +10,51 @@ import (
 	"math"
 
 	"go.artemisc.eu/godium"
-	"golang.org/x/crypto/argon2"
 	"go.artemisc.eu/godium/internal"
 )
 
 const (
-	Argon2i_Alg                 = 1
-	Argon2i_BytesMin            = 16
-	Argon2i_BytesMax            = math.MaxUint32
-	Argon2i_PasswdMin           = 0
-	Argon2i_PasswdMax           = 4294967295
-	Argon2i_MemLimitMin         = 8192
-	Argon2i_MemLimitMax         = 4398046510080
-	Argon2i_MemLimitInteractive = 33554432
-	Argon2i_MemLimitModerate    = 134217728
-	Argon2i_MemLimitSensitive   = 536870912
-	Argon2i_OpsLimitMin         = 3
-	Argon2i_OpsLimitMax         = 4294967295
-	Argon2i_OpsLimitInteractive = 4
-	Argon2i_OpsLimitModerate    = 6
-	Argon2i_OpsLimitSensitive   = 8
-	Argon2i_SaltBytes           = 16
-	Argon2i_StrBytes            = 128
-	Argon2i_StrPrefix           = "$argon2i$"
+	Argon2i_Alg                        = 1
+	Argon2i_BytesMin            uint64 = 16
+	Argon2i_BytesMax            uint64 = math.MaxUint32
+	Argon2i_PasswdMin           uint64 = 0
+	Argon2i_PasswdMax           uint64 = 4294967295
+	Argon2i_MemLimitMin         uint64 = 8192
+	Argon2i_MemLimitMax         uint64 = 4398046510080
+	Argon2i_MemLimitInteractive uint64 = 33554432
+	Argon2i_MemLimitModerate    uint64 = 134217728
+	Argon2i_MemLimitSensitive   uint64 = 536870912
+	Argon2i_OpsLimitMin         uint64 = 3
+	Argon2i_OpsLimitMax         uint64 = 4294967295
+	Argon2i_OpsLimitInteractive uint64 = 4
+	Argon2i_OpsLimitModerate    uint64 = 6
+	Argon2i_OpsLimitSensitive   uint64 = 8
+	Argon2i_SaltBytes                  = 16
+	Argon2i_StrBytes                   = 128
+	Argon2i_StrPrefix                  = "$argon2i$"
 )
 
-// Argon2i
+// argon2iLimits holds the Argon2i_* bounds checked by Hash, Str and StrVerify.
+// Argon2i requires at least 3 passes, unlike Argon2id.
+var argon2iLimits = argon2Limits{
+	alg:         Argon2i_Alg,
+	prefix:      Argon2i_StrPrefix,
+	bytesMin:    Argon2i_BytesMin,
+	bytesMax:    Argon2i_BytesMax,
+	opsLimitMin: Argon2i_OpsLimitMin,
+	opsLimitMax: Argon2i_OpsLimitMax,
+	memLimitMin: Argon2i_MemLimitMin,
+	memLimitMax: Argon2i_MemLimitMax,
+	saltBytes:   Argon2i_SaltBytes,
+}
+
+// Argon2i implements godium.PwHash, based on the Argon2i password hashing
+// function.
 type Argon2i struct {
 	pw []byte
 }
 
-// NewArgon2i
+// NewArgon2i creates a new instance of Argon2i with pw as the given password.
 func NewArgon2i(pw []byte) (a *Argon2i) {
 	a = &Argon2i{
 		pw: internal.Copy(pw, uint64(len(pw))),
@@ -53,77 +67,53 @@ func (pw *Argon2i) Wipe() {
 	godium.Wipe(pw.pw)
 }
 
-// pickParams
-func (pw *Argon2i) pickParams(opslimit, memlimit uint64) (time, memory uint32) {
-	panic("TODO")
-	return
-}
-
 // Hash implements godium.PwHash.
-func (pw *Argon2i) Hash(dst, salt []byte, out, opslimit, memlimit uint64) (h []byte) {
-	h = pw.HashParallel(dst, salt, out, opslimit, memlimit, 1)
+func (pw *Argon2i) Hash(dst, salt []byte, out, opslimit, memlimit uint64) (h []byte, err error) {
+	h, err = pw.HashParallel(dst, salt, out, opslimit, memlimit, 1)
 	return
 }
 
 // HashParallel functions like Hash, but accepts an additional parameter that
 // specifies the level of parallelism for the generation of the hash.
-func (pw *Argon2i) HashParallel(dst, salt []byte, out, opslimit, memlimit uint64, threads uint8) (h []byte) {
-	if threads < 1 {
-		panic("argon2i: at least 1 thread required")
-	}
-
-	time, memory := pw.pickParams(opslimit, memlimit)
-	res := argon2.Key(pw.pw, salt, time, memory, threads, uint32(out))
-	h = append(dst, res...)
-	godium.Wipe(res)
+func (pw *Argon2i) HashParallel(dst, salt []byte, out, opslimit, memlimit uint64, threads uint8) (h []byte, err error) {
+	h, err = argon2Hash(&argon2iLimits, dst, pw.pw, salt, out, opslimit, memlimit, threads)
 	return
 }
 
 // Str implements godium.PwHash.
-func (pw *Argon2i) Str(dst []byte, opslimit, memlimit uint64) (h []byte) {
-	h = pw.StrParallel(dst, opslimit, memlimit, 1)
+func (pw *Argon2i) Str(dst []byte, opslimit, memlimit uint64) (h []byte, err error) {
+	h, err = pw.StrParallel(dst, opslimit, memlimit, 1)
 	return
 }
 
 // StrParallel functions like Str, but accepts an additional parameter that
 // specifies the level of parallelism for the generation of the hash string.
-func (pw *Argon2i) StrParallel(dst []byte, opslimit, memlimit uint64, threads uint8) (h []byte) {
-	// todo generate Salt, outlen
-	h = pw.HashParallel(dst, nil, 0, opslimit, memlimit, threads)
-	// TODO turn h into a string of form $argoni$parems$base64(hash)$
-	return
-}
-
-// StrVerify implements godium.PwHash.
-func (pw *Argon2i) StrVerify(h []byte) (valid bool) {
-	valid = pw.StrVerifyParallel(h, 1)
+func (pw *Argon2i) StrParallel(dst []byte, opslimit, memlimit uint64, threads uint8) (h []byte, err error) {
+	h, err = argon2Str(&argon2iLimits, dst, pw.pw, opslimit, memlimit, threads)
 	return
 }
 
-// StrVerifyParallel functions like StrVerify, but accepts an additional
-// parameter that specifies the level of parallelism for the verification of the
-// hash.
-func (pw *Argon2i) StrVerifyParallel(h []byte, threads uint8) (valid bool) {
-	// pw.StrParallel()
-	// TODO extract salt/opslimit/memlimit from h
-	// return subtle.ConstantTimeCompare(result, h) == 1
+// StrVerify implements godium.PwHash. The level of parallelism is read from
+// the stored hash string.
+func (pw *Argon2i) StrVerify(h []byte) (err error) {
+	err = argon2StrVerify(&argon2iLimits, pw.pw, h)
 	return
 }
 
-func (pw *Argon2i) BytesMin() int            { return Argon2i_BytesMin }
-func (pw *Argon2i) BytesMax() int            { return Argon2i_BytesMax }
-func (pw *Argon2i) PasswdMin() int           { return Argon2i_PasswdMin }
-func (pw *Argon2i) PasswdMax() int           { return Argon2i_PasswdMax }
-func (pw *Argon2i) MemLimitMin() int         { return Argon2i_MemLimitMin }
-func (pw *Argon2i) MemLimitMax() int         { return Argon2i_MemLimitMax }
-func (pw *Argon2i) MemLimitInteractive() int { return Argon2i_MemLimitInteractive }
-func (pw *Argon2i) MemLimitModerate() int    { return Argon2i_MemLimitSensitive }
-func (pw *Argon2i) MemLimitSensitive() int   { return Argon2i_MemLimitSensitive }
-func (pw *Argon2i) OpsLimitMin() int         { return Argon2i_OpsLimitMin }
-func (pw *Argon2i) OpsLimitMax() int         { return Argon2i_OpsLimitMax }
-func (pw *Argon2i) OpsLimitInteractive() int { return Argon2i_OpsLimitInteractive }
-func (pw *Argon2i) OpsLimitModerate() int    { return Argon2i_OpsLimitSensitive }
-func (pw *Argon2i) OpsLimitSensitive() int   { return Argon2i_OpsLimitSensitive }
+func (pw *Argon2i) BytesMin() int            { return int(Argon2i_BytesMin) }
+func (pw *Argon2i) BytesMax() int            { return clampInt(Argon2i_BytesMax) }
+func (pw *Argon2i) PasswdMin() int           { return int(Argon2i_PasswdMin) }
+func (pw *Argon2i) PasswdMax() int           { return clampInt(Argon2i_PasswdMax) }
+func (pw *Argon2i) MemLimitMin() int         { return int(Argon2i_MemLimitMin) }
+func (pw *Argon2i) MemLimitMax() int         { return clampInt(Argon2i_MemLimitMax) }
+func (pw *Argon2i) MemLimitInteractive() int { return int(Argon2i_MemLimitInteractive) }
+func (pw *Argon2i) MemLimitModerate() int    { return int(Argon2i_MemLimitModerate) }
+func (pw *Argon2i) MemLimitSensitive() int   { return int(Argon2i_MemLimitSensitive) }
+func (pw *Argon2i) OpsLimitMin() int         { return int(Argon2i_OpsLimitMin) }
+func (pw *Argon2i) OpsLimitMax() int         { return clampInt(Argon2i_OpsLimitMax) }
+func (pw *Argon2i) OpsLimitInteractive() int { return int(Argon2i_OpsLimitInteractive) }
+func (pw *Argon2i) OpsLimitModerate() int    { return int(Argon2i_OpsLimitModerate) }
+func (pw *Argon2i) OpsLimitSensitive() int   { return int(Argon2i_OpsLimitSensitive) }
 func (pw *Argon2i) SaltBytes() int           { return Argon2i_SaltBytes }
 func (pw *Argon2i) StrBytes() int            { return Argon2i_StrBytes }
 func (pw *Argon2i) StrPrefix() string        { return Argon2i_StrPrefix }
@@ -8,47 +8,112 @@ package pwhash
 
 import (
 	"math"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
 )
 
 const (
-	Argon2id_Alg                 = 2
-	Argon2id_BytesMin            = 16
-	Argon2id_BytesMax            = math.MaxUint32
-	Argon2id_PasswdMin           = 0
-	Argon2id_PasswdMax           = 4294967295
-	Argon2id_MemLimitMin         = 8192
-	Argon2id_MemLimitMax         = 4398046510080
-	Argon2id_MemLimitInteractive = 67108864
-	Argon2id_MemLimitModerate    = 268435456
-	Argon2id_MemLimitSensitive   = 1073741824
-	Argon2id_OpsLimitMin         = 1
-	Argon2id_OpsLimitMax         = 4294967295
-	Argon2id_OpsLimitInteractive = 2
-	Argon2id_OpsLimitModerate    = 3
-	Argon2id_OpsLimitSensitive   = 4
-	Argon2id_SaltBytes           = 16
-	Argon2id_StrBytes            = 128
-	Argon2id_StrPrefix           = "$argon2id$"
+	Argon2id_Alg                        = 2
+	Argon2id_BytesMin            uint64 = 16
+	Argon2id_BytesMax            uint64 = math.MaxUint32
+	Argon2id_PasswdMin           uint64 = 0
+	Argon2id_PasswdMax           uint64 = 4294967295
+	Argon2id_MemLimitMin         uint64 = 8192
+	Argon2id_MemLimitMax         uint64 = 4398046510080
+	Argon2id_MemLimitInteractive uint64 = 67108864
+	Argon2id_MemLimitModerate    uint64 = 268435456
+	Argon2id_MemLimitSensitive   uint64 = 1073741824
+	Argon2id_OpsLimitMin         uint64 = 1
+	Argon2id_OpsLimitMax         uint64 = 4294967295
+	Argon2id_OpsLimitInteractive uint64 = 2
+	Argon2id_OpsLimitModerate    uint64 = 3
+	Argon2id_OpsLimitSensitive   uint64 = 4
+	Argon2id_SaltBytes                  = 16
+	Argon2id_StrBytes                   = 128
+	Argon2id_StrPrefix                  = "$argon2id$"
 )
 
+// argon2idLimits holds the Argon2id_* bounds checked by Hash, Str and
+// StrVerify.
+var argon2idLimits = argon2Limits{
+	alg:         Argon2id_Alg,
+	prefix:      Argon2id_StrPrefix,
+	bytesMin:    Argon2id_BytesMin,
+	bytesMax:    Argon2id_BytesMax,
+	opsLimitMin: Argon2id_OpsLimitMin,
+	opsLimitMax: Argon2id_OpsLimitMax,
+	memLimitMin: Argon2id_MemLimitMin,
+	memLimitMax: Argon2id_MemLimitMax,
+	saltBytes:   Argon2id_SaltBytes,
+}
+
+// Argon2id implements godium.PwHash, based on the Argon2id password hashing
+// function.
 type Argon2id struct {
 	pw []byte
 }
 
-func (pw *Argon2id) BytesMin() int            { return Argon2id_BytesMin }
-func (pw *Argon2id) BytesMax() int            { return Argon2id_BytesMax }
-func (pw *Argon2id) PasswdMin() int           { return Argon2id_PasswdMin }
-func (pw *Argon2id) PasswdMax() int           { return Argon2id_PasswdMax }
-func (pw *Argon2id) MemLimitMin() int         { return Argon2id_MemLimitMin }
-func (pw *Argon2id) MemLimitMax() int         { return Argon2id_MemLimitMax }
-func (pw *Argon2id) MemLimitInteractive() int { return Argon2id_MemLimitInteractive }
-func (pw *Argon2id) MemLimitModerate() int    { return Argon2id_MemLimitSensitive }
-func (pw *Argon2id) MemLimitSensitive() int   { return Argon2id_MemLimitSensitive }
-func (pw *Argon2id) OpsLimitMin() int         { return Argon2id_OpsLimitMin }
-func (pw *Argon2id) OpsLimitMax() int         { return Argon2id_OpsLimitMax }
-func (pw *Argon2id) OpsLimitInteractive() int { return Argon2id_OpsLimitInteractive }
-func (pw *Argon2id) OpsLimitModerate() int    { return Argon2id_OpsLimitSensitive }
-func (pw *Argon2id) OpsLimitSensitive() int   { return Argon2id_OpsLimitSensitive }
+// NewArgon2id creates a new instance of Argon2id with pw as the given password.
+func NewArgon2id(pw []byte) (a *Argon2id) {
+	a = &Argon2id{
+		pw: internal.Copy(pw, uint64(len(pw))),
+	}
+	return
+}
+
+// Wipe implements godium.PwHash.
+func (pw *Argon2id) Wipe() {
+	godium.Wipe(pw.pw)
+}
+
+// Hash implements godium.PwHash.
+func (pw *Argon2id) Hash(dst, salt []byte, out, opslimit, memlimit uint64) (h []byte, err error) {
+	h, err = pw.HashParallel(dst, salt, out, opslimit, memlimit, 1)
+	return
+}
+
+// HashParallel functions like Hash, but accepts an additional parameter that
+// specifies the level of parallelism for the generation of the hash.
+func (pw *Argon2id) HashParallel(dst, salt []byte, out, opslimit, memlimit uint64, threads uint8) (h []byte, err error) {
+	h, err = argon2Hash(&argon2idLimits, dst, pw.pw, salt, out, opslimit, memlimit, threads)
+	return
+}
+
+// Str implements godium.PwHash.
+func (pw *Argon2id) Str(dst []byte, opslimit, memlimit uint64) (h []byte, err error) {
+	h, err = pw.StrParallel(dst, opslimit, memlimit, 1)
+	return
+}
+
+// StrParallel functions like Str, but accepts an additional parameter that
+// specifies the level of parallelism for the generation of the hash string.
+func (pw *Argon2id) StrParallel(dst []byte, opslimit, memlimit uint64, threads uint8) (h []byte, err error) {
+	h, err = argon2Str(&argon2idLimits, dst, pw.pw, opslimit, memlimit, threads)
+	return
+}
+
+// StrVerify implements godium.PwHash. The level of parallelism is read from
+// the stored hash string.
+func (pw *Argon2id) StrVerify(h []byte) (err error) {
+	err = argon2StrVerify(&argon2idLimits, pw.pw, h)
+	return
+}
+
+func (pw *Argon2id) BytesMin() int            { return int(Argon2id_BytesMin) }
+func (pw *Argon2id) BytesMax() int            { return clampInt(Argon2id_BytesMax) }
+func (pw *Argon2id) PasswdMin() int           { return int(Argon2id_PasswdMin) }
+func (pw *Argon2id) PasswdMax() int           { return clampInt(Argon2id_PasswdMax) }
+func (pw *Argon2id) MemLimitMin() int         { return int(Argon2id_MemLimitMin) }
+func (pw *Argon2id) MemLimitMax() int         { return clampInt(Argon2id_MemLimitMax) }
+func (pw *Argon2id) MemLimitInteractive() int { return int(Argon2id_MemLimitInteractive) }
+func (pw *Argon2id) MemLimitModerate() int    { return int(Argon2id_MemLimitModerate) }
+func (pw *Argon2id) MemLimitSensitive() int   { return int(Argon2id_MemLimitSensitive) }
+func (pw *Argon2id) OpsLimitMin() int         { return int(Argon2id_OpsLimitMin) }
+func (pw *Argon2id) OpsLimitMax() int         { return clampInt(Argon2id_OpsLimitMax) }
+func (pw *Argon2id) OpsLimitInteractive() int { return int(Argon2id_OpsLimitInteractive) }
+func (pw *Argon2id) OpsLimitModerate() int    { return int(Argon2id_OpsLimitModerate) }
+func (pw *Argon2id) OpsLimitSensitive() int   { return int(Argon2id_OpsLimitSensitive) }
 func (pw *Argon2id) SaltBytes() int           { return Argon2id_SaltBytes }
 func (pw *Argon2id) StrBytes() int            { return Argon2id_StrBytes }
 func (pw *Argon2id) StrPrefix() string        { return Argon2id_StrPrefix }
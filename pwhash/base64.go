@@ -6,4 +6,78 @@
 
 package pwhash
 
-// todo implementation of base64 encoding/decoding functions as used for scrypt/argon2
+import (
+	"bytes"
+	"encoding/base64"
+)
+
+// cryptItoa64 is the alphabet of the little endian base64 encoding used in
+// the scrypt hash strings of libsodium.
+const cryptItoa64 = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// encodeBase64 appends the unpadded standard base64 encoding of bin to dst, as
+// used in the Argon2 hash strings.
+func encodeBase64(dst, bin []byte) (txt []byte) {
+	n := base64.RawStdEncoding.EncodedLen(len(bin))
+	txt = append(dst, make([]byte, n)...)
+	base64.RawStdEncoding.Encode(txt[len(dst):], bin)
+	return
+}
+
+// decodeBase64 decodes the unpadded standard base64 encoded txt.
+func decodeBase64(txt []byte) (bin []byte, err error) {
+	bin = make([]byte, base64.RawStdEncoding.DecodedLen(len(txt)))
+	n, err := base64.RawStdEncoding.Decode(bin, txt)
+	if err != nil {
+		err = ErrInvalidParams
+		return
+	}
+	bin = bin[:n]
+	return
+}
+
+// encodeCryptUint32 appends the low bits of v to dst, 6 bits per character,
+// least significant first.
+func encodeCryptUint32(dst []byte, v uint32, bits uint) (txt []byte) {
+	txt = dst
+	for bit := uint(0); bit < bits; bit += 6 {
+		txt = append(txt, cryptItoa64[v&0x3f])
+		v >>= 6
+	}
+	return
+}
+
+// decodeCryptUint32 decodes the bits/6 characters of txt produced by
+// encodeCryptUint32.
+func decodeCryptUint32(txt []byte, bits uint) (v uint32, ok bool) {
+	if uint(len(txt))*6 < bits {
+		return
+	}
+
+	for i, bit := 0, uint(0); bit < bits; i, bit = i+1, bit+6 {
+		c := bytes.IndexByte([]byte(cryptItoa64), txt[i])
+		if c < 0 {
+			return
+		}
+		v |= uint32(c) << bit
+	}
+	ok = true
+	return
+}
+
+// encodeCrypt appends the encoding of bin to dst, in groups of up to 3 bytes
+// like encode64 of libsodium's escrypt.
+func encodeCrypt(dst, bin []byte) (txt []byte) {
+	txt = dst
+	for i := 0; i < len(bin); {
+		var v uint32
+		var bits uint
+
+		for ; bits < 24 && i < len(bin); bits += 8 {
+			v |= uint32(bin[i]) << bits
+			i++
+		}
+		txt = encodeCryptUint32(txt, v, bits)
+	}
+	return
+}
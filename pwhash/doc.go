@@ -18,38 +18,53 @@ const (
 	AlgArgon2i          = Argon2i_Alg
 	AlgArgon2id         = Argon2id_Alg
 	AlgDefault          = AlgArgon2id
-	BytesMin            = Argon2i_BytesMin
-	BytesMax            = Argon2i_BytesMax
-	PasswdMin           = Argon2i_PasswdMin
-	PasswdMax           = Argon2i_PasswdMax
-	MemLimitMin         = Argon2i_MemLimitMin
-	MemLimitMax         = Argon2i_MemLimitMax
-	MemLimitInteractive = Argon2i_MemLimitInteractive
-	MemLimitModerate    = Argon2i_MemLimitModerate
-	MemLimitSensitive   = Argon2i_MemLimitSensitive
-	OpsLimitMin         = Argon2i_OpsLimitMin
-	OpsLimitMax         = Argon2i_OpsLimitMax
-	OpsLimitInteractive = Argon2i_OpsLimitInteractive
-	OpsLimitModerate    = Argon2i_OpsLimitModerate
-	OpsLimitSensitive   = Argon2i_OpsLimitSensitive
-	SaltBytes           = Argon2i_SaltBytes
-	StrBytes            = Argon2i_StrBytes
-	StrPrefix           = Argon2i_StrPrefix
+	BytesMin            = Argon2id_BytesMin
+	BytesMax            = Argon2id_BytesMax
+	PasswdMin           = Argon2id_PasswdMin
+	PasswdMax           = Argon2id_PasswdMax
+	MemLimitMin         = Argon2id_MemLimitMin
+	MemLimitMax         = Argon2id_MemLimitMax
+	MemLimitInteractive = Argon2id_MemLimitInteractive
+	MemLimitModerate    = Argon2id_MemLimitModerate
+	MemLimitSensitive   = Argon2id_MemLimitSensitive
+	OpsLimitMin         = Argon2id_OpsLimitMin
+	OpsLimitMax         = Argon2id_OpsLimitMax
+	OpsLimitInteractive = Argon2id_OpsLimitInteractive
+	OpsLimitModerate    = Argon2id_OpsLimitModerate
+	OpsLimitSensitive   = Argon2id_OpsLimitSensitive
+	SaltBytes           = Argon2id_SaltBytes
+	StrBytes            = Argon2id_StrBytes
+	StrPrefix           = Argon2id_StrPrefix
 )
 
-//
 var (
 	ErrWrongAlg      = errors.New("wrong algorithm identifier found")
 	ErrWrongPassword = errors.New("wrong password entered")
+	ErrInvalidParams = errors.New("invalid or out of range hash parameters")
 )
 
 var (
 	reader = random.New()
 )
 
+// maxInt is the largest value the limit methods of godium.PwHash can return.
+const maxInt = int(^uint(0) >> 1)
+
+// clampInt converts a uint64 limit to the int returned by godium.PwHash. Like
+// the SIZE_MAX bound on the libsodium limits, values that do not fit an int on
+// 32-bit platforms are clamped to maxInt.
+func clampInt(v uint64) (c int) {
+	if v > uint64(maxInt) {
+		c = maxInt
+		return
+	}
+	c = int(v)
+	return
+}
+
 // New
 func New(pw []byte) (ph godium.PwHash) {
-	panic("Argon2i not yet implemented")
+	ph = NewArgon2id(pw)
 	return
 }
 
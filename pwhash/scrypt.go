@@ -11,28 +11,34 @@ import (
 	"crypto/subtle"
 	"math"
 
-	//"golang.org/x/crypto/scrypt"
-
 	"go.artemisc.eu/godium"
 	"go.artemisc.eu/godium/internal"
+	"golang.org/x/crypto/scrypt"
 )
 
 const (
-	Scrypt_BytesMin            = 16
-	Scrypt_BytesMax            = 0x1fffffffe0
-	Scrypt_PasswdMin           = 0
-	Scrypt_PasswdMax           = math.MaxUint32
-	Scrypt_MemLimitMin         = 16777216
-	Scrypt_MemLimitMax         = 68719476736
-	Scrypt_MemLimitInteractive = 16777216
-	Scrypt_MemLimitSensitive   = 1073741824
-	Scrypt_OpsLimitMin         = 32768
-	Scrypt_OpsLimitMax         = 4294967295
-	Scrypt_OpsLimitInteractive = 524288
-	Scrypt_OpsLimitSensitive   = 33554432
-	Scrypt_SaltBytes           = 32
-	Scrypt_StrBytes            = 102
-	Scrypt_StrPrefix           = "$7$"
+	Scrypt_BytesMin            uint64 = 16
+	Scrypt_BytesMax            uint64 = 0x1fffffffe0
+	Scrypt_PasswdMin           uint64 = 0
+	Scrypt_PasswdMax           uint64 = math.MaxUint32
+	Scrypt_MemLimitMin         uint64 = 16777216
+	Scrypt_MemLimitMax         uint64 = 68719476736
+	Scrypt_MemLimitInteractive uint64 = 16777216
+	Scrypt_MemLimitSensitive   uint64 = 1073741824
+	Scrypt_OpsLimitMin         uint64 = 32768
+	Scrypt_OpsLimitMax         uint64 = 4294967295
+	Scrypt_OpsLimitInteractive uint64 = 524288
+	Scrypt_OpsLimitSensitive   uint64 = 33554432
+	Scrypt_SaltBytes                  = 32
+	Scrypt_StrBytes                   = 102
+	Scrypt_StrPrefix                  = "$7$"
+
+	// scryptStrHashBytes is the size of the hash encoded in the output of Str.
+	scryptStrHashBytes = 32
+
+	// scryptSettingBytes is the length of the prefix, parameters and encoded
+	// salt at the start of a hash string.
+	scryptSettingBytes = len(Scrypt_StrPrefix) + 1 + 5 + 5 + (Scrypt_SaltBytes*8+5)/6
 )
 
 // scryptStrPrefix is the []byte copy of the Scrypt_StrPrefix constant for
@@ -99,70 +105,137 @@ func (pw *Scrypt) pickParams(opslimit, memlimit uint64) (NLog2, p, r uint64) {
 	return
 }
 
-// extractParams will extract the
-func (pw *Scrypt) extractParams(stored []byte) (opslimit, memlimit uint64, err error) {
-	if !bytes.HasPrefix(stored, scryptStrPrefix) {
-		err = ErrWrongAlg
+// Hash implements godium.PwHash, like crypto_pwhash_scryptsalsa208sha256. The
+// salt must be Scrypt_SaltBytes long.
+func (pw *Scrypt) Hash(dst, salt []byte, out, opslimit, memlimit uint64) (h []byte, err error) {
+	if len(salt) != Scrypt_SaltBytes || out < Scrypt_BytesMin || out > Scrypt_BytesMax {
+		err = ErrInvalidParams
 		return
 	}
 
+	nLog2, p, r := pw.pickParams(opslimit, memlimit)
+	h, err = scryptHash(dst, pw.pw, salt, nLog2, p, r, out)
 	return
 }
 
-// Hash
-func (pw *Scrypt) Hash(dst, salt []byte, out, opslimit, memlimit uint64) (h []byte, err error) {
-	return
-}
-
-// Str
+// Str implements godium.PwHash, like crypto_pwhash_scryptsalsa208sha256_str.
+// The hash string has the form:
+//
+//	$7$<N><r><p><salt>$<hash>
+//
+// The salt is random, and it is used in its encoded form to compute the hash.
 func (pw *Scrypt) Str(dst []byte, opslimit, memlimit uint64) (h []byte, err error) {
-	h = internal.AllocDst(dst, Scrypt_StrBytes)[:0]
-	salt := make([]byte, Scrypt_SaltBytes)
+	var salt [Scrypt_SaltBytes]byte
 
-	err = reader.Buf(salt)
+	err = reader.Buf(salt[:])
 	if err != nil {
 		return
 	}
 
-	//nlog2, p, r := pw.pickParams(opslimit, memlimit)
-	//key, _ := scrypt.Key(pw.pw, salt, 1<<nlog2, r, p, keylen)
+	nLog2, p, r := pw.pickParams(opslimit, memlimit)
+
+	setting := append(make([]byte, 0, scryptSettingBytes), scryptStrPrefix...)
+	setting = append(setting, cryptItoa64[nLog2])
+	setting = encodeCryptUint32(setting, uint32(r), 30)
+	setting = encodeCryptUint32(setting, uint32(p), 30)
+	setting = encodeCrypt(setting, salt[:])
 
+	h, err = pw.str(dst, setting)
 	return
 }
 
-// StrVerify
+// StrVerify implements godium.PwHash, like
+// crypto_pwhash_scryptsalsa208sha256_str_verify.
 func (pw *Scrypt) StrVerify(stored []byte) (err error) {
-	opsLimit, memLimit, err := pw.extractParams(stored)
-	if err != nil {
+	if !bytes.HasPrefix(stored, scryptStrPrefix) {
+		err = ErrWrongAlg
+		return
+	}
+	if len(stored) != Scrypt_StrBytes-1 {
+		err = ErrInvalidParams
 		return
 	}
 
-	h, err := pw.Str(make([]byte, 0, Scrypt_StrBytes), opsLimit, memLimit)
+	h, err := pw.str(make([]byte, 0, Scrypt_StrBytes-1), stored[:scryptSettingBytes])
 	if err != nil {
 		return
 	}
+	defer godium.Wipe(h)
 
-	if subtle.ConstantTimeCompare(stored, h) != 0 {
+	if subtle.ConstantTimeCompare(stored, h) != 1 {
 		err = ErrWrongPassword
 	}
+	return
+}
+
+// str hashes the password with the parameters and salt encoded in setting, and
+// appends the setting, a '$' and the encoded hash to dst.
+func (pw *Scrypt) str(dst, setting []byte) (h []byte, err error) {
+	var nLog2, r, p uint32
+
+	if len(setting) != scryptSettingBytes || !bytes.HasPrefix(setting, scryptStrPrefix) {
+		err = ErrInvalidParams
+		return
+	}
+
+	params := setting[len(scryptStrPrefix):]
+	nLog2, ok := decodeCryptUint32(params[:1], 6)
+	if ok {
+		r, ok = decodeCryptUint32(params[1:6], 30)
+	}
+	if ok {
+		p, ok = decodeCryptUint32(params[6:11], 30)
+	}
+	if !ok {
+		err = ErrInvalidParams
+		return
+	}
+
+	sum, err := scryptHash(nil, pw.pw, params[11:], uint64(nLog2), uint64(p), uint64(r), scryptStrHashBytes)
+	if err != nil {
+		return
+	}
+	defer godium.Wipe(sum)
+
+	h = append(dst, setting...)
+	h = append(h, '$')
+	h = encodeCrypt(h, sum)
+	return
+}
+
+// scryptHash derives out bytes from the password and salt with scrypt, using
+// N = 2^nLog2, and appends them to dst.
+func scryptHash(dst, pw, salt []byte, nLog2, p, r, out uint64) (h []byte, err error) {
+	if nLog2 < 1 || nLog2 > 31 || r == 0 || p == 0 || r*p >= 1<<30 {
+		err = ErrInvalidParams
+		return
+	}
+
+	res, err := scrypt.Key(pw, salt, 1<<nLog2, int(r), int(p), int(out))
+	if err != nil {
+		err = ErrInvalidParams
+		return
+	}
 
+	h = append(dst, res...)
+	godium.Wipe(res)
 	return
 }
 
-func (pw *Scrypt) BytesMin() int            { return Scrypt_BytesMin }
-func (pw *Scrypt) BytesMax() int            { return Scrypt_BytesMax }
-func (pw *Scrypt) PasswdMin() int           { return Scrypt_PasswdMin }
-func (pw *Scrypt) PasswdMax() int           { return Scrypt_PasswdMax }
-func (pw *Scrypt) MemLimitMin() int         { return Scrypt_MemLimitMin }
-func (pw *Scrypt) MemLimitMax() int         { return Scrypt_MemLimitMax }
-func (pw *Scrypt) MemLimitInteractive() int { return Scrypt_MemLimitInteractive }
-func (pw *Scrypt) MemLimitModerate() int    { return Scrypt_MemLimitSensitive }
-func (pw *Scrypt) MemLimitSensitive() int   { return Scrypt_MemLimitSensitive }
-func (pw *Scrypt) OpsLimitMin() int         { return Scrypt_OpsLimitMin }
-func (pw *Scrypt) OpsLimitMax() int         { return Scrypt_OpsLimitMax }
-func (pw *Scrypt) OpsLimitInteractive() int { return Scrypt_OpsLimitInteractive }
-func (pw *Scrypt) OpsLimitModerate() int    { return Scrypt_OpsLimitSensitive }
-func (pw *Scrypt) OpsLimitSensitive() int   { return Scrypt_OpsLimitSensitive }
+func (pw *Scrypt) BytesMin() int            { return int(Scrypt_BytesMin) }
+func (pw *Scrypt) BytesMax() int            { return clampInt(Scrypt_BytesMax) }
+func (pw *Scrypt) PasswdMin() int           { return int(Scrypt_PasswdMin) }
+func (pw *Scrypt) PasswdMax() int           { return clampInt(Scrypt_PasswdMax) }
+func (pw *Scrypt) MemLimitMin() int         { return int(Scrypt_MemLimitMin) }
+func (pw *Scrypt) MemLimitMax() int         { return clampInt(Scrypt_MemLimitMax) }
+func (pw *Scrypt) MemLimitInteractive() int { return int(Scrypt_MemLimitInteractive) }
+func (pw *Scrypt) MemLimitModerate() int    { return int(Scrypt_MemLimitSensitive) }
+func (pw *Scrypt) MemLimitSensitive() int   { return int(Scrypt_MemLimitSensitive) }
+func (pw *Scrypt) OpsLimitMin() int         { return int(Scrypt_OpsLimitMin) }
+func (pw *Scrypt) OpsLimitMax() int         { return clampInt(Scrypt_OpsLimitMax) }
+func (pw *Scrypt) OpsLimitInteractive() int { return int(Scrypt_OpsLimitInteractive) }
+func (pw *Scrypt) OpsLimitModerate() int    { return int(Scrypt_OpsLimitSensitive) }
+func (pw *Scrypt) OpsLimitSensitive() int   { return int(Scrypt_OpsLimitSensitive) }
 func (pw *Scrypt) SaltBytes() int           { return Scrypt_SaltBytes }
 func (pw *Scrypt) StrBytes() int            { return Scrypt_StrBytes }
 func (pw *Scrypt) StrPrefix() string        { return Scrypt_StrPrefix }
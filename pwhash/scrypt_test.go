@@ -0,0 +1,126 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package pwhash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// testBytes returns size bytes counting up from start.
+func testBytes(start byte, size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = start + byte(i)
+	}
+	return
+}
+
+func TestScryptHash(t *testing.T) {
+	// computed with libsodium's crypto_pwhash_scryptsalsa208sha256
+	vectors := []struct {
+		pw       string
+		salt     []byte
+		opslimit uint64
+		memlimit uint64
+		hash     string
+	}{
+		{"password", testBytes(0, 32), 524288, 16777216,
+			"42fa366219ca6b8997059907a4eb416cf8d911749572dfbcd167084b5807f524"},
+		{"", testBytes(0, 32), 32768, 16777216,
+			"2b5867aa88658362a424233cb13abafb"},
+		{"correct horse", testBytes(32, 32), 1000000, 8388608,
+			"fbb3e5ecb6a8140fe3de6939eb6de16b9f5cbd48f8b5cd53ea730adce6797f7c" +
+				"1aa9faeb5ce0d3943010e262b0f6e05afc8ceda187b55383f507bbafd0b8053a"},
+	}
+
+	for _, v := range vectors {
+		expect, _ := hex.DecodeString(v.hash)
+
+		got, err := NewScrypt([]byte(v.pw)).Hash(nil, v.salt, uint64(len(expect)), v.opslimit, v.memlimit)
+		if err != nil || !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.pw, expect, got, err)
+		}
+	}
+}
+
+func TestScryptStrVerify(t *testing.T) {
+	// computed with libsodium's crypto_pwhash_scryptsalsa208sha256_str
+	vectors := []struct {
+		pw  string
+		str string
+	}{
+		{"password", "$7$C6..../....Gnen9OfSc6dNqw58dg/INUekVU411CU3eC4pWsykKT2$3sFpnbaGL1UkzvT0DmoqvhMjrqmJDc3tgoEp1KMqLV/"},
+		{"", "$7$86..../....iaGUpoZaHUfnegpkb6lHM9bINzrUADjsqvkXns2PA16$i4M4PawmGqXenIndX6S18NXC2ERrNvM6o7c8hMlbkHC"},
+	}
+
+	for _, v := range vectors {
+		if err := NewScrypt([]byte(v.pw)).StrVerify([]byte(v.str)); err != nil {
+			t.Error("valid hash string was rejected", v.pw, err)
+		}
+		if err := NewScrypt([]byte(v.pw + "x")).StrVerify([]byte(v.str)); err != ErrWrongPassword {
+			t.Error("wrong password was accepted", v.pw, err)
+		}
+
+		forged := []byte(v.str)
+		forged[len(forged)-1] ^= 1
+		if err := NewScrypt([]byte(v.pw)).StrVerify(forged); err != ErrWrongPassword {
+			t.Error("forged hash string was accepted", v.pw, err)
+		}
+	}
+}
+
+func TestScryptStr(t *testing.T) {
+	pw := NewScrypt([]byte("password"))
+
+	str, err := pw.Str(nil, Scrypt_OpsLimitInteractive, Scrypt_MemLimitInteractive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(str) != Scrypt_StrBytes-1 || !bytes.HasPrefix(str, []byte(Scrypt_StrPrefix)) {
+		t.Error("unexpected hash string", string(str))
+	}
+	if err = pw.StrVerify(str); err != nil {
+		t.Error("hash string did not verify", err)
+	}
+
+	other, _ := pw.Str(nil, Scrypt_OpsLimitInteractive, Scrypt_MemLimitInteractive)
+	if bytes.Equal(str, other) {
+		t.Error("hash strings share a salt")
+	}
+}
+
+func TestScryptInvalid(t *testing.T) {
+	pw := NewScrypt([]byte("password"))
+	valid := "$7$86..../....iaGUpoZaHUfnegpkb6lHM9bINzrUADjsqvkXns2PA16$i4M4PawmGqXenIndX6S18NXC2ERrNvM6o7c8hMlbkHC"
+
+	if _, err := pw.Hash(nil, testBytes(0, 16), 32, Scrypt_OpsLimitMin, Scrypt_MemLimitMin); err != ErrInvalidParams {
+		t.Error("short salt was accepted", err)
+	}
+	if _, err := pw.Hash(nil, testBytes(0, 32), Scrypt_BytesMin-1, Scrypt_OpsLimitMin, Scrypt_MemLimitMin); err != ErrInvalidParams {
+		t.Error("short output was accepted", err)
+	}
+
+	invalid := []struct {
+		name string
+		str  string
+		err  error
+	}{
+		{"argon2", "$argon2id$v=19$m=65536,t=2,p=1$c2FsdHNhbHRzYWx0c2FsdA$aGFzaA", ErrWrongAlg},
+		{"truncated", valid[:len(valid)-1], ErrInvalidParams},
+		{"N", valid[:3] + "~" + valid[4:], ErrInvalidParams},
+		{"N too large", valid[:3] + "U" + valid[4:], ErrInvalidParams},
+		{"r", valid[:4] + "!" + valid[5:], ErrInvalidParams},
+		{"r is zero", valid[:4] + "....." + valid[9:], ErrInvalidParams},
+	}
+	for _, v := range invalid {
+		if err := pw.StrVerify([]byte(v.str)); err != v.err {
+			t.Error("invalid hash string was accepted", v.name, err)
+		}
+	}
+}
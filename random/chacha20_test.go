@@ -0,0 +1,117 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package random
+
+import (
+	"bytes"
+	"testing"
+	"testing/iotest"
+
+	"go.artemisc.eu/godium"
+)
+
+func TestBufShortReads(t *testing.T) {
+	expect := []byte("0123456789abcdef0123456789abcdef")
+	got := make([]byte, len(expect))
+
+	rnd := NewFrom(iotest.OneByteReader(bytes.NewReader(expect)))
+	if err := rnd.Buf(got); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestChacha20Sizes(t *testing.T) {
+	rnd := NewChacha20()
+	zero := make([]byte, 32)
+
+	for _, size := range []int{1, 31, 32, chacha20BufBytes - 1, chacha20BufBytes,
+		chacha20BufBytes + 1, 3 * chacha20ChunkBytes / 2} {
+		a := make([]byte, size)
+		b := make([]byte, size)
+
+		if err := rnd.Buf(a); err != nil {
+			t.Error("unexpected error", err)
+		}
+		if err := rnd.Buf(b); err != nil {
+			t.Error("unexpected error", err)
+		}
+
+		if size >= 32 && (bytes.Equal(a, b) ||
+			bytes.Equal(a[size-32:], zero) || bytes.Equal(b[size-32:], zero)) {
+			t.Error("generator repeated or skipped output", size)
+		}
+	}
+}
+
+func TestChacha20Erasure(t *testing.T) {
+	g := chacha20Pool.New().(*chacha20State)
+	out := make([]byte, 100)
+
+	if _, err := g.read(out); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	if !bytes.Equal(g.buf[:g.off], make([]byte, g.off)) {
+		t.Error("released output was not erased from the buffer")
+	}
+}
+
+func benchmarkBuf(b *testing.B, rnd godium.Random, size int) {
+	buf := make([]byte, size)
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = rnd.Buf(buf)
+	}
+}
+
+func benchmarkBufParallel(b *testing.B, rnd godium.Random, size int) {
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		buf := make([]byte, size)
+		for pb.Next() {
+			_ = rnd.Buf(buf)
+		}
+	})
+}
+
+func BenchmarkSystemBuf16(b *testing.B)  { benchmarkBuf(b, New(), 16) }
+func BenchmarkSystemBuf1K(b *testing.B)  { benchmarkBuf(b, New(), 1024) }
+func BenchmarkSystemBuf64K(b *testing.B) { benchmarkBuf(b, New(), 65536) }
+
+func BenchmarkSystemBufParallel16(b *testing.B) { benchmarkBufParallel(b, New(), 16) }
+
+func BenchmarkSystemUInt64(b *testing.B) {
+	rnd := New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = rnd.UInt64()
+	}
+}
+
+func BenchmarkChacha20Buf16(b *testing.B)  { benchmarkBuf(b, NewChacha20(), 16) }
+func BenchmarkChacha20Buf1K(b *testing.B)  { benchmarkBuf(b, NewChacha20(), 1024) }
+func BenchmarkChacha20Buf64K(b *testing.B) { benchmarkBuf(b, NewChacha20(), 65536) }
+
+func BenchmarkChacha20BufParallel16(b *testing.B) { benchmarkBufParallel(b, NewChacha20(), 16) }
+
+func BenchmarkChacha20UInt64(b *testing.B) {
+	rnd := NewChacha20()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = rnd.UInt64()
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package random
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/stream"
+)
+
+const (
+	// SeedBytes is the size of the seed used by the deterministic generator.
+	SeedBytes = stream.Chacha20Ietf_KeyBytes
+
+	// DeterministicBytesMax is the maximal number of bytes a deterministic
+	// generator can produce, as the ChaCha20-IETF block counter is limited to
+	// 32 bits.
+	DeterministicBytesMax = (1 << 32) * stream.Chacha20Ietf_BlockBytes
+)
+
+// deterministicNonce is the nonce used by libsodium's
+// randombytes_buf_deterministic.
+var deterministicNonce = [stream.Chacha20Ietf_NonceBytes]byte{
+	'L', 'i', 'b', 's', 'o', 'd', 'i', 'u', 'm', 'D', 'R', 'G',
+}
+
+// deterministic is an io.Reader producing the ChaCha20-IETF keystream for the
+// seed and the deterministicNonce.
+type deterministic struct {
+	stream godium.Stream
+	read   uint64
+}
+
+// NewDeterministic creates a godium.Random that produces the same sequence of
+// bytes for the same seed. The sequence is equal to the output of libsodium's
+// randombytes_buf_deterministic, continued across calls: reading 16 bytes twice
+// gives the same bytes as reading 32 bytes once.
+//
+// At most DeterministicBytesMax bytes can be read, after which Buf and Read
+// return ErrExhausted. NewDeterministic panics if the seed is not SeedBytes
+// long.
+func NewDeterministic(seed []byte) (rnd godium.Random) {
+	checkSeed(seed)
+
+	rnd = NewFrom(&deterministic{
+		stream: stream.NewChacha20Ietf(seed, deterministicNonce[:]),
+	})
+	return
+}
+
+// BufDeterministic fills p with bytes that are indistinguishable from random
+// without knowing the seed, like libsodium's randombytes_buf_deterministic. It
+// panics if the seed is not SeedBytes long.
+func BufDeterministic(p, seed []byte) {
+	checkSeed(seed)
+	if uint64(len(p)) > DeterministicBytesMax {
+		panic("random: deterministic buffer too large")
+	}
+
+	s := stream.NewChacha20Ietf(seed, deterministicNonce[:])
+	s.KeyStream(p)
+	s.Wipe()
+}
+
+// checkSeed panics if the seed is not SeedBytes long. A short seed must not be
+// padded with zeros, as that silently weakens the generator.
+func checkSeed(seed []byte) {
+	if len(seed) != SeedBytes {
+		panic("random: invalid seed length")
+	}
+}
+
+// Read implements io.Reader.
+func (d *deterministic) Read(p []byte) (n int, err error) {
+	if uint64(len(p)) > DeterministicBytesMax-d.read {
+		err = ErrExhausted
+		return
+	}
+
+	d.stream.KeyStream(p)
+	d.read += uint64(len(p))
+	n = len(p)
+	return
+}
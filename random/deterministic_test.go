@@ -0,0 +1,86 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package random
+
+import (
+	"bytes"
+	"testing"
+)
+
+// deterministicExpect is the output of randombytes_buf_deterministic for the
+// seed 0x00, 0x01, ..., 0x1f, as found in libsodium's randombytes test.
+var deterministicExpect = []byte{
+	0x0d, 0x8e, 0x6c, 0xc6, 0x87, 0x15, 0x64, 0x89,
+	0x26, 0x73, 0x2e, 0x7e, 0xa7, 0x32, 0x50, 0xcf,
+	0xaf, 0x2d, 0x58, 0x42, 0x20, 0x83, 0x90, 0x4c,
+	0x84, 0x1a, 0x8b, 0xa3, 0x3b, 0x98, 0x61, 0x11,
+	0xf3, 0x46, 0xba, 0x50, 0x72, 0x3a, 0x68, 0xae,
+	0x28, 0x35, 0x24, 0xa6, 0xbd, 0xed, 0x09, 0xf8,
+	0x3b, 0xe6, 0xb8, 0x05, 0x95, 0x85, 0x6f, 0x72,
+	0xe2, 0x5b, 0x86, 0x91, 0x8e, 0x8b, 0x11, 0x4b,
+	0xaf, 0xb9, 0x4b, 0xc8, 0xab, 0xed, 0xd7, 0x3d,
+	0xaa, 0xb4, 0x54, 0x57, 0x6b, 0x7c, 0x58, 0x33,
+	0xeb, 0x0b, 0xf9, 0x82, 0xa1, 0xbb, 0x45, 0x87,
+	0xa5, 0xc9, 0x70, 0xff, 0x08, 0x10, 0xca, 0x3b,
+	0x79, 0x1d, 0x7e, 0x12,
+}
+
+// deterministicSeed returns the seed used to generate deterministicExpect.
+func deterministicSeed() (seed []byte) {
+	seed = make([]byte, SeedBytes)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	return
+}
+
+// TestBufDeterministic
+func TestBufDeterministic(t *testing.T) {
+	out := make([]byte, len(deterministicExpect))
+	BufDeterministic(out, deterministicSeed())
+
+	if !bytes.Equal(deterministicExpect, out) {
+		t.Error("expected result did not match computed", deterministicExpect, out)
+	}
+}
+
+// TestNewDeterministic checks that the generator continues the same sequence
+// across multiple reads.
+func TestNewDeterministic(t *testing.T) {
+	rnd := NewDeterministic(deterministicSeed())
+	out := make([]byte, len(deterministicExpect))
+
+	for _, chunk := range [][]byte{out[:1], out[1:30], out[30:64], out[64:]} {
+		if err := rnd.Buf(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !bytes.Equal(deterministicExpect, out) {
+		t.Error("expected result did not match computed", deterministicExpect, out)
+	}
+}
+
+// TestDeterministicSeedLength checks that seeds of the wrong length are
+// rejected instead of being padded.
+func TestDeterministicSeedLength(t *testing.T) {
+	for _, n := range []int{0, 16, SeedBytes - 1, SeedBytes + 1} {
+		s := make([]byte, n)
+		expectPanic(t, "NewDeterministic", func() { NewDeterministic(s) })
+		expectPanic(t, "BufDeterministic", func() { BufDeterministic(make([]byte, 16), s) })
+	}
+}
+
+// expectPanic fails the test if f does not panic.
+func expectPanic(t *testing.T, name string, f func()) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic", name)
+		}
+	}()
+	f()
+}
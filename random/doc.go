@@ -8,3 +8,13 @@
 
  */
 package random // import "go.artemisc.eu/godium/random"
+
+import (
+	"errors"
+)
+
+var (
+	// ErrExhausted is returned when a deterministic generator can not produce
+	// the requested amount of bytes.
+	ErrExhausted = errors.New("random: deterministic generator exhausted")
+)
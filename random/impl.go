@@ -8,7 +8,6 @@ package random
 
 import (
 	"crypto/rand"
-	"encoding/binary"
 	"io"
 
 	"go.artemisc.eu/godium"
@@ -41,20 +40,52 @@ func (r impl) KeyGen(size int) (key []byte, err error) {
 	return
 }
 
-// Buf
+// Buf fills all of p, or returns an error if the underlying reader could not
+// provide enough bytes.
 func (r impl) Buf(p []byte) (err error) {
-	_, err = r.Read(p)
+	_, err = io.ReadFull(r.Reader, p)
 	return
 }
 
-// UInt32
+// UInt32 panics if the underlying reader fails, as returning a predictable
+// value instead is never safe.
 func (r impl) UInt32() (v uint32) {
-	_ = binary.Read(r.Reader, internal.NativeEndian, &v)
+	var buf [4]byte
+	mustBuf(r, buf[:])
+	v = internal.NativeEndian.Uint32(buf[:])
 	return
 }
 
 // UniformUInt32
 func (r impl) UniformUInt32(upper uint32) (v uint32) {
+	v = uniformUInt32(r, upper)
+	return
+}
+
+// UInt64 panics if the underlying reader fails, as returning a predictable
+// value instead is never safe.
+func (r impl) UInt64() (v uint64) {
+	var buf [8]byte
+	mustBuf(r, buf[:])
+	v = internal.NativeEndian.Uint64(buf[:])
+	return
+}
+
+// UniformUInt64
+func (r impl) UniformUInt64(upper uint64) (v uint64) {
+	v = uniformUInt64(r, upper)
+	return
+}
+
+// mustBuf fills p from rnd, and panics on failure.
+func mustBuf(rnd godium.Random, p []byte) {
+	if err := rnd.Buf(p); err != nil {
+		panic("random: failed to read random bytes: " + err.Error())
+	}
+}
+
+// uniformUInt32 returns a uniformly distributed value in [0, upper).
+func uniformUInt32(rnd godium.Random, upper uint32) (v uint32) {
 	if upper < 2 {
 		return
 	}
@@ -62,7 +93,7 @@ func (r impl) UniformUInt32(upper uint32) (v uint32) {
 	min := (1 + ^upper) % upper /* = 2**32 mod upper_bound */
 
 	for {
-		v = r.UInt32()
+		v = rnd.UInt32()
 		if v >= min {
 			break
 		}
@@ -75,14 +106,8 @@ func (r impl) UniformUInt32(upper uint32) (v uint32) {
 	return
 }
 
-// UInt64
-func (r impl) UInt64() (v uint64) {
-	binary.Read(r.Reader, internal.NativeEndian, &v)
-	return
-}
-
-// UniformUInt64
-func (r impl) UniformUInt64(upper uint64) (v uint64) {
+// uniformUInt64 returns a uniformly distributed value in [0, upper).
+func uniformUInt64(rnd godium.Random, upper uint64) (v uint64) {
 	if upper < 2 {
 		return
 	}
@@ -90,7 +115,7 @@ func (r impl) UniformUInt64(upper uint64) (v uint64) {
 	min := (1 + ^upper) % upper /* = 2**64 mod upper_bound */
 
 	for {
-		v = r.UInt64()
+		v = rnd.UInt64()
 		if v >= min {
 			break
 		}
@@ -0,0 +1,140 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package registry
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/aead"
+	"go.artemisc.eu/godium/box"
+	"go.artemisc.eu/godium/pwhash"
+	"go.artemisc.eu/godium/secretbox"
+	"go.artemisc.eu/godium/sign"
+)
+
+// init registers the primitives implemented by godium under their libsodium
+// names.
+func init() {
+	RegisterAEAD(AEAD{
+		Name:      "aes256gcm",
+		New:       aead.NewAes256Gcm,
+		KeyBytes:  aead.Aes256Gcm_KeyBytes,
+		NSecBytes: aead.Aes256Gcm_NSecBytes,
+		NPubBytes: aead.Aes256Gcm_NPubBytes,
+		ABytes:    aead.Aes256Gcm_ABytes,
+	})
+	RegisterAEAD(AEAD{
+		Name:      "aes256gcmsiv",
+		New:       aead.NewAes256GcmSiv,
+		KeyBytes:  aead.Aes256GcmSiv_KeyBytes,
+		NSecBytes: aead.Aes256GcmSiv_NSecBytes,
+		NPubBytes: aead.Aes256GcmSiv_NPubBytes,
+		ABytes:    aead.Aes256GcmSiv_ABytes,
+	})
+	RegisterAEAD(AEAD{
+		Name:      "chacha20poly1305",
+		New:       aead.NewChacha20Poly1305,
+		KeyBytes:  aead.Chacha20Poly1305_KeyBytes,
+		NSecBytes: aead.Chacha20Poly1305_NSecBytes,
+		NPubBytes: aead.Chacha20Poly1305_NPubBytes,
+		ABytes:    aead.Chacha20Poly1305_ABytes,
+	})
+	RegisterAEAD(AEAD{
+		Name:      "chacha20poly1305_ietf",
+		New:       aead.NewChacha20Poly1305Ietf,
+		KeyBytes:  aead.Chacha20Poly1305Ietf_KeyBytes,
+		NSecBytes: aead.Chacha20Poly1305Ietf_NSecBytes,
+		NPubBytes: aead.Chacha20Poly1305Ietf_NPubBytes,
+		ABytes:    aead.Chacha20Poly1305Ietf_ABytes,
+	})
+	RegisterAEAD(AEAD{
+		Name:      "xchacha20poly1305_ietf",
+		New:       aead.NewXChacha20Poly1305Ietf,
+		KeyBytes:  aead.XChacha20Poly1305Ietf_KeyBytes,
+		NSecBytes: aead.XChacha20Poly1305Ietf_NSecBytes,
+		NPubBytes: aead.XChacha20Poly1305Ietf_NPubBytes,
+		ABytes:    aead.XChacha20Poly1305Ietf_ABytes,
+	})
+	RegisterAEAD(AEAD{
+		Name:      "xchacha20blake2bsiv",
+		New:       aead.NewXChacha20Blake2bSiv,
+		KeyBytes:  aead.XChacha20Blake2bSiv_KeyBytes,
+		NSecBytes: aead.XChacha20Blake2bSiv_NSecBytes,
+		NPubBytes: aead.XChacha20Blake2bSiv_NPubBytes,
+		ABytes:    aead.XChacha20Blake2bSiv_ABytes,
+	})
+
+	RegisterBox(Box{
+		Name:           box.Primitive,
+		New:            box.NewCurve25519XSalsa20Poly1305,
+		PublicKeyBytes: box.Curve25519XSalsa20Poly1305_PublicKeyBytes,
+		SecretKeyBytes: box.Curve25519XSalsa20Poly1305_SecretKeyBytes,
+		MacBytes:       box.Curve25519XSalsa20Poly1305_MacBytes,
+		NonceBytes:     box.Curve25519XSalsa20Poly1305_NonceBytes,
+		SeedBytes:      box.Curve25519XSalsa20Poly1305_SeedBytes,
+		BeforeNmBytes:  box.Curve25519XSalsa20Poly1305_BeforeNmBytes,
+	})
+	RegisterBox(Box{
+		Name:           "curve25519xchacha20poly1305",
+		New:            box.NewCurve25519XChacha20Poly1305,
+		PublicKeyBytes: box.Curve25519XChacha20Poly1305_PublicKeyBytes,
+		SecretKeyBytes: box.Curve25519XChacha20Poly1305_SecretKeyBytes,
+		MacBytes:       box.Curve25519XChacha20Poly1305_MacBytes,
+		NonceBytes:     box.Curve25519XChacha20Poly1305_NonceBytes,
+		SeedBytes:      box.Curve25519XChacha20Poly1305_SeedBytes,
+		BeforeNmBytes:  box.Curve25519XChacha20Poly1305_BeforeNmBytes,
+	})
+
+	RegisterSecretBox(SecretBox{
+		Name:       secretbox.Primitive,
+		New:        secretbox.NewXSalsa20Poly1305,
+		KeyBytes:   secretbox.XSalsa20Poly1305_KeyBytes,
+		MacBytes:   secretbox.XSalsa20Poly1305_MacBytes,
+		NonceBytes: secretbox.XSalsa20Poly1305_NonceBytes,
+	})
+	RegisterSecretBox(SecretBox{
+		Name:       "xchacha20poly1305",
+		New:        secretbox.NewXChacha20Poly1305,
+		KeyBytes:   secretbox.XChacha20Poly1305_KeyBytes,
+		MacBytes:   secretbox.XChacha20Poly1305_MacBytes,
+		NonceBytes: secretbox.XChacha20Poly1305_NonceBytes,
+	})
+
+	RegisterSign(Sign{
+		Name:           sign.Primitive,
+		New:            sign.NewEd25519,
+		NewVerifier:    sign.NewEd25519Verifier,
+		PublicKeyBytes: sign.Ed25519_PublicKeyBytes,
+		SecretKeyBytes: sign.Ed25519_SecretKeyBytes,
+		Bytes:          sign.Ed25519_Bytes,
+		SeedBytes:      sign.Ed25519_SeedBytes,
+	})
+
+	RegisterPwHash(PwHash{
+		Name:      pwhash.Primitive,
+		New:       func(pw []byte) godium.PwHash { return pwhash.NewArgon2id(pw) },
+		BytesMin:  pwhash.Argon2id_BytesMin,
+		BytesMax:  pwhash.Argon2id_BytesMax,
+		SaltBytes: pwhash.Argon2id_SaltBytes,
+		StrBytes:  pwhash.Argon2id_StrBytes,
+	})
+	RegisterPwHash(PwHash{
+		Name:      "argon2i",
+		New:       func(pw []byte) godium.PwHash { return pwhash.NewArgon2i(pw) },
+		BytesMin:  pwhash.Argon2i_BytesMin,
+		BytesMax:  pwhash.Argon2i_BytesMax,
+		SaltBytes: pwhash.Argon2i_SaltBytes,
+		StrBytes:  pwhash.Argon2i_StrBytes,
+	})
+	RegisterPwHash(PwHash{
+		Name:      "scryptsalsa208sha256",
+		New:       pwhash.NewScrypt,
+		BytesMin:  pwhash.Scrypt_BytesMin,
+		BytesMax:  pwhash.Scrypt_BytesMax,
+		SaltBytes: pwhash.Scrypt_SaltBytes,
+		StrBytes:  pwhash.Scrypt_StrBytes,
+	})
+}
@@ -0,0 +1,28 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+/*
+Package registry maps the libsodium names of primitives, such as
+"xchacha20poly1305_ietf", "curve25519xsalsa20poly1305" or "argon2id", to
+constructors for the matching godium interface. This allows an algorithm to be
+selected by name, for example from a configuration file, without the caller
+knowing the concrete implementation.
+
+Every entry also lists the sizes of the keys, nonces and outputs of the
+primitive. All primitives implemented by godium are registered by default,
+other implementations can be added through the Register functions.
+*/
+package registry // import "go.artemisc.eu/godium/registry"
+
+import (
+	"errors"
+)
+
+var (
+	// ErrUnknownPrimitive is returned when no primitive with the requested
+	// name has been registered.
+	ErrUnknownPrimitive = errors.New("registry: unknown primitive")
+)
@@ -0,0 +1,304 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package registry
+
+import (
+	"sort"
+	"sync"
+
+	"go.artemisc.eu/godium"
+)
+
+// AEAD describes a registered godium.AEAD primitive.
+type AEAD struct {
+	Name string
+	New  func(key []byte) godium.AEAD
+
+	KeyBytes  int
+	NSecBytes int
+	NPubBytes int
+	ABytes    int
+}
+
+// Box describes a registered godium.Box primitive.
+type Box struct {
+	Name string
+	New  func(private, public []byte) godium.Box
+
+	PublicKeyBytes int
+	SecretKeyBytes int
+	MacBytes       int
+	NonceBytes     int
+	SeedBytes      int
+	BeforeNmBytes  int
+}
+
+// SecretBox describes a registered godium.SecretBox primitive.
+type SecretBox struct {
+	Name string
+	New  func(key []byte) godium.SecretBox
+
+	KeyBytes   int
+	MacBytes   int
+	NonceBytes int
+}
+
+// Sign describes a registered godium.Sign primitive, together with the
+// constructor of its godium.SignVerifier counterpart.
+type Sign struct {
+	Name        string
+	New         func(key godium.PrivateKey) godium.Sign
+	NewVerifier func(key godium.PublicKey) godium.SignVerifier
+
+	PublicKeyBytes int
+	SecretKeyBytes int
+	Bytes          int
+	SeedBytes      int
+}
+
+// PwHash describes a registered godium.PwHash primitive.
+type PwHash struct {
+	Name string
+	New  func(pw []byte) godium.PwHash
+
+	// BytesMin and BytesMax are uint64, like the output length passed to
+	// godium.PwHash.Hash, as they do not fit an int on 32-bit platforms.
+	BytesMin  uint64
+	BytesMax  uint64
+	SaltBytes int
+	StrBytes  int
+}
+
+// table is a name indexed set of entries, safe for concurrent use.
+type table struct {
+	mu      sync.RWMutex
+	entries map[string]interface{}
+}
+
+var (
+	aeads       = &table{entries: make(map[string]interface{})}
+	boxes       = &table{entries: make(map[string]interface{})}
+	secretBoxes = &table{entries: make(map[string]interface{})}
+	signs       = &table{entries: make(map[string]interface{})}
+	pwHashes    = &table{entries: make(map[string]interface{})}
+)
+
+// add stores the entry under name. It panics if the name is empty, or already
+// registered, as registering the same name twice is a programming error.
+func (t *table) add(name string, entry interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if name == "" {
+		panic("registry: primitive registered without a name")
+	}
+	if _, exists := t.entries[name]; exists {
+		panic("registry: primitive registered twice: " + name)
+	}
+	t.entries[name] = entry
+}
+
+// get returns the entry stored under name.
+func (t *table) get(name string) (entry interface{}, err error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	entry, ok := t.entries[name]
+	if !ok {
+		err = ErrUnknownPrimitive
+	}
+	return
+}
+
+// names returns the sorted list of registered names.
+func (t *table) names() (names []string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	names = make([]string, 0, len(t.entries))
+	for name := range t.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// RegisterAEAD makes an AEAD available under its Name. It panics if the
+// constructor is nil, or if the name is already in use.
+func RegisterAEAD(a AEAD) {
+	if a.New == nil {
+		panic("registry: nil constructor for " + a.Name)
+	}
+	aeads.add(a.Name, a)
+}
+
+// LookupAEAD returns the AEAD registered under name.
+func LookupAEAD(name string) (a AEAD, err error) {
+	e, err := aeads.get(name)
+	if err == nil {
+		a = e.(AEAD)
+	}
+	return
+}
+
+// NewAEAD creates a godium.AEAD for the primitive registered under name.
+func NewAEAD(name string, key []byte) (impl godium.AEAD, err error) {
+	a, err := LookupAEAD(name)
+	if err == nil {
+		impl = a.New(key)
+	}
+	return
+}
+
+// AEADs returns the sorted names of all registered AEADs.
+func AEADs() (names []string) {
+	names = aeads.names()
+	return
+}
+
+// RegisterBox makes a Box available under its Name. It panics if the
+// constructor is nil, or if the name is already in use.
+func RegisterBox(b Box) {
+	if b.New == nil {
+		panic("registry: nil constructor for " + b.Name)
+	}
+	boxes.add(b.Name, b)
+}
+
+// LookupBox returns the Box registered under name.
+func LookupBox(name string) (b Box, err error) {
+	e, err := boxes.get(name)
+	if err == nil {
+		b = e.(Box)
+	}
+	return
+}
+
+// NewBox creates a godium.Box for the primitive registered under name.
+func NewBox(name string, private, public []byte) (box godium.Box, err error) {
+	b, err := LookupBox(name)
+	if err == nil {
+		box = b.New(private, public)
+	}
+	return
+}
+
+// Boxes returns the sorted names of all registered Boxes.
+func Boxes() (names []string) {
+	names = boxes.names()
+	return
+}
+
+// RegisterSecretBox makes a SecretBox available under its Name. It panics if
+// the constructor is nil, or if the name is already in use.
+func RegisterSecretBox(s SecretBox) {
+	if s.New == nil {
+		panic("registry: nil constructor for " + s.Name)
+	}
+	secretBoxes.add(s.Name, s)
+}
+
+// LookupSecretBox returns the SecretBox registered under name.
+func LookupSecretBox(name string) (s SecretBox, err error) {
+	e, err := secretBoxes.get(name)
+	if err == nil {
+		s = e.(SecretBox)
+	}
+	return
+}
+
+// NewSecretBox creates a godium.SecretBox for the primitive registered under
+// name.
+func NewSecretBox(name string, key []byte) (sb godium.SecretBox, err error) {
+	s, err := LookupSecretBox(name)
+	if err == nil {
+		sb = s.New(key)
+	}
+	return
+}
+
+// SecretBoxes returns the sorted names of all registered SecretBoxes.
+func SecretBoxes() (names []string) {
+	names = secretBoxes.names()
+	return
+}
+
+// RegisterSign makes a Sign available under its Name. It panics if either
+// constructor is nil, or if the name is already in use.
+func RegisterSign(s Sign) {
+	if s.New == nil || s.NewVerifier == nil {
+		panic("registry: nil constructor for " + s.Name)
+	}
+	signs.add(s.Name, s)
+}
+
+// LookupSign returns the Sign registered under name.
+func LookupSign(name string) (s Sign, err error) {
+	e, err := signs.get(name)
+	if err == nil {
+		s = e.(Sign)
+	}
+	return
+}
+
+// NewSign creates a godium.Sign for the primitive registered under name.
+func NewSign(name string, key godium.PrivateKey) (sign godium.Sign, err error) {
+	s, err := LookupSign(name)
+	if err == nil {
+		sign = s.New(key)
+	}
+	return
+}
+
+// NewSignVerifier creates a godium.SignVerifier for the primitive registered
+// under name.
+func NewSignVerifier(name string, key godium.PublicKey) (v godium.SignVerifier, err error) {
+	s, err := LookupSign(name)
+	if err == nil {
+		v = s.NewVerifier(key)
+	}
+	return
+}
+
+// Signs returns the sorted names of all registered Signs.
+func Signs() (names []string) {
+	names = signs.names()
+	return
+}
+
+// RegisterPwHash makes a PwHash available under its Name. It panics if the
+// constructor is nil, or if the name is already in use.
+func RegisterPwHash(p PwHash) {
+	if p.New == nil {
+		panic("registry: nil constructor for " + p.Name)
+	}
+	pwHashes.add(p.Name, p)
+}
+
+// LookupPwHash returns the PwHash registered under name.
+func LookupPwHash(name string) (p PwHash, err error) {
+	e, err := pwHashes.get(name)
+	if err == nil {
+		p = e.(PwHash)
+	}
+	return
+}
+
+// NewPwHash creates a godium.PwHash for the primitive registered under name.
+func NewPwHash(name string, pw []byte) (ph godium.PwHash, err error) {
+	p, err := LookupPwHash(name)
+	if err == nil {
+		ph = p.New(pw)
+	}
+	return
+}
+
+// PwHashes returns the sorted names of all registered PwHashes.
+func PwHashes() (names []string) {
+	names = pwHashes.names()
+	return
+}
@@ -0,0 +1,276 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package registry
+
+import (
+	"reflect"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/aead"
+	"go.artemisc.eu/godium/sign"
+)
+
+// expectPanic fails the test if f does not panic.
+func expectPanic(t *testing.T, name string, f func()) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic", name)
+		}
+	}()
+	f()
+}
+
+// maxInt is the largest int, the bound on the PwHash limit methods.
+const maxInt = int(^uint(0) >> 1)
+
+// builtin holds the names of the primitives registered by default.
+var builtin = struct {
+	aeads, boxes, secretBoxes, signs, pwHashes []string
+}{
+	aeads: []string{"aes256gcm", "aes256gcmsiv", "chacha20poly1305", "chacha20poly1305_ietf",
+		"xchacha20blake2bsiv", "xchacha20poly1305_ietf"},
+	boxes:       []string{"curve25519xchacha20poly1305", "curve25519xsalsa20poly1305"},
+	secretBoxes: []string{"xchacha20poly1305", "xsalsa20poly1305"},
+	signs:       []string{"ed25519"},
+	pwHashes:    []string{"argon2i", "argon2id", "scryptsalsa208sha256"},
+}
+
+// contains reports whether every name in expect is in names.
+func contains(names, expect []string) bool {
+	set := make(map[string]bool)
+	for _, name := range names {
+		set[name] = true
+	}
+	for _, name := range expect {
+		if !set[name] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAEADs(t *testing.T) {
+	if !contains(AEADs(), builtin.aeads) {
+		t.Error("expected result did not match computed", builtin.aeads, AEADs())
+	}
+
+	for _, name := range builtin.aeads {
+		a, err := LookupAEAD(name)
+		if err != nil || a.Name != name {
+			t.Fatal("builtin AEAD not found", name, err)
+		}
+
+		impl, err := NewAEAD(name, make([]byte, a.KeyBytes))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expect := []int{impl.KeyBytes(), impl.NSecBytes(), impl.NPubBytes(), impl.ABytes()}
+		got := []int{a.KeyBytes, a.NSecBytes, a.NPubBytes, a.ABytes}
+		if !reflect.DeepEqual(expect, got) {
+			t.Error("expected result did not match computed", name, expect, got)
+		}
+	}
+}
+
+func TestBoxes(t *testing.T) {
+	if !contains(Boxes(), builtin.boxes) {
+		t.Error("expected result did not match computed", builtin.boxes, Boxes())
+	}
+
+	for _, name := range builtin.boxes {
+		b, err := LookupBox(name)
+		if err != nil || b.Name != name {
+			t.Fatal("builtin Box not found", name, err)
+		}
+
+		impl, err := NewBox(name, make([]byte, b.SecretKeyBytes), make([]byte, b.PublicKeyBytes))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expect := []int{impl.PublicKeyBytes(), impl.SecretKeyBytes(), impl.MacBytes(),
+			impl.NonceBytes(), impl.SeedBytes(), impl.BeforeNmBytes()}
+		got := []int{b.PublicKeyBytes, b.SecretKeyBytes, b.MacBytes,
+			b.NonceBytes, b.SeedBytes, b.BeforeNmBytes}
+		if !reflect.DeepEqual(expect, got) {
+			t.Error("expected result did not match computed", name, expect, got)
+		}
+	}
+}
+
+func TestSecretBoxes(t *testing.T) {
+	if !contains(SecretBoxes(), builtin.secretBoxes) {
+		t.Error("expected result did not match computed", builtin.secretBoxes, SecretBoxes())
+	}
+
+	for _, name := range builtin.secretBoxes {
+		s, err := LookupSecretBox(name)
+		if err != nil || s.Name != name {
+			t.Fatal("builtin SecretBox not found", name, err)
+		}
+
+		impl, err := NewSecretBox(name, make([]byte, s.KeyBytes))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expect := []int{impl.KeyBytes(), impl.MacBytes(), impl.NonceBytes()}
+		got := []int{s.KeyBytes, s.MacBytes, s.NonceBytes}
+		if !reflect.DeepEqual(expect, got) {
+			t.Error("expected result did not match computed", name, expect, got)
+		}
+	}
+}
+
+func TestSigns(t *testing.T) {
+	if !contains(Signs(), builtin.signs) {
+		t.Error("expected result did not match computed", builtin.signs, Signs())
+	}
+
+	for _, name := range builtin.signs {
+		s, err := LookupSign(name)
+		if err != nil || s.Name != name {
+			t.Fatal("builtin Sign not found", name, err)
+		}
+
+		pair := sign.KeyPairSeed(make([]byte, s.SeedBytes))
+		impl, err := NewSign(name, pair.SecretKey())
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		expect := []int{impl.PublicKeyBytes(), impl.SecretKeyBytes(), impl.Bytes(), impl.SeedBytes()}
+		got := []int{s.PublicKeyBytes, s.SecretKeyBytes, s.Bytes, s.SeedBytes}
+		if !reflect.DeepEqual(expect, got) {
+			t.Error("expected result did not match computed", name, expect, got)
+		}
+
+		v, err := NewSignVerifier(name, pair.PublicKey())
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		if v.PublicKeyBytes() != s.PublicKeyBytes || v.Bytes() != s.Bytes {
+			t.Error("expected result did not match computed", name, v.PublicKeyBytes(), v.Bytes())
+		}
+
+		msg := []byte("registry")
+		if !v.VerifyDetached(impl.SignDetached(nil, msg), msg) {
+			t.Error("signature did not verify", name)
+		}
+	}
+}
+
+func TestPwHashes(t *testing.T) {
+	if !contains(PwHashes(), builtin.pwHashes) {
+		t.Error("expected result did not match computed", builtin.pwHashes, PwHashes())
+	}
+
+	for _, name := range builtin.pwHashes {
+		p, err := LookupPwHash(name)
+		if err != nil || p.Name != name {
+			t.Fatal("builtin PwHash not found", name, err)
+		}
+
+		impl, err := NewPwHash(name, []byte("password"))
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		// the BytesMax method is clamped to the size of an int
+		bytesMax := p.BytesMax
+		if bytesMax > uint64(maxInt) {
+			bytesMax = uint64(maxInt)
+		}
+
+		expect := []uint64{uint64(impl.BytesMin()), uint64(impl.BytesMax()), uint64(impl.SaltBytes()), uint64(impl.StrBytes())}
+		got := []uint64{p.BytesMin, bytesMax, uint64(p.SaltBytes), uint64(p.StrBytes)}
+		if !reflect.DeepEqual(expect, got) {
+			t.Error("expected result did not match computed", name, expect, got)
+		}
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	if _, err := NewAEAD("rot13", nil); err != ErrUnknownPrimitive {
+		t.Error("unknown AEAD was found", err)
+	}
+	if _, err := NewBox("rot13", nil, nil); err != ErrUnknownPrimitive {
+		t.Error("unknown Box was found", err)
+	}
+	if _, err := NewSecretBox("rot13", nil); err != ErrUnknownPrimitive {
+		t.Error("unknown SecretBox was found", err)
+	}
+	if _, err := NewSign("rot13", nil); err != ErrUnknownPrimitive {
+		t.Error("unknown Sign was found", err)
+	}
+	if _, err := NewSignVerifier("rot13", nil); err != ErrUnknownPrimitive {
+		t.Error("unknown SignVerifier was found", err)
+	}
+	if _, err := NewPwHash("rot13", nil); err != ErrUnknownPrimitive {
+		t.Error("unknown PwHash was found", err)
+	}
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	newAEAD := func(key []byte) godium.AEAD { return aead.NewChacha20Poly1305Ietf(key) }
+
+	expectPanic(t, "duplicate AEAD", func() { RegisterAEAD(AEAD{Name: "aes256gcm", New: newAEAD}) })
+	expectPanic(t, "empty AEAD name", func() { RegisterAEAD(AEAD{New: newAEAD}) })
+	expectPanic(t, "nil AEAD constructor", func() { RegisterAEAD(AEAD{Name: "test-nil"}) })
+
+	expectPanic(t, "duplicate Box", func() {
+		RegisterBox(Box{Name: builtin.boxes[0], New: func(private, public []byte) godium.Box { return nil }})
+	})
+	expectPanic(t, "empty SecretBox name", func() {
+		RegisterSecretBox(SecretBox{New: func(key []byte) godium.SecretBox { return nil }})
+	})
+	expectPanic(t, "Sign without verifier", func() {
+		RegisterSign(Sign{Name: "test-sign", New: sign.NewEd25519})
+	})
+	expectPanic(t, "duplicate PwHash", func() {
+		RegisterPwHash(PwHash{Name: "argon2id", New: func(pw []byte) godium.PwHash { return nil }})
+	})
+
+	if _, err := LookupAEAD("test-nil"); err != ErrUnknownPrimitive {
+		t.Error("invalid AEAD was registered", err)
+	}
+	if _, err := LookupSign("test-sign"); err != ErrUnknownPrimitive {
+		t.Error("invalid Sign was registered", err)
+	}
+}
+
+// thirdParty is an AEAD implemented outside of godium.
+type thirdParty struct {
+	godium.AEAD
+}
+
+func TestRegisterThirdParty(t *testing.T) {
+	RegisterAEAD(AEAD{
+		Name: "test-thirdparty",
+		New: func(key []byte) godium.AEAD {
+			return &thirdParty{AEAD: aead.NewXChacha20Poly1305Ietf(key)}
+		},
+		KeyBytes:  aead.XChacha20Poly1305Ietf_KeyBytes,
+		NPubBytes: aead.XChacha20Poly1305Ietf_NPubBytes,
+		ABytes:    aead.XChacha20Poly1305Ietf_ABytes,
+	})
+
+	if !contains(AEADs(), []string{"test-thirdparty"}) {
+		t.Error("registered AEAD is not listed", AEADs())
+	}
+
+	a, err := NewAEAD("test-thirdparty", make([]byte, aead.XChacha20Poly1305Ietf_KeyBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := a.(*thirdParty); !ok {
+		t.Error("registered constructor was not used", a)
+	}
+
+	nonce := make([]byte, a.NPubBytes())
+	plain, err := a.Open(nil, nonce, a.Seal(nil, nonce, []byte("message"), nil), nil)
+	if err != nil || string(plain) != "message" {
+		t.Error("expected result did not match computed", plain, err)
+	}
+}
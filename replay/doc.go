@@ -0,0 +1,24 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+/*
+Package replay protects receivers of messages sealed with counter nonces
+against replayed and very old messages. A Window keeps a sliding bitmap of
+the counters seen recently, like IPsec and WireGuard do for datagrams that
+can be lost or arrive out of order. Counters are only accepted by the window
+after the message is authenticated, so forged messages can not move it.
+*/
+package replay // import "go.artemisc.eu/godium/replay"
+
+import (
+	"errors"
+)
+
+var (
+	// ErrReplayed is returned when an authentic message carries a counter that
+	// was already accepted, or that fell behind the window.
+	ErrReplayed = errors.New("replay: message replayed or too old")
+)
@@ -0,0 +1,161 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package replay
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"go.artemisc.eu/godium"
+)
+
+// windowWords is the size of the bitmap ring in 64 bit words. One word is
+// kept free, so moving the window never clears bits still inside it.
+const windowWords = 32
+
+const (
+	// Window_Size is the number of counters below the highest accepted one
+	// that are still accepted once.
+	Window_Size = (windowWords - 1) * 64
+)
+
+// Window is a sliding bitmap over 64 bit message counters, as described in
+// RFC 6479. The zero value is an empty window, and it is safe for concurrent
+// use.
+type Window struct {
+	mu     sync.Mutex
+	last   uint64
+	bitmap [windowWords]uint64
+}
+
+// Check returns whether counter would be accepted, without changing the
+// window. It can be used to drop replays before authenticating them, but
+// Accept must still be called after authentication.
+func (w *Window) Check(counter uint64) (ok bool) {
+	w.mu.Lock()
+	ok = w.check(counter)
+	w.mu.Unlock()
+	return
+}
+
+// Accept marks counter as seen, and returns whether it was not seen before
+// and is not too old. It must only be called for authentic messages, so a
+// forged message with a high counter can not move the window.
+func (w *Window) Accept(counter uint64) (ok bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.check(counter) {
+		return
+	}
+
+	index := counter / 64
+	if counter > w.last {
+		// clear the words the window moves over.
+		current := w.last / 64
+		diff := index - current
+		if diff > windowWords {
+			diff = windowWords
+		}
+		for i := uint64(1); i <= diff; i++ {
+			w.bitmap[(current+i)%windowWords] = 0
+		}
+		w.last = counter
+	}
+
+	w.bitmap[index%windowWords] |= 1 << (counter % 64)
+	ok = true
+	return
+}
+
+// check is Check without the lock.
+func (w *Window) check(counter uint64) (ok bool) {
+	switch {
+	case counter > w.last:
+		ok = true
+	case w.last-counter > Window_Size:
+		ok = false
+	default:
+		ok = w.bitmap[(counter/64)%windowWords]&(1<<(counter%64)) == 0
+	}
+	return
+}
+
+// Reset empties the window, for example after a new key is set.
+func (w *Window) Reset() {
+	w.mu.Lock()
+	w.last = 0
+	w.bitmap = [windowWords]uint64{}
+	w.mu.Unlock()
+}
+
+// CounterNonce writes the nonce of size bytes for counter to dst: the counter
+// as a little endian integer, followed by zeros. This is the nonce
+// sodium_increment reaches from an all zero nonce.
+func CounterNonce(dst []byte, counter uint64, size int) (nonce []byte) {
+	nonce = dst[:size]
+	godium.Wipe(nonce)
+	binary.LittleEndian.PutUint64(nonce, counter)
+	return
+}
+
+// Seal seals plain with the AEAD under the nonce of counter. The counter must
+// never repeat under the same key, and is sent along with the message.
+func Seal(a godium.AEAD, dst []byte, counter uint64, plain, ad []byte) (cipher []byte) {
+	var buf [32]byte
+
+	nonce := CounterNonce(buf[:], counter, a.NPubBytes())
+	cipher = a.Seal(dst, nonce, plain, ad)
+	return
+}
+
+// Open opens a message sealed with Seal under counter, and only then checks
+// the counter against the window. An authentic message with a counter that
+// was seen before, or is too old, is rejected with ErrReplayed, and its
+// plaintext is erased.
+func (w *Window) Open(a godium.AEAD, dst []byte, counter uint64, cipher, ad []byte) (plain []byte, err error) {
+	var buf [32]byte
+
+	nonce := CounterNonce(buf[:], counter, a.NPubBytes())
+	if plain, err = a.Open(dst, nonce, cipher, ad); err != nil {
+		return
+	}
+
+	if !w.Accept(counter) {
+		godium.Wipe(plain)
+		err = ErrReplayed
+	}
+	return
+}
+
+// SealBox seals plain for the remote public key under the nonce of counter.
+// The counter must never repeat between the two key pairs, in either
+// direction, and is sent along with the message.
+func SealBox(b godium.Box, dst []byte, counter uint64, plain []byte, remote godium.PublicKey) (cipher []byte, err error) {
+	var buf [32]byte
+
+	nonce := CounterNonce(buf[:], counter, b.NonceBytes())
+	cipher, err = b.Seal(dst, nonce, plain, remote)
+	return
+}
+
+// OpenBox opens a box sealed with SealBox under counter by the remote public
+// key, and only then checks the counter against the window, like Open.
+func (w *Window) OpenBox(b godium.Box, dst []byte, counter uint64, cipher []byte, remote godium.PublicKey) (plain []byte, err error) {
+	var buf [32]byte
+
+	nonce := CounterNonce(buf[:], counter, b.NonceBytes())
+	if plain, err = b.Open(dst, nonce, cipher, remote); err != nil {
+		return
+	}
+
+	if !w.Accept(counter) {
+		godium.Wipe(plain)
+		err = ErrReplayed
+	}
+	return
+}
@@ -0,0 +1,180 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package replay
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/aead"
+	"go.artemisc.eu/godium/box"
+)
+
+// testBytes returns size bytes counting up from start.
+func testBytes(start byte, size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = start + byte(i)
+	}
+	return
+}
+
+func TestWindow(t *testing.T) {
+	var w Window
+
+	steps := []struct {
+		counter uint64
+		ok      bool
+	}{
+		{0, true},
+		{0, false},
+		{1, true},
+		{3, true},
+		{2, true},
+		{3, false},
+		{2, false},
+		{Window_Size + 3, true},
+		{3, false},
+		{4, true},
+		{2, false},
+		{Window_Size + 3, false},
+		{Window_Size + 2, true},
+		{10 * Window_Size, true},
+		{9*Window_Size - 1, false},
+		{9 * Window_Size, true},
+		{9 * Window_Size, false},
+		{9*Window_Size + 1, true},
+		{1<<64 - 1, true},
+		{1<<64 - 2, true},
+		{1<<64 - 1, false},
+		{0, false},
+	}
+
+	for i, v := range steps {
+		if ok := w.Check(v.counter); ok != v.ok {
+			t.Error("expected result did not match computed", i, v.counter, v.ok, ok)
+		}
+		if ok := w.Accept(v.counter); ok != v.ok {
+			t.Error("expected result did not match computed", i, v.counter, v.ok, ok)
+		}
+	}
+
+	w.Reset()
+	if !w.Accept(0) {
+		t.Error("counter was rejected after Reset")
+	}
+}
+
+func TestWindowReorder(t *testing.T) {
+	var w Window
+
+	// every counter inside the window is accepted once, in any order.
+	for base := uint64(0); base < 10*Window_Size; base += Window_Size + 1 {
+		for i := uint64(0); i <= Window_Size; i++ {
+			c := base + Window_Size - i
+			if !w.Accept(c) {
+				t.Fatal("counter was rejected", c)
+			}
+			if w.Accept(c) {
+				t.Fatal("counter was accepted twice", c)
+			}
+		}
+	}
+}
+
+func TestWindowConcurrent(t *testing.T) {
+	const goroutines, counters = 8, 5000
+
+	var w Window
+	var accepted int64
+	var wg sync.WaitGroup
+
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := uint64(0); c < counters; c++ {
+				if w.Accept(c) {
+					atomic.AddInt64(&accepted, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// counters can fall behind the window, but none is accepted twice.
+	if accepted > counters || accepted < Window_Size {
+		t.Error("unexpected number of accepted counters", accepted)
+	}
+}
+
+func TestCounterNonce(t *testing.T) {
+	expect := []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, 0, 0, 0, 0}
+	got := CounterNonce(bytes.Repeat([]byte{0xff}, 32), 0x0102030405060708, 12)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	a := aead.NewChacha20Poly1305Ietf(testBytes(0, 32))
+	ad := []byte("header")
+
+	var w Window
+	for _, c := range []uint64{0, 2, 1} {
+		plain := testBytes(byte(c), 100)
+		sealed := Seal(a, nil, c, plain, ad)
+
+		got, err := w.Open(a, nil, c, sealed, ad)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", c, err)
+		}
+
+		got, err = w.Open(a, nil, c, sealed, ad)
+		if err != ErrReplayed || !bytes.Equal(got, make([]byte, len(plain))) {
+			t.Error("replayed message was accepted", c, err)
+		}
+	}
+
+	// a forged message with a far counter does not move the window.
+	forged := Seal(a, nil, 100000, testBytes(0, 100), ad)
+	forged[0] ^= 1
+	if _, err := w.Open(a, nil, 100000, forged, ad); err != godium.ErrForgedOrCorrupted {
+		t.Error("forged message was accepted", err)
+	}
+	if _, err := w.Open(a, nil, 3, Seal(a, nil, 3, nil, ad), ad); err != nil {
+		t.Error("message was rejected after a forgery", err)
+	}
+
+	// a message sealed under one counter does not open under another.
+	if _, err := w.Open(a, nil, 5, Seal(a, nil, 4, nil, ad), ad); err != godium.ErrForgedOrCorrupted {
+		t.Error("message was accepted under a different counter", err)
+	}
+}
+
+func TestOpenBox(t *testing.T) {
+	alice := box.KeyPairSeedCurve25519XSalsa20Poly1305(testBytes(0x60, 32))
+	bob := box.KeyPairSeedCurve25519XSalsa20Poly1305(testBytes(0xa0, 32))
+	plain := testBytes(0, 100)
+
+	var w Window
+	sealed, err := SealBox(alice, nil, 7, plain, bob.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := w.OpenBox(bob, nil, 7, sealed, alice.PublicKey())
+	if err != nil || !bytes.Equal(plain, got) {
+		t.Error("expected result did not match computed", err)
+	}
+	if _, err = w.OpenBox(bob, nil, 7, sealed, alice.PublicKey()); err != ErrReplayed {
+		t.Error("replayed box was accepted", err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"go.artemisc.eu/godium"
 	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/utils"
 	"golang.org/x/crypto/curve25519"
 )
 
@@ -31,11 +32,7 @@ func Curve25519(dst, in, base []byte) (out []byte, err error) {
 		(*[Bytes]byte)(unsafe.Pointer(&base[0])))
 
 	// check for invalid resulting key
-	d := byte(0)
-	for _, v := range out {
-		d |= v
-	}
-	if -(1 & ((d - 1) >> 8)) != 0 {
+	if utils.IsZero(out) {
 		out, err = nil, godium.ErrInvalidPoint
 	}
 	return
@@ -49,3 +46,58 @@ func Curve25519Base(dst, in []byte) (out []byte) {
 		(*[Bytes]byte)(unsafe.Pointer(&in[0])))
 	return
 }
+
+// Curve25519KeyPair implements godium.KeyPair for a Curve25519 secret scalar
+// and the public point derived from it by Curve25519Base.
+type Curve25519KeyPair struct {
+	private godium.PrivateKey
+	public  godium.PublicKey
+}
+
+// KeyPairCurve25519 generates a new key pair with a random secret scalar.
+func KeyPairCurve25519(random godium.Random) (kp *Curve25519KeyPair, err error) {
+	seed, err := random.KeyGen(Curve25519_ScalarBytes)
+	if err != nil {
+		return
+	}
+	defer godium.Wipe(seed)
+
+	kp = KeyPairSeedCurve25519(seed)
+	return
+}
+
+// KeyPairSeedCurve25519 deterministically creates a key pair from the seed,
+// which is used as the secret scalar as is. It panics if the seed is not
+// Curve25519_ScalarBytes long.
+func KeyPairSeedCurve25519(seed []byte) (kp *Curve25519KeyPair) {
+	if len(seed) != Curve25519_ScalarBytes {
+		panic("scalarmult: invalid Curve25519 seed length")
+	}
+
+	kp = &Curve25519KeyPair{
+		private: internal.Copy(seed, Curve25519_ScalarBytes),
+		public:  make([]byte, Curve25519_Bytes),
+	}
+	Curve25519Base(kp.public[:0], kp.private)
+	return
+}
+
+// Wipe
+func (kp *Curve25519KeyPair) Wipe() {
+	godium.Wipe(kp.private)
+	godium.Wipe(kp.public)
+}
+
+// PublicKey
+func (kp *Curve25519KeyPair) PublicKey() godium.PublicKey {
+	return internal.Copy(kp.public, Curve25519_Bytes)
+}
+
+// SecretKey
+func (kp *Curve25519KeyPair) SecretKey() godium.PrivateKey {
+	return internal.Copy(kp.private, Curve25519_ScalarBytes)
+}
+
+func (kp *Curve25519KeyPair) PublicKeyBytes() int { return Curve25519_Bytes }
+func (kp *Curve25519KeyPair) SecretKeyBytes() int { return Curve25519_ScalarBytes }
+func (kp *Curve25519KeyPair) SeedBytes() int      { return Curve25519_ScalarBytes }
@@ -0,0 +1,65 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package scalarmult
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+var _ godium.KeyPair = (*Curve25519KeyPair)(nil)
+
+// alice's secret and public key from libsodium's scalarmult test, the public
+// key is crypto_scalarmult_base of the secret key.
+var (
+	aliceSk = []byte{
+		0x77, 0x07, 0x6d, 0x0a, 0x73, 0x18, 0xa5, 0x7d,
+		0x3c, 0x16, 0xc1, 0x72, 0x51, 0xb2, 0x66, 0x45,
+		0xdf, 0x4c, 0x2f, 0x87, 0xeb, 0xc0, 0x99, 0x2a,
+		0xb1, 0x77, 0xfb, 0xa5, 0x1d, 0xb9, 0x2c, 0x2a,
+	}
+
+	alicePk = []byte{
+		0x85, 0x20, 0xf0, 0x09, 0x89, 0x30, 0xa7, 0x54,
+		0x74, 0x8b, 0x7d, 0xdc, 0xb4, 0x3e, 0xf7, 0x5a,
+		0x0d, 0xbf, 0x3a, 0x0d, 0x26, 0x38, 0x1a, 0xf4,
+		0xeb, 0xa4, 0xa9, 0x8e, 0xaa, 0x9b, 0x4e, 0x6a,
+	}
+)
+
+func TestCurve25519Base(t *testing.T) {
+	pk := Curve25519Base(nil, aliceSk)
+	if !bytes.Equal(alicePk, pk) {
+		t.Error("expected result did not match computed", alicePk, pk)
+	}
+}
+
+func TestKeyPairSeed(t *testing.T) {
+	kp := KeyPairSeed(aliceSk)
+
+	if !bytes.Equal(alicePk, kp.PublicKey()) {
+		t.Error("expected public key did not match computed", alicePk, kp.PublicKey())
+	}
+	if !bytes.Equal(aliceSk, kp.SecretKey()) {
+		t.Error("expected secret key did not match computed", aliceSk, kp.SecretKey())
+	}
+}
+
+func TestKeyPairSeedLength(t *testing.T) {
+	for _, n := range []int{0, Curve25519_ScalarBytes - 1, Curve25519_ScalarBytes + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("seed of invalid length was accepted", n)
+				}
+			}()
+			KeyPairSeedCurve25519(make([]byte, n))
+		}()
+	}
+}
@@ -6,6 +6,10 @@
 
 package scalarmult // import "go.artemisc.eu/godium/scalarmult"
 
+import (
+	"go.artemisc.eu/godium"
+)
+
 const (
 	Primitive   = "curve25519"
 	Bytes       = Curve25519_Bytes
@@ -23,3 +27,15 @@ func ScalarMultBase(dst, in []byte) (out []byte) {
 	out = Curve25519Base(dst, in)
 	return
 }
+
+// KeyPair
+func KeyPair(random godium.Random) (kp godium.KeyPair, err error) {
+	kp, err = KeyPairCurve25519(random)
+	return
+}
+
+// KeyPairSeed
+func KeyPairSeed(seed []byte) (kp godium.KeyPair) {
+	kp = KeyPairSeedCurve25519(seed)
+	return
+}
@@ -6,5 +6,9 @@
 
 /*
 
- */
+Package SecretBox implements secret key authenticated encryption. A SecretBox
+only keeps its key between calls, so a single instance can be shared by many
+goroutines.
+
+*/
 package secretbox // import "go.artemisc.eu/godium/secretbox"
@@ -0,0 +1,61 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretbox
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/random"
+)
+
+func TestEasy(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range secretboxVectors {
+		s := v.new(testBytes(0, 32))
+		plain := testPattern(100)
+
+		// the nonce is the first output of the generator.
+		nonce := make([]byte, s.NonceBytes())
+		random.NewDeterministic(testBytes(0x80, 32)).Buf(nonce)
+
+		rnd := random.NewDeterministic(testBytes(0x80, 32))
+		cipher, err := Encrypt(s, rnd, nil, plain)
+		if err != nil {
+			t.Fatal(v.name, err)
+		}
+
+		expect := append([]byte{Easy_Version}, nonce...)
+		expect = append(expect, s.Seal(nil, nonce, plain)...)
+		if !bytes.Equal(expect, cipher) {
+			t.Error("expected result did not match computed", v.name, expect, cipher)
+		}
+
+		got, err := Decrypt(s, nil, cipher)
+		if err != nil || !bytes.Equal(plain, got) {
+			t.Error("expected result did not match computed", v.name, plain, got, err)
+		}
+
+		cipher[0] = Easy_Version + 1
+		if _, err = Decrypt(s, nil, cipher); err != godium.ErrUnknownVersion {
+			t.Error("unknown version was accepted", v.name, err)
+		}
+		cipher[0] = Easy_Version
+
+		cipher[1] ^= 1
+		if _, err = Decrypt(s, nil, cipher); err != godium.ErrForgedOrCorrupted {
+			t.Error("forged nonce was accepted", v.name, err)
+		}
+
+		short := cipher[:Easy_HeaderBytes+s.NonceBytes()+s.MacBytes()-1]
+		if _, err = Decrypt(s, nil, short); err != godium.ErrCipherTooShort {
+			t.Error("short box was accepted", v.name, err)
+		}
+	}
+}
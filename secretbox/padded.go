@@ -0,0 +1,76 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretbox
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+)
+
+// padded wraps a SecretBox, padding every message before it is sealed.
+type padded struct {
+	godium.SecretBox
+	padding godium.Padding
+}
+
+// NewPadded wraps the SecretBox, so that Seal and SealDetached pad the message
+// using the padding scheme before encrypting it, and Open and OpenDetached
+// remove the padding after decrypting. The length of the ciphertext then only
+// reveals the padded length of the message.
+func NewPadded(sb godium.SecretBox, padding godium.Padding) (s godium.SecretBox) {
+	s = &padded{
+		SecretBox: sb,
+		padding:   padding,
+	}
+	return
+}
+
+// Seal
+func (s *padded) Seal(dst, nonce, plain []byte) (cipher []byte) {
+	buf := internal.Pad(s.padding, plain)
+	defer godium.Wipe(buf)
+
+	cipher = s.SecretBox.Seal(dst, nonce, buf)
+	return
+}
+
+// SealDetached
+func (s *padded) SealDetached(dst, dstMac, nonce, plain []byte) (cipher, mac []byte) {
+	buf := internal.Pad(s.padding, plain)
+	defer godium.Wipe(buf)
+
+	cipher, mac = s.SecretBox.SealDetached(dst, dstMac, nonce, buf)
+	return
+}
+
+// Open
+func (s *padded) Open(dst, nonce, cipher []byte) (plain []byte, err error) {
+	buf, err := s.SecretBox.Open(dst, nonce, cipher)
+	if err != nil {
+		return
+	}
+
+	plain, err = s.padding.Unpad(buf)
+	if err != nil {
+		godium.Wipe(buf)
+	}
+	return
+}
+
+// OpenDetached
+func (s *padded) OpenDetached(dst, nonce, cipher, mac []byte) (plain []byte, err error) {
+	buf, err := s.SecretBox.OpenDetached(dst, nonce, cipher, mac)
+	if err != nil {
+		return
+	}
+
+	plain, err = s.padding.Unpad(buf)
+	if err != nil {
+		godium.Wipe(buf)
+	}
+	return
+}
@@ -0,0 +1,68 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretbox
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/utils"
+)
+
+// plainBox is a SecretBox that does not encrypt, to observe what the padded
+// wrapper passes to the SecretBox it wraps.
+type plainBox struct{}
+
+func (plainBox) Wipe() {}
+
+func (plainBox) Seal(dst, nonce, plain []byte) (cipher []byte) {
+	cipher = internal.AllocDst(dst, uint64(len(plain)))
+	copy(cipher, plain)
+	return
+}
+
+func (b plainBox) SealDetached(dst, dstMac, nonce, plain []byte) (cipher, mac []byte) {
+	cipher = b.Seal(dst, nonce, plain)
+	return
+}
+
+func (b plainBox) Open(dst, nonce, cipher []byte) (plain []byte, err error) {
+	plain = b.Seal(dst, nonce, cipher)
+	return
+}
+
+func (b plainBox) OpenDetached(dst, nonce, cipher, mac []byte) (plain []byte, err error) {
+	plain = b.Seal(dst, nonce, cipher)
+	return
+}
+
+func (plainBox) KeyBytes() int   { return 0 }
+func (plainBox) MacBytes() int   { return 0 }
+func (plainBox) NonceBytes() int { return 0 }
+
+func TestPadded(t *testing.T) {
+	s := NewPadded(plainBox{}, utils.NewBlockPadding(16))
+
+	for _, msg := range [][]byte{{}, []byte("hi"), bytes.Repeat([]byte{0x80}, 16)} {
+		cipher := s.Seal(nil, nil, msg)
+		expect := utils.Pad(append([]byte{}, msg...), 16)
+		if !bytes.Equal(cipher, expect) {
+			t.Error("expected result did not match computed", expect, cipher)
+		}
+
+		plain, err := s.Open(nil, nil, cipher)
+		if err != nil || !bytes.Equal(plain, msg) {
+			t.Error("expected result did not match computed", msg, plain, err)
+		}
+	}
+
+	if _, err := s.Open(nil, nil, make([]byte, 16)); err != godium.ErrInvalidPadding {
+		t.Error("invalid padding was accepted")
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretbox
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/noncecheck"
+	"go.artemisc.eu/godium/onetimeauth"
+	"go.artemisc.eu/godium/stream"
+)
+
+// boxStream selects the extended nonce stream cipher of a box. A function
+// value would make the keystream buffers escape to the heap.
+type boxStream int
+
+const (
+	boxXSalsa20 boxStream = iota
+	boxXChacha20
+)
+
+// xor xors src with the keystream into dst, starting at block counter ic.
+func (xs boxStream) xor(dst, src, nonce []byte, ic uint64, key []byte) {
+	switch xs {
+	case boxXSalsa20:
+		stream.XSalsa20XORIc(dst, src, nonce, ic, key)
+	case boxXChacha20:
+		stream.XChacha20XORIc(dst, src, nonce, ic, key)
+	}
+}
+
+// recordNonce records the nonce of a seal when nonce reuse is checked.
+func (xs boxStream) recordNonce(key, nonce []byte) {
+	switch xs {
+	case boxXSalsa20:
+		noncecheck.Record("secretbox xsalsa20poly1305", key, nonce[:XSalsa20Poly1305_NonceBytes])
+	case boxXChacha20:
+		noncecheck.Record("secretbox xchacha20poly1305", key, nonce[:XChacha20Poly1305_NonceBytes])
+	}
+}
+
+// zeroBytes is the length of the keystream prefix that is not used to encrypt
+// the message. Its first 32 bytes form the Poly1305 key.
+const zeroBytes = 32
+
+// initBox computes the first keystream block, and keys p with its first 32
+// bytes. The remaining 32 bytes are returned in block0[zeroBytes:], and
+// encrypt the start of the message.
+func initBox(xs boxStream, p *onetimeauth.Poly1305, block0 *[64]byte, key, nonce []byte) {
+	xs.xor(block0[:], block0[:], nonce, 0, key)
+	p.ReKey(block0[:onetimeauth.Poly1305_KeyBytes])
+}
+
+// xorBox xors src with the keystream that follows the Poly1305 key.
+func xorBox(xs boxStream, block0 *[64]byte, dst, src, key, nonce []byte) {
+	n := len(src)
+	if n > len(block0)-zeroBytes {
+		n = len(block0) - zeroBytes
+	}
+
+	for i, v := range src[:n] {
+		dst[i] = v ^ block0[zeroBytes+i]
+	}
+	if len(src) > n {
+		xs.xor(dst[n:], src[n:], nonce, 1, key)
+	}
+}
+
+// sealBox encrypts plain into cipher and writes the Poly1305 tag of the
+// ciphertext to mac, like crypto_secretbox_detached. Only the caller's stack
+// is used, so this is safe for concurrent use and does not allocate.
+func sealBox(xs boxStream, cipher, mac, key, nonce, plain []byte) {
+	var p onetimeauth.Poly1305
+	var block0 [64]byte
+
+	xs.recordNonce(key, nonce)
+	initBox(xs, &p, &block0, key, nonce)
+	xorBox(xs, &block0, cipher, plain, key, nonce)
+
+	p.Write(cipher)
+	p.Sum(mac[:0])
+
+	godium.Wipe(block0[:])
+}
+
+// openBox verifies the tag of cipher, and only decrypts it into plain if it
+// is valid, like crypto_secretbox_open_detached.
+func openBox(xs boxStream, plain, key, nonce, cipher, mac []byte) (err error) {
+	var p onetimeauth.Poly1305
+	var block0 [64]byte
+
+	initBox(xs, &p, &block0, key, nonce)
+
+	p.Write(cipher)
+	if !p.Verify(mac) {
+		err = godium.ErrForgedOrCorrupted
+	} else {
+		xorBox(xs, &block0, plain, cipher, key, nonce)
+	}
+
+	godium.Wipe(block0[:])
+	return
+}
@@ -0,0 +1,199 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretbox
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/noncecheck"
+)
+
+// testPattern returns size bytes of input, byte i set to i % 251.
+func testPattern(size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = byte(i % 251)
+	}
+	return
+}
+
+// testBytes returns size bytes counting up from start.
+func testBytes(start byte, size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = start + byte(i)
+	}
+	return
+}
+
+// skipNonceReuse skips a test that seals under fixed keys and nonces, which
+// the godium_noncecheck build tag reports as nonce reuse. Tests that seal a
+// fixed key and nonce only once call noncecheck.Reset instead.
+func skipNonceReuse(t *testing.T) {
+	if noncecheck.Enabled {
+		t.Skip("seals under fixed keys and nonces")
+	}
+}
+
+// secretboxVectors were computed with libsodium's crypto_secretbox_easy and
+// crypto_secretbox_xchacha20poly1305_easy under the key 00..1f and the nonce
+// 40..57. The hash covers the boxes of testPattern(n) for n up to 600.
+var secretboxVectors = []struct {
+	name  string
+	new   func(key []byte) godium.SecretBox
+	empty string
+	short string
+	hash  string
+}{
+	{"xsalsa20poly1305", NewXSalsa20Poly1305,
+		"25ad7f4489ddd636717f1a6bbc7daf99",
+		"2dfb08f4c565c0be679ee8ed420f383d4a16577a3e54bd2dce507b6a648454fe" +
+			"de265447795649dffccc38d1e5c49d4bf4154b32ca92e023716536521ddf7400" +
+			"b085115e93bb8671b0eb81893b4aa08adf8dfae61f8abafa13addb4ec1034b7c" +
+			"0213224dab494f61c73ee11a8d6b8b99e3905d2b",
+		"513e5952ca6600e562cbc30d7f59f88d635e363decd2f6e47d372f8a134b3f8a"},
+	{"xchacha20poly1305", NewXChacha20Poly1305,
+		"3c6e8a9359304fdc8453180483ac1666",
+		"e8ba3fe255e0692d9e6dd405c7e9ceb33fb6068d4c649ee246b11b9e37fdd279" +
+			"b776bbc18521ccffb5703f02b3caa8c7f4182753f4c55f31a7ddad9583b14bbd" +
+			"a28b9ff7276c65ad5208c77e35391daf56b8402d11d7c257b7fbbe07469794be" +
+			"cb3a2fbb3bd0e2cbeea552af4fef120b3a4a9a11",
+		"d33f9596f9bcbc5e58487ebbb439097e340efdd14604333e74b8312282836644"},
+}
+
+func TestSecretBox(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range secretboxVectors {
+		s := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, s.NonceBytes())
+
+		expect, _ := hex.DecodeString(v.empty)
+		got := s.Seal(nil, nonce, nil)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.name, expect, got)
+		}
+
+		expect, _ = hex.DecodeString(v.short)
+		got = s.Seal(nil, nonce, testPattern(100))
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.name, expect, got)
+		}
+
+		expect, _ = hex.DecodeString(v.hash)
+		h := sha256.New()
+		for n := 0; n < 600; n++ {
+			plain := testPattern(n)
+			box := s.Seal(nil, nonce, plain)
+			h.Write(box)
+
+			c, mac := s.SealDetached(nil, nil, nonce, plain)
+			if !bytes.Equal(box, append(mac, c...)) {
+				t.Error("expected result did not match computed", v.name, n)
+			}
+
+			got, err := s.Open(nil, nonce, box)
+			if err != nil || !bytes.Equal(plain, got) {
+				t.Error("expected result did not match computed", v.name, n, err)
+			}
+			got, err = s.OpenDetached(nil, nonce, c, mac)
+			if err != nil || !bytes.Equal(plain, got) {
+				t.Error("expected result did not match computed", v.name, n, err)
+			}
+		}
+		if sum := h.Sum(nil); !bytes.Equal(expect, sum) {
+			t.Error("expected result did not match computed", v.name, expect, sum)
+		}
+	}
+}
+
+func TestSecretBoxForged(t *testing.T) {
+	noncecheck.Reset()
+
+	for _, v := range secretboxVectors {
+		s := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, s.NonceBytes())
+		box := s.Seal(nil, nonce, testPattern(100))
+
+		for _, i := range []int{0, 20, len(box) - 1} {
+			box[i] ^= 1
+			if _, err := s.Open(nil, nonce, box); err != godium.ErrForgedOrCorrupted {
+				t.Error("forged box was accepted", v.name, i, err)
+			}
+			box[i] ^= 1
+		}
+
+		if _, err := s.Open(nil, nonce, box[:15]); err != godium.ErrCipherTooShort {
+			t.Error("short box was accepted", v.name, err)
+		}
+	}
+}
+
+func TestSecretBoxAllocs(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range secretboxVectors {
+		s := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, s.NonceBytes())
+		plain := testPattern(1000)
+		box := make([]byte, 0, len(plain)+s.MacBytes())
+		out := make([]byte, 0, len(plain))
+
+		allocs := testing.AllocsPerRun(10, func() {
+			b := s.Seal(box, nonce, plain)
+			if _, err := s.Open(out, nonce, b); err != nil {
+				t.Error(v.name, err)
+			}
+		})
+		if allocs != 0 {
+			t.Error("Seal and Open allocated", v.name, allocs)
+		}
+	}
+}
+
+func TestSecretBoxConcurrent(t *testing.T) {
+	skipNonceReuse(t)
+
+	const goroutines, messages = 8, 50
+
+	for _, v := range secretboxVectors {
+		s := v.new(testBytes(0, 32))
+
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+
+				// every goroutine seals different messages under its own
+				// nonces, and compares with a private instance.
+				own := v.new(testBytes(0, 32))
+				nonce := testBytes(byte(g), s.NonceBytes())
+				for i := 0; i < messages; i++ {
+					nonce[0] = byte(i)
+					plain := testPattern(g*97 + i)
+
+					box := s.Seal(nil, nonce, plain)
+					if expect := own.Seal(nil, nonce, plain); !bytes.Equal(expect, box) {
+						t.Error("expected result did not match computed", v.name, g, i)
+						return
+					}
+					if got, err := s.Open(nil, nonce, box); err != nil || !bytes.Equal(plain, got) {
+						t.Error("expected result did not match computed", v.name, g, i, err)
+						return
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+	}
+}
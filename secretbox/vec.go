@@ -0,0 +1,165 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretbox
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+	"go.artemisc.eu/godium/onetimeauth"
+	"go.artemisc.eu/godium/stream"
+)
+
+// vecBox is implemented by the boxes that seal and open scattered buffers
+// directly.
+type vecBox interface {
+	vecStream() (xs boxStream, key []byte)
+}
+
+func (s *xsalsa20poly1305) vecStream() (boxStream, []byte)  { return boxXSalsa20, s.Key }
+func (s *xchacha20poly1305) vecStream() (boxStream, []byte) { return boxXChacha20, s.Key }
+
+// newStream creates the keystream of a box, and keys p with its first 32
+// bytes. The message is encrypted with the keystream that follows.
+func (xs boxStream) newStream(p *onetimeauth.Poly1305, key, nonce []byte) (s godium.Stream) {
+	var polyKey [onetimeauth.Poly1305_KeyBytes]byte
+
+	switch xs {
+	case boxXSalsa20:
+		s = stream.NewXSalsa20(key, nonce)
+	case boxXChacha20:
+		s = stream.NewXChacha20(key, nonce)
+	}
+
+	s.KeyStream(polyKey[:])
+	p.ReKey(polyKey[:])
+	godium.Wipe(polyKey[:])
+	return
+}
+
+// SealVec seals the concatenation of the plain segments, and writes the same
+// bytes as Seal across the dst segments. It returns the number of bytes
+// written, and panics if the dst segments are shorter than that. The boxes of
+// this package work on the segments directly, others copy through a
+// contiguous buffer. The plain and dst segments may only overlap if they are
+// the same memory at the same offsets.
+func SealVec(b godium.SecretBox, dst [][]byte, nonce []byte, plain [][]byte) (n int) {
+	var p onetimeauth.Poly1305
+	var mac [onetimeauth.Poly1305_Bytes]byte
+
+	out, in := internal.NewVec(dst), internal.NewVec(plain)
+
+	v, ok := b.(vecBox)
+	if !ok {
+		// the output of Seal is not always len(plain)+MacBytes(), for
+		// example with padding.
+		buf := internal.Gather(nil, plain)
+		sealed := b.Seal(nil, nonce, buf)
+		godium.Wipe(buf)
+
+		n = len(sealed)
+		if out.Len() < n {
+			panic("secretbox: output segments too short")
+		}
+		out.Write(sealed)
+		return
+	}
+
+	n = in.Len() + b.MacBytes()
+	if out.Len() < n {
+		panic("secretbox: output segments too short")
+	}
+
+	xs, key := v.vecStream()
+	xs.recordNonce(key, nonce)
+	s := xs.newStream(&p, key, nonce)
+
+	// the tag precedes the ciphertext.
+	macOut := out
+	out.Skip(len(mac))
+
+	for in.Len() > 0 {
+		src := in.Next(in.Len())
+		for len(src) > 0 {
+			d := out.Next(len(src))
+			s.XORKeyStream(d, src[:len(d)])
+			p.Write(d)
+			src = src[len(d):]
+		}
+	}
+
+	p.Sum(mac[:0])
+	macOut.Write(mac[:])
+
+	s.Wipe()
+	return
+}
+
+// OpenVec opens the concatenation of the cipher segments, and writes the same
+// bytes as Open across the dst segments. It returns the number of bytes
+// written, and panics if the dst segments are shorter than that. Nothing is
+// written if the message is not authentic. The cipher and dst segments may
+// only overlap if they are the same memory at the same offsets.
+func OpenVec(b godium.SecretBox, dst [][]byte, nonce []byte, cipher [][]byte) (n int, err error) {
+	var p onetimeauth.Poly1305
+	var mac [onetimeauth.Poly1305_Bytes]byte
+
+	out, in := internal.NewVec(dst), internal.NewVec(cipher)
+	if in.Len() < b.MacBytes() {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
+	v, ok := b.(vecBox)
+	if !ok {
+		var plain []byte
+
+		plain, err = b.Open(nil, nonce, internal.Gather(nil, cipher))
+		if err == nil {
+			if out.Len() < len(plain) {
+				godium.Wipe(plain)
+				panic("secretbox: output segments too short")
+			}
+			n = out.Write(plain)
+		}
+
+		godium.Wipe(plain)
+		return
+	}
+
+	mlen := in.Len() - b.MacBytes()
+	if out.Len() < mlen {
+		panic("secretbox: output segments too short")
+	}
+
+	xs, key := v.vecStream()
+	s := xs.newStream(&p, key, nonce)
+	defer s.Wipe()
+
+	// authenticate the ciphertext, and come back to decrypt it.
+	in.Read(mac[:])
+	start := in
+	for in.Len() > 0 {
+		p.Write(in.Next(in.Len()))
+	}
+
+	if !p.Verify(mac[:]) {
+		err = godium.ErrForgedOrCorrupted
+		return
+	}
+
+	for start.Len() > 0 {
+		src := start.Next(start.Len())
+		for len(src) > 0 {
+			d := out.Next(len(src))
+			s.XORKeyStream(d, src[:len(d)])
+			src = src[len(d):]
+		}
+	}
+
+	n = mlen
+	return
+}
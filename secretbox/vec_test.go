@@ -0,0 +1,128 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretbox
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/noncecheck"
+	"go.artemisc.eu/godium/utils"
+)
+
+// segment splits data into segments of random sizes, some of them empty. The
+// segments share the memory of data.
+func segment(r *rand.Rand, data []byte) (segs [][]byte) {
+	for len(data) > 0 {
+		n := r.Intn(len(data)/2 + 2)
+		if n > len(data) {
+			n = len(data)
+		}
+		segs = append(segs, data[:n:n])
+		data = data[n:]
+	}
+	return append(segs, nil)
+}
+
+func TestVec(t *testing.T) {
+	skipNonceReuse(t)
+
+	r := rand.New(rand.NewSource(1))
+
+	for _, v := range secretboxVectors {
+		s := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, s.NonceBytes())
+
+		for _, size := range []int{0, 1, 16, 31, 32, 33, 63, 64, 65, 200, 1000} {
+			for i := 0; i < 10; i++ {
+				plain := testPattern(size)
+				expect := s.Seal(nil, nonce, plain)
+
+				sealed := make([]byte, len(expect))
+				n := SealVec(s, segment(r, sealed), nonce, segment(r, plain))
+				if n != len(expect) || !bytes.Equal(expect, sealed) {
+					t.Fatal("expected result did not match computed", v.name, size, i)
+				}
+
+				opened := make([]byte, size)
+				n, err := OpenVec(s, segment(r, opened), nonce, segment(r, sealed))
+				if err != nil || n != size || !bytes.Equal(plain, opened) {
+					t.Fatal("expected result did not match computed", v.name, size, i, err)
+				}
+			}
+		}
+	}
+}
+
+func TestVecPadded(t *testing.T) {
+	skipNonceReuse(t)
+
+	r := rand.New(rand.NewSource(3))
+
+	for _, v := range secretboxVectors {
+		s := NewPadded(v.new(testBytes(0, 32)), utils.NewBlockPadding(64))
+		nonce := testBytes(0x40, s.NonceBytes())
+
+		for _, size := range []int{0, 1, 63, 64, 65, 200} {
+			plain := testPattern(size)
+			expect := s.Seal(nil, nonce, plain)
+
+			sealed := make([]byte, len(expect))
+			n := SealVec(s, segment(r, sealed), nonce, segment(r, plain))
+			if n != len(expect) || !bytes.Equal(expect, sealed) {
+				t.Fatal("expected result did not match computed", v.name, size, n)
+			}
+
+			opened := make([]byte, size)
+			n, err := OpenVec(s, segment(r, opened), nonce, segment(r, sealed))
+			if err != nil || n != size || !bytes.Equal(plain, opened) {
+				t.Fatal("expected result did not match computed", v.name, size, n, err)
+			}
+
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Error("short output segments were accepted", v.name, size)
+					}
+				}()
+				SealVec(s, segment(r, make([]byte, len(expect)-1)), nonce, segment(r, plain))
+			}()
+		}
+	}
+}
+
+func TestVecForged(t *testing.T) {
+	noncecheck.Reset()
+
+	r := rand.New(rand.NewSource(2))
+
+	for _, v := range secretboxVectors {
+		s := v.new(testBytes(0, 32))
+		nonce := testBytes(0x40, s.NonceBytes())
+		sealed := s.Seal(nil, nonce, testPattern(100))
+
+		for _, i := range []int{0, 50, len(sealed) - 1} {
+			forged := append([]byte(nil), sealed...)
+			forged[i] ^= 1
+
+			opened := make([]byte, 100)
+			n, err := OpenVec(s, segment(r, opened), nonce, segment(r, forged))
+			if err != godium.ErrForgedOrCorrupted || n != 0 {
+				t.Error("forged message was accepted", v.name, i, err)
+			}
+			if !bytes.Equal(opened, make([]byte, 100)) {
+				t.Error("plaintext of a forged message was written", v.name, i)
+			}
+		}
+
+		if _, err := OpenVec(s, nil, nonce, segment(r, sealed[:s.MacBytes()-1])); err != godium.ErrCipherTooShort {
+			t.Error("short ciphertext was accepted", v.name, err)
+		}
+	}
+}
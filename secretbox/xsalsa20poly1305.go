@@ -9,8 +9,6 @@ package secretbox
 import (
 	"go.artemisc.eu/godium"
 	"go.artemisc.eu/godium/internal"
-	"go.artemisc.eu/godium/onetimeauth"
-	"go.artemisc.eu/godium/stream"
 )
 
 const (
@@ -25,11 +23,9 @@ const (
 )
 
 // xsalsa20poly1305 implements the SecretBox interface for the xsalsa20poly1305
-// specification.
+// specification. Only the key is kept, so it is safe for concurrent use.
 type xsalsa20poly1305 struct {
 	godium.Key
-	godium.Stream
-	godium.OneTimeAuth
 }
 
 // New
@@ -41,7 +37,7 @@ func New(key []byte) (s godium.SecretBox) {
 // NewXSalsa20Poly1305
 func NewXSalsa20Poly1305(key []byte) (s godium.SecretBox) {
 	s = &xsalsa20poly1305{
-		Key: key,
+		Key: internal.Copy(key, XSalsa20Poly1305_KeyBytes),
 	}
 	return
 }
@@ -49,55 +45,18 @@ func NewXSalsa20Poly1305(key []byte) (s godium.SecretBox) {
 // Wipe
 func (s *xsalsa20poly1305) Wipe() {
 	godium.Wipe(s.Key)
-	if s.Stream != nil {
-		s.Stream.Wipe()
-	}
-	if s.OneTimeAuth != nil {
-		s.OneTimeAuth.Wipe()
-	}
-}
-
-// initStream
-func (s *xsalsa20poly1305) initStream(key, nonce []byte) {
-	var polyKey [onetimeauth.Poly1305_KeyBytes]byte
-
-	if s.Stream == nil {
-		s.Stream = stream.NewXSalsa20(key, nonce)
-	} else {
-		s.Stream.ReKey(key, nonce)
-	}
-
-	s.Stream.KeyStream(polyKey[:])
-
-	if s.OneTimeAuth == nil {
-		s.OneTimeAuth = onetimeauth.NewPoly1305(polyKey[:])
-	} else {
-		s.OneTimeAuth.ReKey(polyKey[:])
-	}
-
-	godium.Wipe(polyKey[:])
 }
 
 // SealDetached
 func (s *xsalsa20poly1305) SealDetached(dst, dstMac, nonce, plain []byte) (cipher, mac []byte) {
-	subKey := make([]byte, 0, stream.XSalsa20_KeyBytes)
 	cipher = internal.AllocDst(dst, uint64(len(plain)))
 	mac = internal.AllocDst(dstMac, XSalsa20Poly1305_MacBytes)
 
-	// get cipher from subKey
-	s.initStream(subKey, nonce[:XSalsa20Poly1305_NonceBytes])
-
-	s.Stream.XORKeyStream(cipher[XSalsa20Poly1305_MacBytes:], plain)
-
-	// calculate the poly tag
-	s.OneTimeAuth.Write(cipher)
-	s.OneTimeAuth.Sum(mac[:0])
-
-	godium.Wipe(subKey)
+	sealBox(boxXSalsa20, cipher, mac, s.Key, nonce, plain)
 	return
 }
 
-// Seal
+// Seal returns the tag followed by the ciphertext, like crypto_secretbox_xsalsa20poly1305_easy.
 func (s *xsalsa20poly1305) Seal(dst, nonce, plain []byte) (cipher []byte) {
 	mlen := uint64(len(plain))
 
@@ -105,8 +64,8 @@ func (s *xsalsa20poly1305) Seal(dst, nonce, plain []byte) (cipher []byte) {
 
 	// call with slices of len == 0, pointing to the right parts of cipher.
 	_, _ = s.SealDetached(
-		cipher[:0],
 		cipher[XSalsa20Poly1305_MacBytes:XSalsa20Poly1305_MacBytes],
+		cipher[:0],
 		nonce, plain)
 
 	return
@@ -114,34 +73,26 @@ func (s *xsalsa20poly1305) Seal(dst, nonce, plain []byte) (cipher []byte) {
 
 // OpenDetached
 func (s *xsalsa20poly1305) OpenDetached(dst, nonce, cipher, mac []byte) (plain []byte, err error) {
-	subKey := make([]byte, 0, stream.XSalsa20_KeyBytes)
-	cipher = internal.AllocDst(dst, uint64(len(plain)))
-
-	// get cipher from subKey
-	s.initStream(subKey, nonce[:XSalsa20Poly1305_NonceBytes])
-
-	// calculate the poly tag
-	s.OneTimeAuth.Write(cipher)
-	if !s.OneTimeAuth.Verify(mac) {
-		err = godium.ErrForgedOrCorrupted
-		return
-	}
-
-	s.Stream.XORKeyStream(cipher[XSalsa20Poly1305_MacBytes:], plain)
+	plain = internal.AllocDst(dst, uint64(len(cipher)))
 
-	godium.Wipe(subKey)
+	err = openBox(boxXSalsa20, plain, s.Key, nonce, cipher, mac)
 	return
 }
 
 // Open
 func (s *xsalsa20poly1305) Open(dst, nonce, cipher []byte) (plain []byte, err error) {
+	if len(cipher) < XSalsa20Poly1305_MacBytes {
+		err = godium.ErrCipherTooShort
+		return
+	}
+
 	mlen := uint64(len(cipher)) - XSalsa20Poly1305_MacBytes
 	plain = internal.AllocDst(dst, mlen)
 
 	// call with slices of len == 0, pointing to the right parts of the plain
 	plain, err = s.OpenDetached(plain[:0], nonce,
-		cipher[:XSalsa20Poly1305_MacBytes],
-		cipher[XSalsa20Poly1305_MacBytes:])
+		cipher[XSalsa20Poly1305_MacBytes:],
+		cipher[:XSalsa20Poly1305_MacBytes])
 	return
 }
 
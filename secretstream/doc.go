@@ -28,3 +28,9 @@ func New() (s godium.SecretStream) {
 	s = NewXChacha20Poly1305()
 	return
 }
+
+// NewFrom
+func NewFrom(random godium.Random) (s godium.SecretStream) {
+	s = NewXChacha20Poly1305From(random)
+	return
+}
@@ -0,0 +1,53 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretstream
+
+import (
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/internal"
+)
+
+// padded wraps a SecretStream, padding every message before it is pushed.
+type padded struct {
+	godium.SecretStream
+	padding godium.Padding
+}
+
+// NewPadded wraps the SecretStream, so that Push pads every message using the
+// padding scheme before encrypting it, and Pull removes the padding after
+// decrypting. The length of each ciphertext then only reveals the padded
+// length of its message. Both ends of the stream must use the same scheme.
+func NewPadded(s godium.SecretStream, padding godium.Padding) (p godium.SecretStream) {
+	p = &padded{
+		SecretStream: s,
+		padding:      padding,
+	}
+	return
+}
+
+// Push
+func (s *padded) Push(dst, plain, ad []byte, tag byte) (cipher []byte) {
+	buf := internal.Pad(s.padding, plain)
+	defer godium.Wipe(buf)
+
+	cipher = s.SecretStream.Push(dst, buf, ad, tag)
+	return
+}
+
+// Pull
+func (s *padded) Pull(dst, cipher, ad []byte) (plain []byte, tag byte, err error) {
+	buf, tag, err := s.SecretStream.Pull(dst, cipher, ad)
+	if err != nil {
+		return
+	}
+
+	plain, err = s.padding.Unpad(buf)
+	if err != nil {
+		godium.Wipe(buf)
+	}
+	return
+}
@@ -0,0 +1,87 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretstream
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/utils"
+)
+
+func TestPadded(t *testing.T) {
+	key := testKey()
+	padding := utils.NewBlockPadding(16)
+	push := NewPadded(NewXChacha20Poly1305(), padding)
+	pull := NewPadded(NewXChacha20Poly1305(), padding)
+	raw := NewXChacha20Poly1305()
+
+	header := push.InitPush(nil, key)
+	if err := pull.InitPull(header, key); err != nil {
+		t.Fatal(err)
+	}
+	if err := raw.InitPull(header, key); err != nil {
+		t.Fatal(err)
+	}
+
+	// the last vector is a padded TAG_FINAL message
+	for i, v := range xchacha20poly1305Vectors {
+		expect := utils.Pad(append([]byte{}, v.plain...), 16)
+
+		cipher := push.Push(nil, v.plain, v.ad, v.tag)
+		if len(cipher) != len(expect)+XChacha20Poly1305_ABytes {
+			t.Error("unexpected ciphertext length", i, len(cipher))
+		}
+
+		// the wrapped stream sees the padded message
+		plain, tag, err := raw.Pull(nil, cipher, v.ad)
+		if err != nil || tag != v.tag || !bytes.Equal(expect, plain) {
+			t.Error("expected result did not match computed", i, expect, plain, err)
+		}
+
+		plain, tag, err = pull.Pull(nil, cipher, v.ad)
+		if err != nil || tag != v.tag || !bytes.Equal(v.plain, plain) {
+			t.Error("expected result did not match computed", i, v.plain, plain, err)
+		}
+	}
+}
+
+func TestPaddedInvalid(t *testing.T) {
+	key := testKey()
+	push := NewXChacha20Poly1305()
+	pull := NewPadded(NewXChacha20Poly1305(), utils.NewBlockPadding(16))
+
+	header := push.InitPush(nil, key)
+	if err := pull.InitPull(header, key); err != nil {
+		t.Fatal(err)
+	}
+
+	// a message without padding, and a final message with more than a block
+	// of padding, are rejected after authentication
+	messages := []struct {
+		buf []byte
+		tag byte
+	}{
+		{make([]byte, 16), XChacha20Poly1305_TAG_MESSAGE},
+		{utils.Pad([]byte("last"), 32), XChacha20Poly1305_TAG_FINAL},
+	}
+
+	for i, m := range messages {
+		cipher := push.Push(nil, m.buf, nil, m.tag)
+
+		forged := append([]byte{}, cipher...)
+		forged[len(forged)-1] ^= 1
+		if _, _, err := pull.Pull(nil, forged, nil); err != godium.ErrForgedOrCorrupted {
+			t.Error("forged message was accepted", i, err)
+		}
+
+		if _, _, err := pull.Pull(nil, cipher, nil); err != godium.ErrInvalidPadding {
+			t.Error("invalid padding was accepted", i, err)
+		}
+	}
+}
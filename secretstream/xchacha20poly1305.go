@@ -16,6 +16,7 @@ import (
 	"go.artemisc.eu/godium/onetimeauth"
 	"go.artemisc.eu/godium/random"
 	"go.artemisc.eu/godium/stream"
+	"go.artemisc.eu/godium/utils"
 )
 
 const (
@@ -59,12 +60,21 @@ type XChacha20Poly1305 struct {
 	stream godium.Stream
 	poly   *onetimeauth.Poly1305
 	pad    [8]byte
+	random godium.Random
 }
 
 // NewXChacha20Poly1305
 func NewXChacha20Poly1305() (s *XChacha20Poly1305) {
+	s = NewXChacha20Poly1305From(rand)
+	return
+}
+
+// NewXChacha20Poly1305From creates a secretstream that draws the header
+// generated by InitPush from the provided godium.Random.
+func NewXChacha20Poly1305From(random godium.Random) (s *XChacha20Poly1305) {
 	s = new(XChacha20Poly1305)
 	s.poly = new(onetimeauth.Poly1305)
+	s.random = random
 	return
 }
 
@@ -105,7 +115,7 @@ func (s *XChacha20Poly1305) Wipe() {
 func (s *XChacha20Poly1305) InitPush(dst []byte, key godium.Key) (header []byte) {
 	header = internal.AllocDst(dst, XChacha20Poly1305_HeaderBytes)
 
-	rand.Buf(header)
+	s.random.Buf(header)
 	core.HChacha20(s.key[:0], header, key, nil)
 	copy(s.stateINonce(), header[core.HChacha20_InputBytes:])
 
@@ -177,7 +187,7 @@ func (s *XChacha20Poly1305) Push(dst, plain, ad []byte, t byte) (cipher []byte)
 
 	s.stream.ReKey(s.key[:], s.nonce[:])
 	s.stream.KeyStream(block[:])
-	s.poly.ReKey(block[:])
+	s.poly.ReKey(block[:onetimeauth.Poly1305_KeyBytes])
 	godium.Wipe(block[:])
 
 	s.poly.Write(ad)
@@ -191,7 +201,7 @@ func (s *XChacha20Poly1305) Push(dst, plain, ad []byte, t byte) (cipher []byte)
 	c = cipher[1:]
 	s.stream.XORKeyStream(c, plain)
 	s.poly.Write(c[:mlen])
-	s.poly.Write(_pad0[:(0x10-(stream.Chacha20Ietf_BlockBytes+mlen))&0xf])
+	s.poly.Write(_pad0[:(mlen-stream.Chacha20Ietf_BlockBytes+0x10)&0xf])
 
 	binary.LittleEndian.PutUint64(slen[:], adlen)
 	s.poly.Write(slen[:])
@@ -206,8 +216,8 @@ func (s *XChacha20Poly1305) Push(dst, plain, ad []byte, t byte) (cipher []byte)
 		iNonce[i] ^= mac[i]
 	}
 
-	core.Increment(s.stateCounter())
-	if tag.ShouldReKey() || core.IsZero(s.stateCounter()) {
+	utils.Increment(s.stateCounter())
+	if tag.ShouldReKey() || utils.IsZero(s.stateCounter()) {
 		s.ReKey()
 	}
 
@@ -232,7 +242,7 @@ func (s *XChacha20Poly1305) Pull(dst, cipher, ad []byte) (plain []byte, tag byte
 
 	s.stream.ReKey(s.key[:], s.nonce[:])
 	s.stream.KeyStream(block[:])
-	s.poly.ReKey(block[:])
+	s.poly.ReKey(block[:onetimeauth.Poly1305_KeyBytes])
 	godium.Wipe(block[:])
 
 	s.poly.Write(ad)
@@ -246,7 +256,7 @@ func (s *XChacha20Poly1305) Pull(dst, cipher, ad []byte) (plain []byte, tag byte
 
 	c = cipher[1:]
 	s.poly.Write(c[:mlen])
-	s.poly.Write(_pad0[:(0x10-(stream.Chacha20Ietf_BlockBytes+mlen))&0xf])
+	s.poly.Write(_pad0[:(mlen-stream.Chacha20Ietf_BlockBytes+0x10)&0xf])
 
 	binary.LittleEndian.PutUint64(slen[:], adlen)
 	s.poly.Write(slen[:])
@@ -261,15 +271,15 @@ func (s *XChacha20Poly1305) Pull(dst, cipher, ad []byte) (plain []byte, tag byte
 
 	plain = internal.AllocDst(dst, mlen)
 
-	s.stream.XORKeyStream(plain, c)
+	s.stream.XORKeyStream(plain, c[:mlen])
 	iNonce := s.stateINonce()
 	for i := range iNonce {
 		iNonce[i] ^= storedMac[i]
 	}
 
-	core.Increment(s.stateCounter())
+	utils.Increment(s.stateCounter())
 	if XChacha20Poly1305Tag(tag).ShouldReKey() ||
-		core.IsZero(s.stateCounter()) {
+		utils.IsZero(s.stateCounter()) {
 		s.ReKey()
 	}
 
@@ -0,0 +1,119 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secretstream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+func testKey() (key []byte) {
+	key = make([]byte, XChacha20Poly1305_KeyBytes)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return
+}
+
+func testPattern(size int) (p []byte) {
+	p = make([]byte, size)
+	for i := range p {
+		p[i] = byte(i % 251)
+	}
+	return
+}
+
+// xchacha20poly1305Vectors is a stream pushed by libsodium's
+// crypto_secretstream_xchacha20poly1305 under testKey.
+var xchacha20poly1305Header = "e4f05b1de88b46e81c3f7d1385c2b61c2084f414726e9a33"
+var xchacha20poly1305Vectors = []struct {
+	plain  []byte
+	ad     []byte
+	tag    byte
+	cipher string
+}{
+	{[]byte("first"), nil, XChacha20Poly1305_TAG_MESSAGE,
+		"641ac95a5d03d862b6e8e1170577e09af60590317bf6"},
+	{testPattern(100), []byte("additional data"), XChacha20Poly1305_TAG_REKEY,
+		"fb54b82b2a001ee6ea32ca74f1cc35cf621d8b17eb0b8b38f4cad984378ddf50" +
+			"16938759a4fe703dc1b7be5837b9d94174d91567324c4ee5cd4e6bbfbfd283e1" +
+			"7cad506476545bf8eb93f2290917a6f4a8ffa8ad980fc153ddc20daede0a7f03" +
+			"cd82ee2a979c3c544012b5212ec7e0e39c9e6eb5f8"},
+	{[]byte("after rekey"), nil, XChacha20Poly1305_TAG_PUSH,
+		"11ca33b734ae15edfd98e11b6d10dc2d419772cb002962e202385776"},
+	{[]byte("last"), []byte("ad"), XChacha20Poly1305_TAG_FINAL,
+		"fb9d2352e359ede76473941ec146d41c1384237e81"},
+}
+
+func TestXChacha20Poly1305Pull(t *testing.T) {
+	header, _ := hex.DecodeString(xchacha20poly1305Header)
+
+	s := NewXChacha20Poly1305()
+	if err := s.InitPull(header, testKey()); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, v := range xchacha20poly1305Vectors {
+		cipher, _ := hex.DecodeString(v.cipher)
+
+		plain, tag, err := s.Pull(nil, cipher, v.ad)
+		if err != nil {
+			t.Fatal("message", i, err)
+		}
+		if !bytes.Equal(v.plain, plain) {
+			t.Error("expected result did not match computed", i, v.plain, plain)
+		}
+		if tag != v.tag {
+			t.Error("expected result did not match computed", i, v.tag, tag)
+		}
+	}
+}
+
+func TestXChacha20Poly1305PushPull(t *testing.T) {
+	key := testKey()
+	push := NewXChacha20Poly1305()
+	pull := NewXChacha20Poly1305()
+
+	header := push.InitPush(nil, key)
+	if err := pull.InitPull(header, key); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, v := range xchacha20poly1305Vectors {
+		cipher := push.Push(nil, v.plain, v.ad, v.tag)
+		if len(cipher) != len(v.plain)+XChacha20Poly1305_ABytes {
+			t.Error("unexpected ciphertext length", i, len(cipher))
+		}
+
+		plain, tag, err := pull.Pull(nil, cipher, v.ad)
+		if err != nil {
+			t.Fatal("message", i, err)
+		}
+		if !bytes.Equal(v.plain, plain) || tag != v.tag {
+			t.Error("expected result did not match computed", i, v.plain, plain)
+		}
+	}
+}
+
+func TestXChacha20Poly1305Forged(t *testing.T) {
+	header, _ := hex.DecodeString(xchacha20poly1305Header)
+	v := xchacha20poly1305Vectors[1]
+	cipher, _ := hex.DecodeString(xchacha20poly1305Vectors[0].cipher)
+
+	s := NewXChacha20Poly1305()
+	s.InitPull(header, testKey())
+	s.Pull(nil, cipher, nil)
+
+	cipher, _ = hex.DecodeString(v.cipher)
+	cipher[len(cipher)/2] ^= 1
+	if _, _, err := s.Pull(nil, cipher, v.ad); err != godium.ErrForgedOrCorrupted {
+		t.Error("forged message was accepted", err)
+	}
+}
@@ -33,7 +33,11 @@ func NewVerifier(key godium.PublicKey) (v godium.SignVerifier) {
 
 // KeyPair
 func KeyPair(random godium.Random) (s godium.Sign, err error) {
-	s, err = KeyPairEd25519(random)
+	ed, err := KeyPairEd25519(random)
+	if err != nil {
+		return
+	}
+	s = ed
 	return
 }
 
@@ -52,12 +52,20 @@ func KeyPairEd25519(random godium.Random) (s *Ed25519Sign, err error) {
 	return
 }
 
-// KeyPairSeedEd25519
+// KeyPairSeedEd25519 deterministically derives a key pair from the seed, like
+// crypto_sign_seed_keypair. It panics if the seed is not Ed25519_SeedBytes
+// long.
 func KeyPairSeedEd25519(seed []byte) (s *Ed25519Sign) {
+	if len(seed) != Ed25519_SeedBytes {
+		panic("sign: invalid Ed25519 seed length")
+	}
+
 	seed = internal.Copy(seed, SeedBytes)
 	defer godium.Wipe(seed)
 
-	var digest [32]byte
+	var digest [hash.Sha512_Bytes]byte
+	defer godium.Wipe(digest[:])
+
 	hash.SumSha512(digest[:0], seed[:Ed25519_SeedBytes])
 	digest[0] &= 248
 	digest[31] &= 127
@@ -136,6 +144,11 @@ func (s *Ed25519Sign) PublicKey() godium.PublicKey {
 	return internal.Copy(s.public, Ed25519_PublicKeyBytes)
 }
 
+// SecretKey
+func (s *Ed25519Sign) SecretKey() godium.PrivateKey {
+	return internal.Copy(s.private, Ed25519_SecretKeyBytes)
+}
+
 func (s *Ed25519Sign) PublicKeyBytes() (c int) { return Ed25519_PublicKeyBytes }
 func (s *Ed25519Sign) SecretKeyBytes() (c int) { return Ed25519_SecretKeyBytes }
 func (s *Ed25519Sign) Bytes() (c int)          { return Ed25519_Bytes }
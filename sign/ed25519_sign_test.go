@@ -0,0 +1,51 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package sign
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// TestKeyPairSeed uses a vector computed with libsodium's
+// crypto_sign_seed_keypair and crypto_sign_detached.
+func TestKeyPairSeed(t *testing.T) {
+	var seed [SeedBytes]byte
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	expectPk, _ := hex.DecodeString("03a107bff3ce10be1d70dd18e74bc09967e4d6309ba50d5f1ddc8664125531b8")
+	expectSig, _ := hex.DecodeString("90ae7c2daeb769ad9f9dbb948f9779747a3b317e8d6eec62c3b0a9715c7146b0" +
+		"74394558f327246fc6a8754132bb14e6e3d79358bd9128034ee7c22639395907")
+	msg := []byte("godium")
+
+	s := KeyPairSeed(seed[:])
+	if !bytes.Equal(expectPk, s.PublicKey()) {
+		t.Error("expected public key did not match computed", expectPk, s.PublicKey())
+	}
+
+	sig := s.SignDetached(nil, msg)
+	if !bytes.Equal(expectSig, sig) {
+		t.Error("expected result did not match computed", expectSig, sig)
+	}
+	if !NewVerifier(s.PublicKey()).VerifyDetached(sig, msg) {
+		t.Error("signature did not verify")
+	}
+
+	for _, n := range []int{0, SeedBytes - 1, SeedBytes + 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("seed of invalid length was accepted", n)
+				}
+			}()
+			KeyPairSeed(make([]byte, n))
+		}()
+	}
+}
@@ -7,8 +7,11 @@
 package stream
 
 import (
-	"github.com/Yawning/chacha20"
+	"encoding/binary"
+
 	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/core"
+	"go.artemisc.eu/godium/noncecheck"
 )
 
 const (
@@ -23,73 +26,209 @@ const (
 	XChacha20_KeyBytes   = 32
 	XChacha20_NonceBytes = 24
 	XChacha20_BlockBytes = 64
+
+	// chacha20IetfBlocksMax is the number of blocks an IETF stream can produce
+	// with its 32 bit counter.
+	chacha20IetfBlocksMax = 1 << 32
 )
 
-// chacha20 implements the chacha20, chacha20ietf and xchacha20 variants of the
-// chacha20 stream cipher.
+// chacha20Impl implements the chacha20, chacha20ietf and xchacha20 variants of
+// the chacha20 stream cipher. The original variant and xchacha20 use a 64 bit
+// block counter, the IETF variant a 32 bit counter and a longer nonce.
 type chacha20Impl struct {
-	*chacha20.Cipher
-	key   []byte
-	nonce []byte
+	state      [16]uint32
+	block      [Chacha20_BlockBytes]byte
+	off        int
+	counter    uint64
+	nonceBytes int
 }
 
 // NewChacha20
 func NewChacha20(key, nonce []byte) (s godium.Stream) {
-	c, _ := chacha20.NewCipher(key, nonce)
-	s = &chacha20Impl{
-		Cipher: c,
-		key:    key[:Chacha20_KeyBytes],
-		nonce:  nonce[:Chacha20_NonceBytes],
-	}
+	s = newChacha20(Chacha20_NonceBytes, key, nonce)
 	return
 }
 
 // NewChacha20Ietf
 func NewChacha20Ietf(key, nonce []byte) (s godium.Stream) {
-	c, _ := chacha20.NewCipher(key, nonce)
-	s = &chacha20Impl{
-		Cipher: c,
-		key:    key[:Chacha20Ietf_KeyBytes],
-		nonce:  nonce[:Chacha20Ietf_NonceBytes],
-	}
+	s = newChacha20(Chacha20Ietf_NonceBytes, key, nonce)
 	return
 }
 
 // NewXChacha20
 func NewXChacha20(key, nonce []byte) (s godium.Stream) {
-	c, _ := chacha20.NewCipher(key, nonce)
-	s = &chacha20Impl{
-		Cipher: c,
-		key:    key[:XChacha20_KeyBytes],
-		nonce:  nonce[:XChacha20_NonceBytes],
+	s = newChacha20(XChacha20_NonceBytes, key, nonce)
+	return
+}
+
+// Chacha20XORIc xors src with the keystream into dst, starting at block
+// counter ic, like crypto_stream_chacha20_xor_ic. It does not allocate.
+func Chacha20XORIc(dst, src, nonce []byte, ic uint64, key []byte) {
+	xorIcChacha20(Chacha20_NonceBytes, dst, src, nonce, ic, key)
+}
+
+// Chacha20IetfXORIc xors src with the keystream into dst, starting at block
+// counter ic, like crypto_stream_chacha20_ietf_xor_ic. It does not allocate.
+func Chacha20IetfXORIc(dst, src, nonce []byte, ic uint32, key []byte) {
+	xorIcChacha20(Chacha20Ietf_NonceBytes, dst, src, nonce, uint64(ic), key)
+}
+
+// XChacha20XORIc xors src with the keystream into dst, starting at block
+// counter ic, like crypto_stream_xchacha20_xor_ic. It does not allocate.
+func XChacha20XORIc(dst, src, nonce []byte, ic uint64, key []byte) {
+	xorIcChacha20(XChacha20_NonceBytes, dst, src, nonce, ic, key)
+}
+
+// xorIcChacha20 keeps the state of the one-shot functions on the stack.
+func xorIcChacha20(nonceBytes int, dst, src, nonce []byte, ic uint64, key []byte) {
+	s := chacha20Impl{
+		nonceBytes: nonceBytes,
+	}
+	s.ReKey(key, nonce)
+	s.counter = ic
+	s.XORKeyStream(dst, src)
+	s.Wipe()
+}
+
+// newChacha20 creates the variant that uses nonces of nonceBytes bytes.
+func newChacha20(nonceBytes int, key, nonce []byte) (c *chacha20Impl) {
+	c = &chacha20Impl{
+		nonceBytes: nonceBytes,
 	}
+	c.ReKey(key, nonce)
 	return
 }
 
+// ietf returns whether the stream uses the 32 bit counter of RFC 8439.
+func (s *chacha20Impl) ietf() bool {
+	return s.nonceBytes == Chacha20Ietf_NonceBytes
+}
+
+// Wipe erases the key, the nonce and the buffered keystream.
 func (s *chacha20Impl) Wipe() {
-	s.Cipher.Reset()
+	s.state = [16]uint32{}
+	godium.Wipe(s.block[:])
+	s.off = len(s.block)
+	s.counter = 0
 }
 
+// ReKey sets a new key and nonce, and starts at block 0. XChaCha20 derives
+// the key from the first 16 bytes of the nonce with HChaCha20. It panics if
+// the key is not 32 bytes long.
 func (s *chacha20Impl) ReKey(key, nonce []byte) {
-	s.Cipher.ReKey(key, nonce)
-	s.key = key[:len(s.key)]
-	s.nonce = nonce[:len(s.nonce)]
+	var subKey [core.HChacha20_OutputBytes]byte
+
+	if len(key) != Chacha20_KeyBytes {
+		panic("stream: invalid ChaCha20 key length")
+	}
+	nonce = nonce[:s.nonceBytes]
+	noncecheck.RecordStream("stream chacha20", key, nonce)
+
+	if s.nonceBytes == XChacha20_NonceBytes {
+		key = core.HChacha20(subKey[:0], nonce, key, nil)
+		nonce = nonce[core.HChacha20_InputBytes:]
+	}
+
+	copy(s.state[:4], chacha20Sigma[:])
+	for i := 0; i < 8; i++ {
+		s.state[4+i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+
+	// the nonce fills the last words of the state, the counter the rest.
+	s.state[12], s.state[13] = 0, 0
+	for i, n := 16-len(nonce)/4, 0; i < 16; i, n = i+1, n+4 {
+		s.state[i] = binary.LittleEndian.Uint32(nonce[n:])
+	}
+
+	godium.Wipe(subKey[:])
+	godium.Wipe(s.block[:])
+	s.off = len(s.block)
+	s.counter = 0
 }
 
+// XORKeyStream implements cipher.Stream. It panics if dst is shorter than
+// src, or if an IETF stream would run out of counter values.
 func (s *chacha20Impl) XORKeyStream(dst, src []byte) {
-	s.Cipher.XORKeyStream(dst, src)
+	if len(dst) < len(src) {
+		panic("stream: output smaller than input")
+	}
+	dst = dst[:len(src)]
+
+	// use the keystream left in the buffered block first.
+	if s.off < len(s.block) {
+		n := len(src)
+		if rem := len(s.block) - s.off; n > rem {
+			n = rem
+		}
+		for i, v := range src[:n] {
+			dst[i] = v ^ s.block[s.off+i]
+		}
+		s.off += n
+		dst, src = dst[n:], src[n:]
+	}
+
+	if len(src) == 0 {
+		return
+	}
+
+	blocks := uint64(len(src)+Chacha20_BlockBytes-1) / Chacha20_BlockBytes
+	if s.ietf() && blocks > chacha20IetfBlocksMax-s.counter {
+		panic("stream: ChaCha20 IETF counter overflow")
+	}
+
+	full := len(src) &^ (Chacha20_BlockBytes - 1)
+	s.xorBlocks(dst[:full], src[:full])
+
+	if rem := len(src) - full; rem > 0 {
+		godium.Wipe(s.block[:])
+		s.xorBlocks(s.block[:], s.block[:])
+		for i, v := range src[full:] {
+			dst[full+i] = v ^ s.block[i]
+		}
+		s.off = rem
+	}
 }
 
+// xorBlocks xors the complete blocks in src with the keystream into dst, and
+// advances the counter. The kernels work on a 32 bit counter, so the input is
+// split where the lower half of a 64 bit counter wraps around.
+func (s *chacha20Impl) xorBlocks(dst, src []byte) {
+	for len(src) > 0 {
+		n := len(src)
+		if limit := (1<<32 - s.counter&(1<<32-1)) * Chacha20_BlockBytes; uint64(n) > limit {
+			n = int(limit)
+		}
+
+		s.state[12] = uint32(s.counter)
+		if !s.ietf() {
+			s.state[13] = uint32(s.counter >> 32)
+		}
+		chacha20Blocks(&s.state, dst[:n], src[:n])
+
+		s.counter += uint64(n / Chacha20_BlockBytes)
+		dst, src = dst[n:], src[n:]
+	}
+}
+
+// KeyStream writes len(dst) bytes of keystream to dst.
 func (s *chacha20Impl) KeyStream(dst []byte) {
-	s.Cipher.KeyStream(dst)
+	godium.Wipe(dst)
+	s.XORKeyStream(dst, dst)
 }
 
+// Seek sets the block counter, discarding any buffered keystream. It panics
+// if the counter does not fit the 32 bit counter of an IETF stream.
 func (s *chacha20Impl) Seek(counter uint64) (st godium.Stream) {
+	if s.ietf() && counter >= chacha20IetfBlocksMax {
+		panic("stream: ChaCha20 IETF counter overflow")
+	}
+
 	st = s
-	_ = s.Cipher.Seek(counter)
+	s.counter = counter
+	s.off = len(s.block)
 	return
 }
 
-func (s *chacha20Impl) KeyBytes() int   { return len(s.key) }
-func (s *chacha20Impl) NonceBytes() int { return len(s.nonce) }
+func (s *chacha20Impl) KeyBytes() int   { return Chacha20_KeyBytes }
+func (s *chacha20Impl) NonceBytes() int { return s.nonceBytes }
 func (s *chacha20Impl) BlockBytes() int { return Chacha20_BlockBytes }
@@ -0,0 +1,61 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+package stream
+
+import (
+	"go.artemisc.eu/godium/internal/cpu"
+)
+
+const (
+	chacha20SSSE3Bytes = 4 * Chacha20_BlockBytes
+	chacha20AVX2Bytes  = 8 * Chacha20_BlockBytes
+)
+
+var (
+	useAVX2  = cpu.X86.HasAVX2
+	useSSSE3 = cpu.X86.HasSSSE3
+)
+
+// chacha20BlocksAVX2 processes 8 blocks at a time, len(src) must be a
+// multiple of 512 bytes.
+//
+//go:noescape
+func chacha20BlocksAVX2(s *[16]uint32, dst, src []byte)
+
+// chacha20BlocksSSSE3 processes 4 blocks at a time, len(src) must be a
+// multiple of 256 bytes.
+//
+//go:noescape
+func chacha20BlocksSSSE3(s *[16]uint32, dst, src []byte)
+
+// chacha20Blocks xors src with the keystream into dst, see
+// chacha20BlocksGeneric. The widest kernel supported by the processor handles
+// as much of the input as it can, the rest is passed on to narrower kernels.
+func chacha20Blocks(s *[16]uint32, dst, src []byte) {
+	counter := s[12]
+
+	if useAVX2 && len(src) >= chacha20AVX2Bytes {
+		n := len(src) &^ (chacha20AVX2Bytes - 1)
+		chacha20BlocksAVX2(s, dst[:n], src[:n])
+		s[12] += uint32(n / Chacha20_BlockBytes)
+		dst, src = dst[n:], src[n:]
+	}
+
+	if useSSSE3 && len(src) >= chacha20SSSE3Bytes {
+		n := len(src) &^ (chacha20SSSE3Bytes - 1)
+		chacha20BlocksSSSE3(s, dst[:n], src[:n])
+		s[12] += uint32(n / Chacha20_BlockBytes)
+		dst, src = dst[n:], src[n:]
+	}
+
+	if len(src) > 0 {
+		chacha20BlocksGeneric(s, dst, src)
+	}
+	s[12] = counter
+}
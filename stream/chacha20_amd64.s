@@ -0,0 +1,652 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+#include "textflag.h"
+
+// The kernels keep word i of every block in register i, so that one
+// instruction works on the same word of 4 (SSSE3) or 8 (AVX2) blocks. One
+// register is spilled to the stack to rotate by 12 and 7 bits. At the end
+// the words are transposed back into blocks.
+
+DATA ·chacha20Rol16<>+0x00(SB)/8, $0x0504070601000302
+DATA ·chacha20Rol16<>+0x08(SB)/8, $0x0D0C0F0E09080B0A
+DATA ·chacha20Rol16<>+0x10(SB)/8, $0x0504070601000302
+DATA ·chacha20Rol16<>+0x18(SB)/8, $0x0D0C0F0E09080B0A
+GLOBL ·chacha20Rol16<>(SB), (NOPTR+RODATA), $32
+
+DATA ·chacha20Rol8<>+0x00(SB)/8, $0x0605040702010003
+DATA ·chacha20Rol8<>+0x08(SB)/8, $0x0E0D0C0F0A09080B
+DATA ·chacha20Rol8<>+0x10(SB)/8, $0x0605040702010003
+DATA ·chacha20Rol8<>+0x18(SB)/8, $0x0E0D0C0F0A09080B
+GLOBL ·chacha20Rol8<>(SB), (NOPTR+RODATA), $32
+
+// chacha20Lanes holds the counter offset of every lane.
+DATA ·chacha20Lanes<>+0x00(SB)/4, $0
+DATA ·chacha20Lanes<>+0x04(SB)/4, $1
+DATA ·chacha20Lanes<>+0x08(SB)/4, $2
+DATA ·chacha20Lanes<>+0x0C(SB)/4, $3
+DATA ·chacha20Lanes<>+0x10(SB)/4, $4
+DATA ·chacha20Lanes<>+0x14(SB)/4, $5
+DATA ·chacha20Lanes<>+0x18(SB)/4, $6
+DATA ·chacha20Lanes<>+0x1C(SB)/4, $7
+GLOBL ·chacha20Lanes<>(SB), (NOPTR+RODATA), $32
+
+DATA ·chacha20Four<>+0x00(SB)/8, $0x0000000400000004
+DATA ·chacha20Four<>+0x08(SB)/8, $0x0000000400000004
+GLOBL ·chacha20Four<>(SB), (NOPTR+RODATA), $16
+
+DATA ·chacha20Eight<>+0x00(SB)/8, $0x0000000800000008
+DATA ·chacha20Eight<>+0x08(SB)/8, $0x0000000800000008
+DATA ·chacha20Eight<>+0x10(SB)/8, $0x0000000800000008
+DATA ·chacha20Eight<>+0x18(SB)/8, $0x0000000800000008
+GLOBL ·chacha20Eight<>(SB), (NOPTR+RODATA), $32
+
+#define ROTL_SSE(n, v, t) \
+	MOVO  v, t;           \
+	PSLLL $n, v;          \
+	PSRLL $(32-n), t;     \
+	PXOR  t, v
+
+// ROUND_SSSE3 computes 4 quarter rounds on 4 blocks. The spill slot follows
+// the input and working areas of the frame.
+#define ROUND_SSSE3(a0, b0, c0, d0, a1, b1, c1, d1, a2, b2, c2, d2, a3, b3, c3, d3) \
+	PADDL  b0, a0; PADDL b1, a1; PADDL b2, a2; PADDL b3, a3;                            \
+	PXOR   a0, d0; PXOR a1, d1; PXOR a2, d2; PXOR a3, d3;                               \
+	PSHUFB ·chacha20Rol16<>(SB), d0; PSHUFB ·chacha20Rol16<>(SB), d1;                   \
+	PSHUFB ·chacha20Rol16<>(SB), d2; PSHUFB ·chacha20Rol16<>(SB), d3;                   \
+	PADDL  d0, c0; PADDL d1, c1; PADDL d2, c2; PADDL d3, c3;                            \
+	PXOR   c0, b0; PXOR c1, b1; PXOR c2, b2; PXOR c3, b3;                               \
+	MOVOU  c0, 512(SP);                                                                 \
+	ROTL_SSE(12, b0, c0); ROTL_SSE(12, b1, c0); ROTL_SSE(12, b2, c0); ROTL_SSE(12, b3, c0); \
+	MOVOU  512(SP), c0;                                                                 \
+	PADDL  b0, a0; PADDL b1, a1; PADDL b2, a2; PADDL b3, a3;                            \
+	PXOR   a0, d0; PXOR a1, d1; PXOR a2, d2; PXOR a3, d3;                               \
+	PSHUFB ·chacha20Rol8<>(SB), d0; PSHUFB ·chacha20Rol8<>(SB), d1;                     \
+	PSHUFB ·chacha20Rol8<>(SB), d2; PSHUFB ·chacha20Rol8<>(SB), d3;                     \
+	PADDL  d0, c0; PADDL d1, c1; PADDL d2, c2; PADDL d3, c3;                            \
+	PXOR   c0, b0; PXOR c1, b1; PXOR c2, b2; PXOR c3, b3;                               \
+	MOVOU  c0, 512(SP);                                                                 \
+	ROTL_SSE(7, b0, c0); ROTL_SSE(7, b1, c0); ROTL_SSE(7, b2, c0); ROTL_SSE(7, b3, c0);    \
+	MOVOU  512(SP), c0
+
+#define ROTL_AVX2(n, v, t) \
+	VPSLLD $n, v, t;       \
+	VPSRLD $(32-n), v, v;  \
+	VPOR   t, v, v
+
+// ROUND_AVX2 computes 4 quarter rounds on 8 blocks.
+#define ROUND_AVX2(a0, b0, c0, d0, a1, b1, c1, d1, a2, b2, c2, d2, a3, b3, c3, d3) \
+	VPADDD  b0, a0, a0; VPADDD b1, a1, a1; VPADDD b2, a2, a2; VPADDD b3, a3, a3;       \
+	VPXOR   a0, d0, d0; VPXOR a1, d1, d1; VPXOR a2, d2, d2; VPXOR a3, d3, d3;          \
+	VPSHUFB ·chacha20Rol16<>(SB), d0, d0; VPSHUFB ·chacha20Rol16<>(SB), d1, d1;        \
+	VPSHUFB ·chacha20Rol16<>(SB), d2, d2; VPSHUFB ·chacha20Rol16<>(SB), d3, d3;        \
+	VPADDD  d0, c0, c0; VPADDD d1, c1, c1; VPADDD d2, c2, c2; VPADDD d3, c3, c3;       \
+	VPXOR   c0, b0, b0; VPXOR c1, b1, b1; VPXOR c2, b2, b2; VPXOR c3, b3, b3;          \
+	VMOVDQU c0, 1024(SP);                                                              \
+	ROTL_AVX2(12, b0, c0); ROTL_AVX2(12, b1, c0); ROTL_AVX2(12, b2, c0); ROTL_AVX2(12, b3, c0); \
+	VMOVDQU 1024(SP), c0;                                                              \
+	VPADDD  b0, a0, a0; VPADDD b1, a1, a1; VPADDD b2, a2, a2; VPADDD b3, a3, a3;       \
+	VPXOR   a0, d0, d0; VPXOR a1, d1, d1; VPXOR a2, d2, d2; VPXOR a3, d3, d3;          \
+	VPSHUFB ·chacha20Rol8<>(SB), d0, d0; VPSHUFB ·chacha20Rol8<>(SB), d1, d1;          \
+	VPSHUFB ·chacha20Rol8<>(SB), d2, d2; VPSHUFB ·chacha20Rol8<>(SB), d3, d3;          \
+	VPADDD  d0, c0, c0; VPADDD d1, c1, c1; VPADDD d2, c2, c2; VPADDD d3, c3, c3;       \
+	VPXOR   c0, b0, b0; VPXOR c1, b1, b1; VPXOR c2, b2, b2; VPXOR c3, b3, b3;          \
+	VMOVDQU c0, 1024(SP);                                                              \
+	ROTL_AVX2(7, b0, c0); ROTL_AVX2(7, b1, c0); ROTL_AVX2(7, b2, c0); ROTL_AVX2(7, b3, c0);     \
+	VMOVDQU 1024(SP), c0
+
+// func chacha20BlocksSSSE3(s *[16]uint32, dst, src []byte)
+// Requires: SSSE3
+//
+// The frame holds the input words at 0(SP), the output of the rounds at
+// 256(SP) and the spill slot at 512(SP).
+TEXT ·chacha20BlocksSSSE3(SB), 0, $528-56
+	MOVQ s+0(FP), AX
+	MOVQ dst_base+8(FP), DI
+	MOVQ src_base+32(FP), SI
+	MOVQ src_len+40(FP), CX
+	TESTQ CX, CX
+	JZ    ssse3_done
+
+	// broadcast every word of the state, lane i uses counter + i.
+	MOVOU 0(AX), X0
+	PSHUFD $0x00, X0, X1
+	MOVOU X1, 0(SP)
+	PSHUFD $0x55, X0, X1
+	MOVOU X1, 16(SP)
+	PSHUFD $0xAA, X0, X1
+	MOVOU X1, 32(SP)
+	PSHUFD $0xFF, X0, X1
+	MOVOU X1, 48(SP)
+	MOVOU 16(AX), X0
+	PSHUFD $0x00, X0, X1
+	MOVOU X1, 64(SP)
+	PSHUFD $0x55, X0, X1
+	MOVOU X1, 80(SP)
+	PSHUFD $0xAA, X0, X1
+	MOVOU X1, 96(SP)
+	PSHUFD $0xFF, X0, X1
+	MOVOU X1, 112(SP)
+	MOVOU 32(AX), X0
+	PSHUFD $0x00, X0, X1
+	MOVOU X1, 128(SP)
+	PSHUFD $0x55, X0, X1
+	MOVOU X1, 144(SP)
+	PSHUFD $0xAA, X0, X1
+	MOVOU X1, 160(SP)
+	PSHUFD $0xFF, X0, X1
+	MOVOU X1, 176(SP)
+	MOVOU 48(AX), X0
+	PSHUFD $0x00, X0, X1
+	MOVOU ·chacha20Lanes<>(SB), X2
+	PADDL X2, X1
+	MOVOU X1, 192(SP)
+	PSHUFD $0x55, X0, X1
+	MOVOU X1, 208(SP)
+	PSHUFD $0xAA, X0, X1
+	MOVOU X1, 224(SP)
+	PSHUFD $0xFF, X0, X1
+	MOVOU X1, 240(SP)
+
+ssse3_loop:
+	MOVOU 0(SP), X0
+	MOVOU 16(SP), X1
+	MOVOU 32(SP), X2
+	MOVOU 48(SP), X3
+	MOVOU 64(SP), X4
+	MOVOU 80(SP), X5
+	MOVOU 96(SP), X6
+	MOVOU 112(SP), X7
+	MOVOU 128(SP), X8
+	MOVOU 144(SP), X9
+	MOVOU 160(SP), X10
+	MOVOU 176(SP), X11
+	MOVOU 192(SP), X12
+	MOVOU 208(SP), X13
+	MOVOU 224(SP), X14
+	MOVOU 240(SP), X15
+	MOVQ  $10, DX
+
+ssse3_rounds:
+	ROUND_SSSE3(X0, X4, X8, X12, X1, X5, X9, X13, X2, X6, X10, X14, X3, X7, X11, X15)
+	ROUND_SSSE3(X0, X5, X10, X15, X1, X6, X11, X12, X2, X7, X8, X13, X3, X4, X9, X14)
+	DECQ DX
+	JNZ  ssse3_rounds
+
+	MOVOU X0, 256(SP)
+	MOVOU X1, 272(SP)
+	MOVOU X2, 288(SP)
+	MOVOU X3, 304(SP)
+	MOVOU X4, 320(SP)
+	MOVOU X5, 336(SP)
+	MOVOU X6, 352(SP)
+	MOVOU X7, 368(SP)
+	MOVOU X8, 384(SP)
+	MOVOU X9, 400(SP)
+	MOVOU X10, 416(SP)
+	MOVOU X11, 432(SP)
+	MOVOU X12, 448(SP)
+	MOVOU X13, 464(SP)
+	MOVOU X14, 480(SP)
+	MOVOU X15, 496(SP)
+
+	// words 0 to 3 of every block
+	MOVOU 256(SP), X0
+	MOVOU 0(SP), X4
+	PADDL X4, X0
+	MOVOU 272(SP), X1
+	MOVOU 16(SP), X5
+	PADDL X5, X1
+	MOVOU 288(SP), X2
+	MOVOU 32(SP), X6
+	PADDL X6, X2
+	MOVOU 304(SP), X3
+	MOVOU 48(SP), X7
+	PADDL X7, X3
+	MOVO       X0, X4
+	PUNPCKLLQ  X1, X4
+	MOVO       X2, X5
+	PUNPCKLLQ  X3, X5
+	PUNPCKHLQ  X1, X0
+	PUNPCKHLQ  X3, X2
+	MOVO       X4, X1
+	PUNPCKLQDQ X5, X1
+	PUNPCKHQDQ X5, X4
+	MOVO       X0, X3
+	PUNPCKLQDQ X2, X3
+	PUNPCKHQDQ X2, X0
+	MOVOU 0(SI), X8
+	PXOR  X8, X1
+	MOVOU X1, 0(DI)
+	MOVOU 64(SI), X8
+	PXOR  X8, X4
+	MOVOU X4, 64(DI)
+	MOVOU 128(SI), X8
+	PXOR  X8, X3
+	MOVOU X3, 128(DI)
+	MOVOU 192(SI), X8
+	PXOR  X8, X0
+	MOVOU X0, 192(DI)
+
+	// words 4 to 7 of every block
+	MOVOU 320(SP), X0
+	MOVOU 64(SP), X4
+	PADDL X4, X0
+	MOVOU 336(SP), X1
+	MOVOU 80(SP), X5
+	PADDL X5, X1
+	MOVOU 352(SP), X2
+	MOVOU 96(SP), X6
+	PADDL X6, X2
+	MOVOU 368(SP), X3
+	MOVOU 112(SP), X7
+	PADDL X7, X3
+	MOVO       X0, X4
+	PUNPCKLLQ  X1, X4
+	MOVO       X2, X5
+	PUNPCKLLQ  X3, X5
+	PUNPCKHLQ  X1, X0
+	PUNPCKHLQ  X3, X2
+	MOVO       X4, X1
+	PUNPCKLQDQ X5, X1
+	PUNPCKHQDQ X5, X4
+	MOVO       X0, X3
+	PUNPCKLQDQ X2, X3
+	PUNPCKHQDQ X2, X0
+	MOVOU 16(SI), X8
+	PXOR  X8, X1
+	MOVOU X1, 16(DI)
+	MOVOU 80(SI), X8
+	PXOR  X8, X4
+	MOVOU X4, 80(DI)
+	MOVOU 144(SI), X8
+	PXOR  X8, X3
+	MOVOU X3, 144(DI)
+	MOVOU 208(SI), X8
+	PXOR  X8, X0
+	MOVOU X0, 208(DI)
+
+	// words 8 to 11 of every block
+	MOVOU 384(SP), X0
+	MOVOU 128(SP), X4
+	PADDL X4, X0
+	MOVOU 400(SP), X1
+	MOVOU 144(SP), X5
+	PADDL X5, X1
+	MOVOU 416(SP), X2
+	MOVOU 160(SP), X6
+	PADDL X6, X2
+	MOVOU 432(SP), X3
+	MOVOU 176(SP), X7
+	PADDL X7, X3
+	MOVO       X0, X4
+	PUNPCKLLQ  X1, X4
+	MOVO       X2, X5
+	PUNPCKLLQ  X3, X5
+	PUNPCKHLQ  X1, X0
+	PUNPCKHLQ  X3, X2
+	MOVO       X4, X1
+	PUNPCKLQDQ X5, X1
+	PUNPCKHQDQ X5, X4
+	MOVO       X0, X3
+	PUNPCKLQDQ X2, X3
+	PUNPCKHQDQ X2, X0
+	MOVOU 32(SI), X8
+	PXOR  X8, X1
+	MOVOU X1, 32(DI)
+	MOVOU 96(SI), X8
+	PXOR  X8, X4
+	MOVOU X4, 96(DI)
+	MOVOU 160(SI), X8
+	PXOR  X8, X3
+	MOVOU X3, 160(DI)
+	MOVOU 224(SI), X8
+	PXOR  X8, X0
+	MOVOU X0, 224(DI)
+
+	// words 12 to 15 of every block
+	MOVOU 448(SP), X0
+	MOVOU 192(SP), X4
+	PADDL X4, X0
+	MOVOU 464(SP), X1
+	MOVOU 208(SP), X5
+	PADDL X5, X1
+	MOVOU 480(SP), X2
+	MOVOU 224(SP), X6
+	PADDL X6, X2
+	MOVOU 496(SP), X3
+	MOVOU 240(SP), X7
+	PADDL X7, X3
+	MOVO       X0, X4
+	PUNPCKLLQ  X1, X4
+	MOVO       X2, X5
+	PUNPCKLLQ  X3, X5
+	PUNPCKHLQ  X1, X0
+	PUNPCKHLQ  X3, X2
+	MOVO       X4, X1
+	PUNPCKLQDQ X5, X1
+	PUNPCKHQDQ X5, X4
+	MOVO       X0, X3
+	PUNPCKLQDQ X2, X3
+	PUNPCKHQDQ X2, X0
+	MOVOU 48(SI), X8
+	PXOR  X8, X1
+	MOVOU X1, 48(DI)
+	MOVOU 112(SI), X8
+	PXOR  X8, X4
+	MOVOU X4, 112(DI)
+	MOVOU 176(SI), X8
+	PXOR  X8, X3
+	MOVOU X3, 176(DI)
+	MOVOU 240(SI), X8
+	PXOR  X8, X0
+	MOVOU X0, 240(DI)
+
+	MOVOU 192(SP), X0
+	MOVOU ·chacha20Four<>(SB), X1
+	PADDL X1, X0
+	MOVOU X0, 192(SP)
+
+	ADDQ $256, SI
+	ADDQ $256, DI
+	SUBQ $256, CX
+	JNZ  ssse3_loop
+
+	// erase the keystream and the state from the stack.
+	PXOR X0, X0
+	MOVOU X0, 0(SP)
+	MOVOU X0, 16(SP)
+	MOVOU X0, 32(SP)
+	MOVOU X0, 48(SP)
+	MOVOU X0, 64(SP)
+	MOVOU X0, 80(SP)
+	MOVOU X0, 96(SP)
+	MOVOU X0, 112(SP)
+	MOVOU X0, 128(SP)
+	MOVOU X0, 144(SP)
+	MOVOU X0, 160(SP)
+	MOVOU X0, 176(SP)
+	MOVOU X0, 192(SP)
+	MOVOU X0, 208(SP)
+	MOVOU X0, 224(SP)
+	MOVOU X0, 240(SP)
+	MOVOU X0, 256(SP)
+	MOVOU X0, 272(SP)
+	MOVOU X0, 288(SP)
+	MOVOU X0, 304(SP)
+	MOVOU X0, 320(SP)
+	MOVOU X0, 336(SP)
+	MOVOU X0, 352(SP)
+	MOVOU X0, 368(SP)
+	MOVOU X0, 384(SP)
+	MOVOU X0, 400(SP)
+	MOVOU X0, 416(SP)
+	MOVOU X0, 432(SP)
+	MOVOU X0, 448(SP)
+	MOVOU X0, 464(SP)
+	MOVOU X0, 480(SP)
+	MOVOU X0, 496(SP)
+	MOVOU X0, 512(SP)
+
+ssse3_done:
+	RET
+
+// func chacha20BlocksAVX2(s *[16]uint32, dst, src []byte)
+// Requires: AVX, AVX2
+//
+// The frame holds the input words at 0(SP), the output of the rounds at
+// 512(SP) and the spill slot at 1024(SP).
+TEXT ·chacha20BlocksAVX2(SB), 0, $1056-56
+	MOVQ s+0(FP), AX
+	MOVQ dst_base+8(FP), DI
+	MOVQ src_base+32(FP), SI
+	MOVQ src_len+40(FP), CX
+	TESTQ CX, CX
+	JZ    avx2_done
+
+	// broadcast every word of the state, lane i uses counter + i.
+	VPBROADCASTD 0(AX), Y0
+	VMOVDQU      Y0, 0(SP)
+	VPBROADCASTD 4(AX), Y0
+	VMOVDQU      Y0, 32(SP)
+	VPBROADCASTD 8(AX), Y0
+	VMOVDQU      Y0, 64(SP)
+	VPBROADCASTD 12(AX), Y0
+	VMOVDQU      Y0, 96(SP)
+	VPBROADCASTD 16(AX), Y0
+	VMOVDQU      Y0, 128(SP)
+	VPBROADCASTD 20(AX), Y0
+	VMOVDQU      Y0, 160(SP)
+	VPBROADCASTD 24(AX), Y0
+	VMOVDQU      Y0, 192(SP)
+	VPBROADCASTD 28(AX), Y0
+	VMOVDQU      Y0, 224(SP)
+	VPBROADCASTD 32(AX), Y0
+	VMOVDQU      Y0, 256(SP)
+	VPBROADCASTD 36(AX), Y0
+	VMOVDQU      Y0, 288(SP)
+	VPBROADCASTD 40(AX), Y0
+	VMOVDQU      Y0, 320(SP)
+	VPBROADCASTD 44(AX), Y0
+	VMOVDQU      Y0, 352(SP)
+	VPBROADCASTD 48(AX), Y0
+	VPADDD       ·chacha20Lanes<>(SB), Y0, Y0
+	VMOVDQU      Y0, 384(SP)
+	VPBROADCASTD 52(AX), Y0
+	VMOVDQU      Y0, 416(SP)
+	VPBROADCASTD 56(AX), Y0
+	VMOVDQU      Y0, 448(SP)
+	VPBROADCASTD 60(AX), Y0
+	VMOVDQU      Y0, 480(SP)
+
+avx2_loop:
+	VMOVDQU 0(SP), Y0
+	VMOVDQU 32(SP), Y1
+	VMOVDQU 64(SP), Y2
+	VMOVDQU 96(SP), Y3
+	VMOVDQU 128(SP), Y4
+	VMOVDQU 160(SP), Y5
+	VMOVDQU 192(SP), Y6
+	VMOVDQU 224(SP), Y7
+	VMOVDQU 256(SP), Y8
+	VMOVDQU 288(SP), Y9
+	VMOVDQU 320(SP), Y10
+	VMOVDQU 352(SP), Y11
+	VMOVDQU 384(SP), Y12
+	VMOVDQU 416(SP), Y13
+	VMOVDQU 448(SP), Y14
+	VMOVDQU 480(SP), Y15
+	MOVQ    $10, DX
+
+avx2_rounds:
+	ROUND_AVX2(Y0, Y4, Y8, Y12, Y1, Y5, Y9, Y13, Y2, Y6, Y10, Y14, Y3, Y7, Y11, Y15)
+	ROUND_AVX2(Y0, Y5, Y10, Y15, Y1, Y6, Y11, Y12, Y2, Y7, Y8, Y13, Y3, Y4, Y9, Y14)
+	DECQ DX
+	JNZ  avx2_rounds
+
+	VMOVDQU Y0, 512(SP)
+	VMOVDQU Y1, 544(SP)
+	VMOVDQU Y2, 576(SP)
+	VMOVDQU Y3, 608(SP)
+	VMOVDQU Y4, 640(SP)
+	VMOVDQU Y5, 672(SP)
+	VMOVDQU Y6, 704(SP)
+	VMOVDQU Y7, 736(SP)
+	VMOVDQU Y8, 768(SP)
+	VMOVDQU Y9, 800(SP)
+	VMOVDQU Y10, 832(SP)
+	VMOVDQU Y11, 864(SP)
+	VMOVDQU Y12, 896(SP)
+	VMOVDQU Y13, 928(SP)
+	VMOVDQU Y14, 960(SP)
+	VMOVDQU Y15, 992(SP)
+
+	// words 0 to 7 of every block
+	VMOVDQU 512(SP), Y0
+	VPADDD  0(SP), Y0, Y0
+	VMOVDQU 544(SP), Y1
+	VPADDD  32(SP), Y1, Y1
+	VMOVDQU 576(SP), Y2
+	VPADDD  64(SP), Y2, Y2
+	VMOVDQU 608(SP), Y3
+	VPADDD  96(SP), Y3, Y3
+	VMOVDQU 640(SP), Y4
+	VPADDD  128(SP), Y4, Y4
+	VMOVDQU 672(SP), Y5
+	VPADDD  160(SP), Y5, Y5
+	VMOVDQU 704(SP), Y6
+	VPADDD  192(SP), Y6, Y6
+	VMOVDQU 736(SP), Y7
+	VPADDD  224(SP), Y7, Y7
+	VPUNPCKLDQ  Y1, Y0, Y8
+	VPUNPCKHDQ  Y1, Y0, Y9
+	VPUNPCKLDQ  Y3, Y2, Y10
+	VPUNPCKHDQ  Y3, Y2, Y11
+	VPUNPCKLQDQ Y10, Y8, Y0
+	VPUNPCKHQDQ Y10, Y8, Y1
+	VPUNPCKLQDQ Y11, Y9, Y2
+	VPUNPCKHQDQ Y11, Y9, Y3
+	VPUNPCKLDQ  Y5, Y4, Y8
+	VPUNPCKHDQ  Y5, Y4, Y9
+	VPUNPCKLDQ  Y7, Y6, Y10
+	VPUNPCKHDQ  Y7, Y6, Y11
+	VPUNPCKLQDQ Y10, Y8, Y4
+	VPUNPCKHQDQ Y10, Y8, Y5
+	VPUNPCKLQDQ Y11, Y9, Y6
+	VPUNPCKHQDQ Y11, Y9, Y7
+	VPERM2I128  $0x20, Y4, Y0, Y8
+	VPERM2I128  $0x31, Y4, Y0, Y9
+	VPXOR       0(SI), Y8, Y8
+	VMOVDQU     Y8, 0(DI)
+	VPXOR       256(SI), Y9, Y9
+	VMOVDQU     Y9, 256(DI)
+	VPERM2I128  $0x20, Y5, Y1, Y8
+	VPERM2I128  $0x31, Y5, Y1, Y9
+	VPXOR       64(SI), Y8, Y8
+	VMOVDQU     Y8, 64(DI)
+	VPXOR       320(SI), Y9, Y9
+	VMOVDQU     Y9, 320(DI)
+	VPERM2I128  $0x20, Y6, Y2, Y8
+	VPERM2I128  $0x31, Y6, Y2, Y9
+	VPXOR       128(SI), Y8, Y8
+	VMOVDQU     Y8, 128(DI)
+	VPXOR       384(SI), Y9, Y9
+	VMOVDQU     Y9, 384(DI)
+	VPERM2I128  $0x20, Y7, Y3, Y8
+	VPERM2I128  $0x31, Y7, Y3, Y9
+	VPXOR       192(SI), Y8, Y8
+	VMOVDQU     Y8, 192(DI)
+	VPXOR       448(SI), Y9, Y9
+	VMOVDQU     Y9, 448(DI)
+
+	// words 8 to 15 of every block
+	VMOVDQU 768(SP), Y0
+	VPADDD  256(SP), Y0, Y0
+	VMOVDQU 800(SP), Y1
+	VPADDD  288(SP), Y1, Y1
+	VMOVDQU 832(SP), Y2
+	VPADDD  320(SP), Y2, Y2
+	VMOVDQU 864(SP), Y3
+	VPADDD  352(SP), Y3, Y3
+	VMOVDQU 896(SP), Y4
+	VPADDD  384(SP), Y4, Y4
+	VMOVDQU 928(SP), Y5
+	VPADDD  416(SP), Y5, Y5
+	VMOVDQU 960(SP), Y6
+	VPADDD  448(SP), Y6, Y6
+	VMOVDQU 992(SP), Y7
+	VPADDD  480(SP), Y7, Y7
+	VPUNPCKLDQ  Y1, Y0, Y8
+	VPUNPCKHDQ  Y1, Y0, Y9
+	VPUNPCKLDQ  Y3, Y2, Y10
+	VPUNPCKHDQ  Y3, Y2, Y11
+	VPUNPCKLQDQ Y10, Y8, Y0
+	VPUNPCKHQDQ Y10, Y8, Y1
+	VPUNPCKLQDQ Y11, Y9, Y2
+	VPUNPCKHQDQ Y11, Y9, Y3
+	VPUNPCKLDQ  Y5, Y4, Y8
+	VPUNPCKHDQ  Y5, Y4, Y9
+	VPUNPCKLDQ  Y7, Y6, Y10
+	VPUNPCKHDQ  Y7, Y6, Y11
+	VPUNPCKLQDQ Y10, Y8, Y4
+	VPUNPCKHQDQ Y10, Y8, Y5
+	VPUNPCKLQDQ Y11, Y9, Y6
+	VPUNPCKHQDQ Y11, Y9, Y7
+	VPERM2I128  $0x20, Y4, Y0, Y8
+	VPERM2I128  $0x31, Y4, Y0, Y9
+	VPXOR       32(SI), Y8, Y8
+	VMOVDQU     Y8, 32(DI)
+	VPXOR       288(SI), Y9, Y9
+	VMOVDQU     Y9, 288(DI)
+	VPERM2I128  $0x20, Y5, Y1, Y8
+	VPERM2I128  $0x31, Y5, Y1, Y9
+	VPXOR       96(SI), Y8, Y8
+	VMOVDQU     Y8, 96(DI)
+	VPXOR       352(SI), Y9, Y9
+	VMOVDQU     Y9, 352(DI)
+	VPERM2I128  $0x20, Y6, Y2, Y8
+	VPERM2I128  $0x31, Y6, Y2, Y9
+	VPXOR       160(SI), Y8, Y8
+	VMOVDQU     Y8, 160(DI)
+	VPXOR       416(SI), Y9, Y9
+	VMOVDQU     Y9, 416(DI)
+	VPERM2I128  $0x20, Y7, Y3, Y8
+	VPERM2I128  $0x31, Y7, Y3, Y9
+	VPXOR       224(SI), Y8, Y8
+	VMOVDQU     Y8, 224(DI)
+	VPXOR       480(SI), Y9, Y9
+	VMOVDQU     Y9, 480(DI)
+
+	VMOVDQU 384(SP), Y0
+	VPADDD  ·chacha20Eight<>(SB), Y0, Y0
+	VMOVDQU Y0, 384(SP)
+
+	ADDQ $512, SI
+	ADDQ $512, DI
+	SUBQ $512, CX
+	JNZ  avx2_loop
+
+	// erase the keystream and the state from the stack.
+	VPXOR Y0, Y0, Y0
+	VMOVDQU Y0, 0(SP)
+	VMOVDQU Y0, 32(SP)
+	VMOVDQU Y0, 64(SP)
+	VMOVDQU Y0, 96(SP)
+	VMOVDQU Y0, 128(SP)
+	VMOVDQU Y0, 160(SP)
+	VMOVDQU Y0, 192(SP)
+	VMOVDQU Y0, 224(SP)
+	VMOVDQU Y0, 256(SP)
+	VMOVDQU Y0, 288(SP)
+	VMOVDQU Y0, 320(SP)
+	VMOVDQU Y0, 352(SP)
+	VMOVDQU Y0, 384(SP)
+	VMOVDQU Y0, 416(SP)
+	VMOVDQU Y0, 448(SP)
+	VMOVDQU Y0, 480(SP)
+	VMOVDQU Y0, 512(SP)
+	VMOVDQU Y0, 544(SP)
+	VMOVDQU Y0, 576(SP)
+	VMOVDQU Y0, 608(SP)
+	VMOVDQU Y0, 640(SP)
+	VMOVDQU Y0, 672(SP)
+	VMOVDQU Y0, 704(SP)
+	VMOVDQU Y0, 736(SP)
+	VMOVDQU Y0, 768(SP)
+	VMOVDQU Y0, 800(SP)
+	VMOVDQU Y0, 832(SP)
+	VMOVDQU Y0, 864(SP)
+	VMOVDQU Y0, 896(SP)
+	VMOVDQU Y0, 928(SP)
+	VMOVDQU Y0, 960(SP)
+	VMOVDQU Y0, 992(SP)
+	VMOVDQU Y0, 1024(SP)
+	VZEROUPPER
+
+avx2_done:
+	RET
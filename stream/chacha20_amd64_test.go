@@ -0,0 +1,82 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build amd64,gc,!purego
+
+package stream
+
+import (
+	"bytes"
+	"testing"
+)
+
+// chacha20Kernels lists the assembly implementations that are supported by
+// the processor, with a function that enables only that implementation.
+func chacha20Kernels() (names []string, enable []func()) {
+	avx2, ssse3 := useAVX2, useSSSE3
+
+	set := func(a, b bool) func() {
+		return func() { useAVX2, useSSSE3 = a, b }
+	}
+
+	names = append(names, "generic")
+	enable = append(enable, set(false, false))
+	if ssse3 {
+		names = append(names, "SSSE3")
+		enable = append(enable, set(false, true))
+	}
+	if avx2 {
+		names = append(names, "AVX2")
+		enable = append(enable, set(true, false))
+	}
+	names = append(names, "default")
+	enable = append(enable, set(avx2, ssse3))
+	return
+}
+
+func TestChacha20Kernels(t *testing.T) {
+	skipNonceReuse(t)
+
+	names, enable := chacha20Kernels()
+	defer enable[len(enable)-1]()
+
+	src := make([]byte, 16*Chacha20_BlockBytes+17)
+	for i := range src {
+		src[i] = byte(i % 251)
+	}
+
+	var expect [][]byte
+	enable[0]()
+	for n := 0; n <= len(src); n += 61 {
+		dst := make([]byte, n)
+		NewChacha20(testKey(), testNonce(Chacha20_NonceBytes)).Seek(7).XORKeyStream(dst, src[:n])
+		expect = append(expect, dst)
+	}
+
+	for i, name := range names {
+		enable[i]()
+		t.Run(name, TestChacha20)
+		t.Run(name, TestChacha20CounterWrap)
+
+		for j, n := 0, 0; n <= len(src); j, n = j+1, n+61 {
+			dst := make([]byte, n)
+			NewChacha20(testKey(), testNonce(Chacha20_NonceBytes)).Seek(7).XORKeyStream(dst, src[:n])
+			if !bytes.Equal(expect[j], dst) {
+				t.Error("expected result did not match computed", name, n)
+			}
+		}
+	}
+}
+
+func BenchmarkChacha20Kernels(b *testing.B) {
+	names, enable := chacha20Kernels()
+	defer enable[len(enable)-1]()
+
+	for i, name := range names {
+		enable[i]()
+		b.Run(name, BenchmarkChacha20_8K)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package stream
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// chacha20Sigma holds the constant words "expand 32-byte k".
+var chacha20Sigma = [4]uint32{0x61707865, 0x3320646e, 0x79622d32, 0x6b206574}
+
+// chacha20QuarterRound mixes the four words a, b, c and d.
+func chacha20QuarterRound(a, b, c, d uint32) (uint32, uint32, uint32, uint32) {
+	a += b
+	d = bits.RotateLeft32(d^a, 16)
+	c += d
+	b = bits.RotateLeft32(b^c, 12)
+	a += b
+	d = bits.RotateLeft32(d^a, 8)
+	c += d
+	b = bits.RotateLeft32(b^c, 7)
+	return a, b, c, d
+}
+
+// chacha20BlocksGeneric xors src with the keystream of len(src) / 64 blocks
+// into dst, starting at the block counter in word 12 of the state. The length
+// of src must be a multiple of the block size, and the counter must not wrap
+// around. The state is not changed, the caller advances the counter.
+func chacha20BlocksGeneric(s *[16]uint32, dst, src []byte) {
+	counter := s[12]
+
+	for off := 0; off+Chacha20_BlockBytes <= len(src); off += Chacha20_BlockBytes {
+		x0, x1, x2, x3 := s[0], s[1], s[2], s[3]
+		x4, x5, x6, x7 := s[4], s[5], s[6], s[7]
+		x8, x9, x10, x11 := s[8], s[9], s[10], s[11]
+		x12, x13, x14, x15 := counter, s[13], s[14], s[15]
+
+		for i := 0; i < 10; i++ {
+			// column round
+			x0, x4, x8, x12 = chacha20QuarterRound(x0, x4, x8, x12)
+			x1, x5, x9, x13 = chacha20QuarterRound(x1, x5, x9, x13)
+			x2, x6, x10, x14 = chacha20QuarterRound(x2, x6, x10, x14)
+			x3, x7, x11, x15 = chacha20QuarterRound(x3, x7, x11, x15)
+
+			// diagonal round
+			x0, x5, x10, x15 = chacha20QuarterRound(x0, x5, x10, x15)
+			x1, x6, x11, x12 = chacha20QuarterRound(x1, x6, x11, x12)
+			x2, x7, x8, x13 = chacha20QuarterRound(x2, x7, x8, x13)
+			x3, x4, x9, x14 = chacha20QuarterRound(x3, x4, x9, x14)
+		}
+
+		in, out := src[off:off+Chacha20_BlockBytes], dst[off:off+Chacha20_BlockBytes]
+		chacha20XORWord(out[0:], in[0:], x0+s[0])
+		chacha20XORWord(out[4:], in[4:], x1+s[1])
+		chacha20XORWord(out[8:], in[8:], x2+s[2])
+		chacha20XORWord(out[12:], in[12:], x3+s[3])
+		chacha20XORWord(out[16:], in[16:], x4+s[4])
+		chacha20XORWord(out[20:], in[20:], x5+s[5])
+		chacha20XORWord(out[24:], in[24:], x6+s[6])
+		chacha20XORWord(out[28:], in[28:], x7+s[7])
+		chacha20XORWord(out[32:], in[32:], x8+s[8])
+		chacha20XORWord(out[36:], in[36:], x9+s[9])
+		chacha20XORWord(out[40:], in[40:], x10+s[10])
+		chacha20XORWord(out[44:], in[44:], x11+s[11])
+		chacha20XORWord(out[48:], in[48:], x12+counter)
+		chacha20XORWord(out[52:], in[52:], x13+s[13])
+		chacha20XORWord(out[56:], in[56:], x14+s[14])
+		chacha20XORWord(out[60:], in[60:], x15+s[15])
+
+		counter++
+	}
+}
+
+// chacha20XORWord xors the first 4 bytes of src with the little endian word v
+// into dst.
+func chacha20XORWord(dst, src []byte, v uint32) {
+	binary.LittleEndian.PutUint32(dst, binary.LittleEndian.Uint32(src)^v)
+}
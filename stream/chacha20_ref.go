@@ -0,0 +1,15 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// +build !amd64 !gc purego
+
+package stream
+
+// chacha20Blocks xors src with the keystream into dst, see
+// chacha20BlocksGeneric.
+func chacha20Blocks(s *[16]uint32, dst, src []byte) {
+	chacha20BlocksGeneric(s, dst, src)
+}
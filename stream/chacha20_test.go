@@ -0,0 +1,211 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package stream
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"go.artemisc.eu/godium"
+	"go.artemisc.eu/godium/noncecheck"
+)
+
+func testKey() (key []byte) {
+	key = make([]byte, Chacha20_KeyBytes)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return
+}
+
+// testNonce returns a nonce of size bytes starting at 0x40.
+func testNonce(size int) (nonce []byte) {
+	nonce = make([]byte, size)
+	for i := range nonce {
+		nonce[i] = byte(0x40 + i)
+	}
+	return
+}
+
+// skipNonceReuse skips a test that seals under fixed keys and nonces, which
+// the godium_noncecheck build tag reports as nonce reuse. Tests that seal a
+// fixed key and nonce only once call noncecheck.Reset instead.
+func skipNonceReuse(t *testing.T) {
+	if noncecheck.Enabled {
+		t.Skip("seals under fixed keys and nonces")
+	}
+}
+
+func TestChacha20IetfRFC8439(t *testing.T) {
+	// RFC 8439, section 2.4.2
+	nonce, _ := hex.DecodeString("000000000000004a00000000")
+	plain := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it.")
+	expect, _ := hex.DecodeString("6e2e359a2568f98041ba0728dd0d6981e97e7aec1d4360c20a27afccfd9fae0b" +
+		"f91b65c5524733ab8f593dabcd62b3571639d624e65152ab8f530c359f0861d8" +
+		"07ca0dbf500d6a6156a38e088a22b65e52bc514d16ccf806818ce91ab7793736" +
+		"5af90bbf74a35be6b40b8eedf2785e42874d")
+
+	s := NewChacha20Ietf(testKey(), nonce)
+	got := make([]byte, len(plain))
+	s.Seek(1).XORKeyStream(got, plain)
+	if !bytes.Equal(expect, got) {
+		t.Error("expected result did not match computed", expect, got)
+	}
+}
+
+// chacha20Vectors hold the first block of keystream and the SHA-256 hash of
+// 100000 bytes of keystream under testKey, computed with libsodium's
+// crypto_stream_chacha20, crypto_stream_chacha20_ietf and
+// crypto_stream_xchacha20.
+var chacha20Vectors = []struct {
+	name  string
+	new   func(key, nonce []byte) godium.Stream
+	nonce int
+	block string
+	hash  string
+}{
+	{"chacha20", NewChacha20, Chacha20_NonceBytes,
+		"db6446e45a5708683c5eaa79221070e72158caa830dbd72aa22aa694cebff358" +
+			"ffceabfdc7f160c44d3ef5bce06d59f4623d3b577a1f5cdff50c6d8dc913fd76",
+		"192334f4ab87a16c828a1dc97404a39ee20c8cd9e646a14ab3ad2f30de939be2"},
+	{"chacha20ietf", NewChacha20Ietf, Chacha20Ietf_NonceBytes,
+		"19509e57d0203bafacfd26f596b7a404110a2f4de89800cd52c89b45c7dbc7c3" +
+			"78a24c93cae84cdfb3ed04263bc2e8065bdbef947813f59c778e3657e8f4e1e7",
+		"08b9da77bcecef7f74c3d5c28ceb660296a21b875fd255248ec3cc9ebadc2778"},
+	{"xchacha20", NewXChacha20, XChacha20_NonceBytes,
+		"85ee3116337d23c62215345c52264d7f3c6e8a9359304fdc8453180483ac1666" +
+			"3fb7048e486198e54eb811953bf0dc76a767a9d29134dae8ad692519afd7b6d8",
+		"0b8bc60325ee2702ff8e2a81150e188aa7cf67a12d488a4fe4e6211ab71d4868"},
+}
+
+func TestChacha20(t *testing.T) {
+	skipNonceReuse(t)
+
+	for _, v := range chacha20Vectors {
+		expect, _ := hex.DecodeString(v.block)
+		s := v.new(testKey(), testNonce(v.nonce))
+
+		got := make([]byte, Chacha20_BlockBytes)
+		s.KeyStream(got)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.name, expect, got)
+		}
+
+		expect, _ = hex.DecodeString(v.hash)
+		stream := make([]byte, 100000)
+		s.Seek(0).KeyStream(stream)
+		if sum := sha256.Sum256(stream); !bytes.Equal(expect, sum[:]) {
+			t.Error("expected result did not match computed", v.name, expect, sum[:])
+		}
+
+		// the same keystream, produced in pieces of varying length.
+		s.ReKey(testKey(), testNonce(v.nonce))
+		got = make([]byte, len(stream))
+		for off, n := 0, 1; off < len(got); off, n = off+n, n*7%1031 {
+			end := off + n
+			if end > len(got) {
+				end = len(got)
+			}
+			s.XORKeyStream(got[off:end], make([]byte, end-off))
+		}
+		if !bytes.Equal(stream, got) {
+			t.Error("expected result did not match computed", v.name)
+		}
+	}
+}
+
+func TestChacha20CounterWrap(t *testing.T) {
+	// the lower word of the 64 bit counter wraps around in the middle of the
+	// keystream, computed with libsodium's crypto_stream_chacha20_xor_ic.
+	expect, _ := hex.DecodeString("ca37e7058645ce2cbec7e8612b333cc75b26c1249dcd9482ad25efac8edd4930")
+
+	s := NewChacha20(testKey(), testNonce(Chacha20_NonceBytes))
+	got := make([]byte, 11*Chacha20_BlockBytes+5)
+	s.Seek(1<<32 - 3).KeyStream(got)
+	if sum := sha256.Sum256(got); !bytes.Equal(expect, sum[:]) {
+		t.Error("expected result did not match computed", expect, sum[:])
+	}
+}
+
+func TestChacha20XORIc(t *testing.T) {
+	skipNonceReuse(t)
+
+	key := testKey()
+	src := make([]byte, 1000)
+	for i := range src {
+		src[i] = byte(i)
+	}
+
+	oneShots := []func(dst, src, nonce []byte, ic uint64, key []byte){
+		Chacha20XORIc,
+		func(dst, src, nonce []byte, ic uint64, key []byte) {
+			Chacha20IetfXORIc(dst, src, nonce, uint32(ic), key)
+		},
+		XChacha20XORIc,
+	}
+
+	for i, v := range chacha20Vectors {
+		nonce := testNonce(v.nonce)
+		expect := make([]byte, len(src))
+		v.new(key, nonce).Seek(3).XORKeyStream(expect, src)
+
+		got := make([]byte, len(src))
+		oneShots[i](got, src, nonce, 3, key)
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", v.name)
+		}
+
+		allocs := testing.AllocsPerRun(10, func() {
+			oneShots[i](got, src, nonce, 3, key)
+		})
+		if allocs != 0 {
+			t.Error("one-shot allocated", v.name, allocs)
+		}
+	}
+}
+
+// expectPanic fails the test if f does not panic.
+func expectPanic(t *testing.T, name string, f func()) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic", name)
+		}
+	}()
+	f()
+}
+
+func TestChacha20IetfCounterOverflow(t *testing.T) {
+	s := NewChacha20Ietf(testKey(), testNonce(Chacha20Ietf_NonceBytes))
+	buf := make([]byte, 2*Chacha20_BlockBytes)
+
+	s.Seek(1<<32 - 2).KeyStream(buf)
+	expectPanic(t, "exhausted counter", func() { s.KeyStream(buf[:1]) })
+
+	s.Seek(1<<32 - 1)
+	expectPanic(t, "too long", func() { s.KeyStream(buf[:Chacha20_BlockBytes+1]) })
+
+	expectPanic(t, "Seek", func() { s.Seek(1 << 32) })
+	expectPanic(t, "short key", func() { s.ReKey(buf[:16], buf) })
+}
+
+func benchmarkChacha20(b *testing.B, size int) {
+	s := NewChacha20Ietf(testKey(), testNonce(Chacha20Ietf_NonceBytes))
+	buf := make([]byte, size)
+
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Seek(0).XORKeyStream(buf, buf)
+	}
+}
+
+func BenchmarkChacha20_64(b *testing.B)  { benchmarkChacha20(b, 64) }
+func BenchmarkChacha20_256(b *testing.B) { benchmarkChacha20(b, 256) }
+func BenchmarkChacha20_1K(b *testing.B)  { benchmarkChacha20(b, 1024) }
+func BenchmarkChacha20_8K(b *testing.B)  { benchmarkChacha20(b, 8192) }
@@ -11,6 +11,7 @@ import (
 
 	"go.artemisc.eu/godium"
 	"go.artemisc.eu/godium/core"
+	"go.artemisc.eu/godium/noncecheck"
 )
 
 const (
@@ -45,6 +46,23 @@ func NewXSalsa20(key, nonce []byte) (s godium.Stream) {
 	return
 }
 
+// XSalsa20XORIc xors src with the keystream into dst, starting at block
+// counter ic, like crypto_stream_xsalsa20_xor_ic. It does not allocate.
+func XSalsa20XORIc(dst, src, nonce []byte, ic uint64, key []byte) {
+	var subKey [core.HSalsa20_OutputBytes]byte
+	var s salsa20Impl
+
+	nonce = nonce[:XSalsa20_NonceBytes]
+	core.HSalsa20(subKey[:0], nonce, key, nil)
+
+	s.ReKey(subKey[:], nonce[core.HSalsa20_InputBytes:])
+	s.Seek(ic)
+	s.XORKeyStream(dst, src)
+
+	s.Wipe()
+	godium.Wipe(subKey[:])
+}
+
 // incrCounter
 func (s *salsa20Impl) incrCounter() {
 	u := uint32(1)
@@ -72,6 +90,7 @@ func (s *salsa20Impl) Wipe() {
 // ReKey
 func (s *salsa20Impl) ReKey(key, nonce []byte) {
 	s.isXSalsa = len(nonce) >= XSalsa20_NonceBytes
+	noncecheck.RecordStream("stream salsa20", key, nonce[:s.NonceBytes()])
 
 	if s.isXSalsa {
 		key = core.HSalsa20(nil, nonce, key, nil)
@@ -83,35 +102,13 @@ func (s *salsa20Impl) ReKey(key, nonce []byte) {
 	for i := 8; i < 16; i++ {
 		s.counter[i] = 0
 	}
+	s.blockOffset = 0
 }
 
 // KeyStream
 func (s *salsa20Impl) KeyStream(dst []byte) {
-	// first block
-	if s.blockOffset > 0 {
-		n := copy(dst, s.block[s.blockOffset:])
-		dst = dst[n:]
-
-		s.blockOffset += n
-	}
-
-	if s.blockOffset == Salsa20_BlockBytes {
-		s.incrCounter()
-		s.blockOffset = 0
-	}
-
-	// rest of the blocks
-	for len(dst) > 0 {
-		s.nextState()
-		n := copy(dst, s.block[:])
-
-		if n < Salsa20_BlockBytes {
-			s.blockOffset = n
-			return
-		}
-
-		dst = dst[Salsa20_BlockBytes:]
-	}
+	godium.Wipe(dst)
+	s.XORKeyStream(dst, dst)
 }
 
 // XORKeyStream
@@ -141,6 +138,7 @@ func (s *salsa20Impl) XORKeyStream(dst, src []byte) {
 
 		dst = dst[rem:]
 		src = src[rem:]
+		s.blockOffset = 0
 	}
 
 	// full blocks
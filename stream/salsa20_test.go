@@ -0,0 +1,52 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package stream
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestXSalsa20Chunks(t *testing.T) {
+	skipNonceReuse(t)
+
+	key, nonce := testKey(), testNonce(XSalsa20_NonceBytes)
+	src := make([]byte, 1000)
+	for i := range src {
+		src[i] = byte(i % 251)
+	}
+
+	expect := make([]byte, len(src))
+	XSalsa20XORIc(expect, src, nonce, 0, key)
+
+	for _, chunk := range []int{1, 7, 32, 63, 64, 65, 100} {
+		s := NewXSalsa20(key, nonce)
+		got := make([]byte, len(src))
+		for i := 0; i < len(src); i += chunk {
+			end := i + chunk
+			if end > len(src) {
+				end = len(src)
+			}
+			s.XORKeyStream(got[i:end], src[i:end])
+		}
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", chunk)
+		}
+
+		// a keystream prefix followed by XORKeyStream, like a secretbox.
+		s = NewXSalsa20(key, nonce)
+		ks := make([]byte, chunk)
+		s.KeyStream(ks)
+		s.XORKeyStream(got[chunk:], src[chunk:])
+		for i, v := range ks {
+			got[i] = src[i] ^ v
+		}
+		if !bytes.Equal(expect, got) {
+			t.Error("expected result did not match computed", chunk)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+/*
+Package utils provides the constant-time helpers from libsodium's utils.h, for
+working with secrets, nonces and counters without leaking their values through
+timing.
+
+Numbers are represented as byte slices in little endian order, like the nonces
+and counters used by the box, secretbox and aead packages. Functions that take
+two numbers panic if their lengths differ, as libsodium requires both to have
+the same size.
+*/
+package utils // import "go.artemisc.eu/godium/utils"
@@ -0,0 +1,173 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package utils
+
+import (
+	"math/bits"
+
+	"go.artemisc.eu/godium"
+)
+
+// padMarker is the first byte of the ISO/IEC 7816-4 padding, all other bytes
+// of the padding are 0.
+const padMarker = 0x80
+
+// Pad appends ISO/IEC 7816-4 padding to buf, so its length becomes a multiple
+// of blockSize, like sodium_pad. At least one byte of padding is added, so a
+// buffer that already is a multiple of blockSize grows by a full block. Pad
+// panics if blockSize is not positive.
+func Pad(buf []byte, blockSize int) (padded []byte) {
+	if blockSize <= 0 {
+		panic("utils: block size must be positive")
+	}
+
+	padded = padTo(buf, len(buf)+blockSize-len(buf)%blockSize)
+	return
+}
+
+// Unpad removes the ISO/IEC 7816-4 padding added by Pad, like sodium_unpad.
+// The last blockSize bytes of padded are always inspected, so the time taken
+// does not depend on the length of the padding. The returned slice shares its
+// underlying array with padded.
+func Unpad(padded []byte, blockSize int) (buf []byte, err error) {
+	var acc, valid, padLen uint
+
+	if blockSize <= 0 || len(padded) < blockSize {
+		err = godium.ErrInvalidPadding
+		return
+	}
+
+	tail := len(padded) - 1
+	for i := uint(0); i < uint(blockSize); i++ {
+		c := uint(padded[tail-int(i)])
+		barrier := (((acc - 1) & (padLen - 1) & ((c ^ padMarker) - 1)) >> 8) & 1
+		acc |= c
+		padLen |= i & -barrier
+		valid |= barrier
+	}
+
+	if valid != 1 {
+		err = godium.ErrInvalidPadding
+		return
+	}
+
+	buf = padded[:tail-int(padLen)]
+	return
+}
+
+// padTo appends the marker to buf, followed by zeros up to size bytes.
+func padTo(buf []byte, size int) (padded []byte) {
+	n := len(buf)
+
+	if cap(buf) >= size {
+		padded = buf[:size]
+	} else {
+		padded = make([]byte, size)
+		copy(padded, buf)
+	}
+
+	padded[n] = padMarker
+	godium.Wipe(padded[n+1:])
+	return
+}
+
+// blockPadding implements godium.Padding for sodium_pad.
+type blockPadding int
+
+// NewBlockPadding creates a godium.Padding that pads messages to a multiple of
+// blockSize, using Pad and Unpad. It panics if blockSize is not positive.
+func NewBlockPadding(blockSize int) (p godium.Padding) {
+	if blockSize <= 0 {
+		panic("utils: block size must be positive")
+	}
+
+	p = blockPadding(blockSize)
+	return
+}
+
+func (p blockPadding) Pad(buf []byte) (padded []byte) {
+	padded = Pad(buf, int(p))
+	return
+}
+
+func (p blockPadding) Unpad(padded []byte) (buf []byte, err error) {
+	buf, err = Unpad(padded, int(p))
+	return
+}
+
+func (p blockPadding) PaddedBytes(n int) (c int) {
+	c = n + int(p) - n%int(p)
+	return
+}
+
+// bucketPadding implements godium.Padding for schemes that only allow a fixed
+// set of padded lengths. The ISO/IEC 7816-4 marker is used to find the end of
+// the message, so any padded length leaves room for at least one byte.
+type bucketPadding struct {
+	bucket func(n int) int
+}
+
+// NewPadmePadding creates a godium.Padding that pads messages to the lengths
+// allowed by PADMÉ, as described in "Reducing Metadata Leakage from Encrypted
+// Files and Communication with PURBs". A padded length reveals at most
+// O(log log n) bits about the message length, at a cost of at most 12% of
+// overhead.
+func NewPadmePadding() (p godium.Padding) {
+	p = bucketPadding{bucket: padme}
+	return
+}
+
+// NewPowerOfTwoPadding creates a godium.Padding that pads messages to the next
+// power of two, with a minimum of minBytes. A padded length reveals at most
+// O(log log n) bits about the message length, at a cost of up to 100% of
+// overhead.
+func NewPowerOfTwoPadding(minBytes int) (p godium.Padding) {
+	p = bucketPadding{bucket: func(n int) int {
+		if n <= minBytes {
+			return minBytes
+		}
+		return 1 << uint(bits.Len(uint(n-1)))
+	}}
+	return
+}
+
+func (p bucketPadding) Pad(buf []byte) (padded []byte) {
+	padded = padTo(buf, p.PaddedBytes(len(buf)))
+	return
+}
+
+func (p bucketPadding) Unpad(padded []byte) (buf []byte, err error) {
+	buf, err = Unpad(padded, len(padded))
+	if err != nil {
+		return
+	}
+
+	if p.PaddedBytes(len(buf)) != len(padded) {
+		buf, err = nil, godium.ErrInvalidPadding
+	}
+	return
+}
+
+func (p bucketPadding) PaddedBytes(n int) (c int) {
+	c = p.bucket(n + 1)
+	return
+}
+
+// padme returns the smallest length allowed by PADMÉ that is at least n.
+func padme(n int) (c int) {
+	if n < 2 {
+		c = n
+		return
+	}
+
+	e := bits.Len(uint(n)) - 1
+	s := bits.Len(uint(e))
+	mask := 1<<uint(e-s) - 1
+
+	c = (n + mask) &^ mask
+	return
+}
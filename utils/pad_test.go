@@ -0,0 +1,106 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package utils
+
+import (
+	"bytes"
+	"testing"
+
+	"go.artemisc.eu/godium"
+)
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		in        []byte
+		blockSize int
+		expect    []byte
+	}{
+		{[]byte{}, 4, []byte{0x80, 0x00, 0x00, 0x00}},
+		{[]byte{0x01}, 4, []byte{0x01, 0x80, 0x00, 0x00}},
+		{[]byte{0x01, 0x02, 0x03}, 4, []byte{0x01, 0x02, 0x03, 0x80}},
+		{[]byte{0x01, 0x02, 0x03, 0x04}, 4, []byte{0x01, 0x02, 0x03, 0x04, 0x80, 0x00, 0x00, 0x00}},
+		{[]byte{0x80}, 1, []byte{0x80, 0x80}},
+	}
+
+	for _, test := range tests {
+		got := Pad(append([]byte{}, test.in...), test.blockSize)
+		if !bytes.Equal(got, test.expect) {
+			t.Error("expected result did not match computed", test.expect, got)
+		}
+
+		buf, err := Unpad(got, test.blockSize)
+		if err != nil || !bytes.Equal(buf, test.in) {
+			t.Error("expected result did not match computed", test.in, buf, err)
+		}
+	}
+}
+
+func TestUnpadInvalid(t *testing.T) {
+	tests := []struct {
+		in        []byte
+		blockSize int
+	}{
+		{[]byte{}, 4},
+		{[]byte{0x80, 0x00}, 4},
+		{[]byte{0x00, 0x00, 0x00, 0x00}, 4},
+		{[]byte{0x01, 0x80, 0x00, 0x01}, 4},
+		{[]byte{0x80, 0x00, 0x00, 0x00, 0x00}, 4},
+		{[]byte{0x80}, 0},
+	}
+
+	for _, test := range tests {
+		_, err := Unpad(test.in, test.blockSize)
+		if err != godium.ErrInvalidPadding {
+			t.Error("invalid padding was accepted", test.in, test.blockSize)
+		}
+	}
+}
+
+func TestPadme(t *testing.T) {
+	tests := []struct {
+		n, expect int
+	}{
+		{1, 1}, {2, 2}, {9, 10}, {100, 104}, {1000, 1024}, {1025, 1088},
+		{65535, 65536}, {1000000, 1015808},
+	}
+
+	for _, test := range tests {
+		got := padme(test.n)
+		if got != test.expect {
+			t.Error("expected result did not match computed", test.expect, got)
+		}
+	}
+}
+
+func TestPaddingSchemes(t *testing.T) {
+	schemes := []godium.Padding{
+		NewBlockPadding(16),
+		NewPadmePadding(),
+		NewPowerOfTwoPadding(32),
+	}
+
+	for _, p := range schemes {
+		for n := 0; n < 300; n++ {
+			msg := bytes.Repeat([]byte{0x80}, n)
+
+			padded := p.Pad(append([]byte{}, msg...))
+			if len(padded) != p.PaddedBytes(n) || len(padded) <= n {
+				t.Error("padded length did not match PaddedBytes", n, len(padded))
+			}
+
+			buf, err := p.Unpad(padded)
+			if err != nil || !bytes.Equal(buf, msg) {
+				t.Error("expected result did not match computed", msg, buf, err)
+			}
+		}
+	}
+
+	p := NewPowerOfTwoPadding(32)
+	if _, err := p.Unpad(Pad(make([]byte, 40), 8)); err != godium.ErrInvalidPadding {
+		t.Error("padding to a length outside the buckets was accepted")
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package utils
+
+import (
+	"unsafe"
+
+	"go.artemisc.eu/godium"
+)
+
+// checkLen panics if a and b do not have the same length.
+func checkLen(a, b []byte) {
+	if len(a) != len(b) {
+		panic("utils: length mismatch")
+	}
+}
+
+// MemCmp compares a and b in constant time, like sodium_memcmp. It returns 0
+// if they are equal, and -1 otherwise. Only the length of the slices is
+// leaked, slices of different length are never equal.
+func MemCmp(a, b []byte) (r int) {
+	var d uint32
+
+	if len(a) != len(b) {
+		r = -1
+		return
+	}
+
+	for i := range a {
+		d |= uint32(a[i] ^ b[i])
+	}
+
+	r = int(1&((d-1)>>8)) - 1
+	return
+}
+
+// Equal reports whether a and b are equal, in constant time.
+func Equal(a, b []byte) (equal bool) {
+	equal = MemCmp(a, b) == 0
+	return
+}
+
+// Compare compares the little endian numbers a and b in constant time, like
+// sodium_compare. It returns -1 if a < b, 0 if a == b and 1 if a > b.
+func Compare(a, b []byte) (r int) {
+	var gt, eq uint32 = 0, 1
+
+	checkLen(a, b)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		x1, x2 := uint32(a[i]), uint32(b[i])
+		gt |= ((x2 - x1) >> 8) & eq
+		eq &= ((x2 ^ x1) - 1) >> 8
+	}
+
+	r = int(gt+gt+eq) - 1
+	return
+}
+
+// IsZero reports whether all bytes in n are 0, in constant time, like
+// sodium_is_zero.
+func IsZero(n []byte) (zero bool) {
+	var d uint32
+
+	for i := range n {
+		d |= uint32(n[i])
+	}
+
+	zero = 1&((d-1)>>8) == 1
+	return
+}
+
+// Increment adds 1 to the little endian number n, in constant time, like
+// sodium_increment. The result wraps around to 0 on overflow.
+func Increment(n []byte) {
+	var c uint32 = 1
+
+	for i := range n {
+		c += uint32(n[i])
+		n[i] = byte(c)
+		c >>= 8
+	}
+}
+
+// Add computes a = (a + b) mod 2^(8*len(a)) for the little endian numbers a
+// and b, in constant time, like sodium_add.
+func Add(a, b []byte) {
+	var c uint32
+
+	checkLen(a, b)
+
+	for i := range a {
+		c += uint32(a[i]) + uint32(b[i])
+		a[i] = byte(c)
+		c >>= 8
+	}
+}
+
+// Sub computes a = (a - b) mod 2^(8*len(a)) for the little endian numbers a
+// and b, in constant time, like sodium_sub.
+func Sub(a, b []byte) {
+	var c uint32
+
+	checkLen(a, b)
+
+	for i := range a {
+		c = uint32(a[i]) - uint32(b[i]) - c
+		a[i] = byte(c)
+		c = (c >> 8) & 1
+	}
+}
+
+// Select sets dst to x if v is 1, and to y if v is 0, in constant time. The
+// behavior is undefined if v takes any other value.
+func Select(v int, dst, x, y []byte) {
+	mask := byte(-v)
+
+	checkLen(x, y)
+	checkLen(dst, x)
+
+	for i := range dst {
+		dst[i] = y[i] ^ (mask & (x[i] ^ y[i]))
+	}
+}
+
+// Swap exchanges the contents of a and b if v is 1, and leaves them untouched
+// if v is 0, in constant time. The behavior is undefined if v takes any other
+// value.
+func Swap(v int, a, b []byte) {
+	mask := byte(-v)
+
+	checkLen(a, b)
+
+	for i := range a {
+		t := mask & (a[i] ^ b[i])
+		a[i] ^= t
+		b[i] ^= t
+	}
+}
+
+// Move copies src into dst and wipes the bytes of src that were not
+// overwritten by the copy, so the secret only remains in dst. It returns the
+// number of bytes copied, which is the minimum of len(src) and len(dst). Like
+// copy, dst and src may overlap.
+func Move(dst, src []byte) (n int) {
+	n = copy(dst, src)
+	if n == 0 {
+		godium.Wipe(src)
+		return
+	}
+
+	s := uintptr(unsafe.Pointer(&src[0]))
+	d := uintptr(unsafe.Pointer(&dst[0]))
+
+	for i := range src {
+		p := s + uintptr(i)
+		if p < d || p >= d+uintptr(n) {
+			src[i] = 0
+		}
+	}
+	return
+}
@@ -0,0 +1,150 @@
+// Copyright 2017, Project ArteMisc
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package utils
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMemCmp(t *testing.T) {
+	if MemCmp([]byte{1, 2, 3}, []byte{1, 2, 3}) != 0 {
+		t.Error("equal slices compared unequal")
+	}
+	if MemCmp([]byte{1, 2, 3}, []byte{1, 2, 4}) != -1 {
+		t.Error("unequal slices compared equal")
+	}
+	if MemCmp([]byte{1, 2, 3}, []byte{1, 2}) != -1 {
+		t.Error("slices of different length compared equal")
+	}
+	if MemCmp(nil, []byte{}) != 0 {
+		t.Error("empty slices compared unequal")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b   []byte
+		expect int
+	}{
+		{[]byte{0x00, 0x00}, []byte{0x00, 0x00}, 0},
+		{[]byte{0x01, 0x00}, []byte{0x00, 0x00}, 1},
+		{[]byte{0x00, 0x00}, []byte{0x01, 0x00}, -1},
+		{[]byte{0xff, 0x00}, []byte{0x00, 0x01}, -1},
+		{[]byte{0x00, 0x01}, []byte{0xff, 0x00}, 1},
+		{[]byte{0x12, 0x34, 0x56}, []byte{0x12, 0x34, 0x56}, 0},
+		{[]byte{}, []byte{}, 0},
+	}
+
+	for _, test := range tests {
+		got := Compare(test.a, test.b)
+		if got != test.expect {
+			t.Error("expected result did not match computed", test.expect, got)
+		}
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	if !IsZero(make([]byte, 32)) || !IsZero(nil) {
+		t.Error("zero buffer reported as non-zero")
+	}
+
+	for i := 0; i < 32; i++ {
+		buf := make([]byte, 32)
+		buf[i] = 0x80
+		if IsZero(buf) {
+			t.Error("non-zero buffer reported as zero", i)
+		}
+	}
+}
+
+func TestIncrement(t *testing.T) {
+	tests := []struct {
+		in, expect []byte
+	}{
+		{[]byte{0x00, 0x00, 0x00}, []byte{0x01, 0x00, 0x00}},
+		{[]byte{0xff, 0x00, 0x00}, []byte{0x00, 0x01, 0x00}},
+		{[]byte{0xff, 0xff, 0x00}, []byte{0x00, 0x00, 0x01}},
+		{[]byte{0xff, 0xff, 0xff}, []byte{0x00, 0x00, 0x00}},
+	}
+
+	for _, test := range tests {
+		Increment(test.in)
+		if !bytes.Equal(test.in, test.expect) {
+			t.Error("expected result did not match computed", test.expect, test.in)
+		}
+	}
+}
+
+func TestAddSub(t *testing.T) {
+	a := []byte{0xfe, 0xff, 0x01, 0x00}
+	b := []byte{0x03, 0x00, 0xff, 0xff}
+	sum := []byte{0x01, 0x00, 0x01, 0x00}
+
+	c := append([]byte{}, a...)
+	Add(c, b)
+	if !bytes.Equal(c, sum) {
+		t.Error("expected result did not match computed", sum, c)
+	}
+
+	Sub(c, b)
+	if !bytes.Equal(c, a) {
+		t.Error("expected result did not match computed", a, c)
+	}
+
+	zero := make([]byte, len(a))
+	Sub(zero, []byte{0x01, 0x00, 0x00, 0x00})
+	if !bytes.Equal(zero, []byte{0xff, 0xff, 0xff, 0xff}) {
+		t.Error("subtraction did not wrap around", zero)
+	}
+}
+
+func TestSelectSwap(t *testing.T) {
+	x := []byte{1, 2, 3, 4}
+	y := []byte{5, 6, 7, 8}
+	dst := make([]byte, 4)
+
+	Select(1, dst, x, y)
+	if !bytes.Equal(dst, x) {
+		t.Error("expected result did not match computed", x, dst)
+	}
+	Select(0, dst, x, y)
+	if !bytes.Equal(dst, y) {
+		t.Error("expected result did not match computed", y, dst)
+	}
+
+	Swap(0, x, y)
+	if !bytes.Equal(x, []byte{1, 2, 3, 4}) || !bytes.Equal(y, []byte{5, 6, 7, 8}) {
+		t.Error("buffers swapped for v = 0", x, y)
+	}
+	Swap(1, x, y)
+	if !bytes.Equal(x, []byte{5, 6, 7, 8}) || !bytes.Equal(y, []byte{1, 2, 3, 4}) {
+		t.Error("buffers not swapped for v = 1", x, y)
+	}
+}
+
+func TestMove(t *testing.T) {
+	src := []byte{1, 2, 3, 4}
+	dst := make([]byte, 3)
+
+	n := Move(dst, src)
+	if n != 3 || !bytes.Equal(dst, []byte{1, 2, 3}) || !IsZero(src) {
+		t.Error("expected result did not match computed", n, dst, src)
+	}
+
+	buf := []byte{1, 2, 3, 4, 5, 6}
+	n = Move(buf[:4], buf[2:])
+	if n != 4 || !bytes.Equal(buf, []byte{3, 4, 5, 6, 0, 0}) {
+		t.Error("expected result did not match computed", n, buf)
+	}
+
+	buf = []byte{1, 2, 3, 4, 5, 6}
+	n = Move(buf[2:], buf[:4])
+	if n != 4 || !bytes.Equal(buf, []byte{0, 0, 1, 2, 3, 4}) {
+		t.Error("expected result did not match computed", n, buf)
+	}
+}